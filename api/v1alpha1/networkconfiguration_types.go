@@ -22,9 +22,8 @@ import (
 
 // NetworkClusterPolicySpec defines the desired state of NetworkClusterPolicy
 type NetworkClusterPolicySpec struct {
-	// Configuration type that the operator will configure to the nodes. Possible options: gaudi-so.
-	// TODO: plausible other options: host-nic
-	// +kubebuilder:validation:Enum=gaudi-so
+	// Configuration type that the operator will configure to the nodes. Possible options: gaudi-so and host-nic.
+	// +kubebuilder:validation:Enum=gaudi-so;host-nic
 	ConfigurationType string `json:"configurationType"`
 
 	// Select which nodes the operator should target. Align with labels created by NFD.
@@ -35,18 +34,54 @@ type NetworkClusterPolicySpec struct {
 	// Gaudi Scale-Out specific settings. Only valid when configuration type is 'gaudi-so'
 	GaudiScaleOut GaudiScaleOutSpec `json:"gaudiScaleOut,omitempty"`
 
+	// Host NIC specific settings. Only valid when configuration type is 'host-nic'
+	HostNIC HostNICSpec `json:"hostNIC,omitempty"`
+
 	// LogLevel sets the operator's log level.
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=8
 	LogLevel int `json:"logLevel,omitempty"`
 }
 
-// GaudiScaleOutSpec defines the desired state of GaudiScaleOut
-type GaudiScaleOutSpec struct {
-	// Disable Gaudi scale-out interfaces in NetworkManager. For nodes where NetworkManager tries
-	// to configure the Gaudi interfaces, prevent it from doing so.
+// AddressPool defines the network the point-to-point networks of the
+// interfaces are allocated from
+type AddressPool struct {
+	// Network in CIDR notation the addresses are allocated from, e.g. "10.210.0.0/16".
+	// +kubebuilder:validation:Required
+	CIDR string `json:"cidr"`
+
+	// Prefix length of the point-to-point network allocated for each interface.
+	// The switch port uses the first address of a /30 or /126 network and the
+	// interface the second one. In a /127 network the switch port uses the
+	// network address. Defaults to 30 for IPv4 and 127 for IPv6.
+	// +optional
+	PrefixLength int `json:"prefixLength,omitempty"`
+}
+
+// LinkDiscoverySpec holds the settings shared by the configuration types
+// that configure the interfaces with the link discovery DaemonSet
+type LinkDiscoverySpec struct {
+	// Disable the interfaces in NetworkManager. For nodes where NetworkManager tries
+	// to configure the interfaces, prevent it from doing so.
 	DisableNetworkManager bool `json:"disableNetworkManager,omitempty"`
 
+	// Keep the interfaces disabled in NetworkManager when it restarts or the node
+	// reboots with a NetworkManager configuration file. The interfaces are matched
+	// by their name or MAC address. Possible options: interface-name and mac.
+	// Requires disableNetworkManager.
+	// +kubebuilder:validation:Enum=interface-name;mac
+	// +optional
+	NetworkManagerConfig string `json:"networkManagerConfig,omitempty"`
+
+	// Backend applying the L3 configuration. With netlink the addresses and
+	// routes are set directly on the interfaces. With networkmanager they are
+	// applied with NetworkManager connection profiles so that NetworkManager
+	// keeps them. Possible options: netlink and networkmanager.
+	// Cannot be used with disableNetworkManager.
+	// +kubebuilder:validation:Enum=netlink;networkmanager
+	// +optional
+	Backend string `json:"backend,omitempty"`
+
 	// Layer where the configuration should occur. Possible options: L2 and L3.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Enum=L2;L3
@@ -59,18 +94,122 @@ type GaudiScaleOutSpec struct {
 	// +kubebuilder:validation:Enum=Never;Always;IfNotPresent
 	PullPolicy string `json:"pullPolicy,omitempty"`
 
-	// MTU for the scale-out interfaces.
+	// MTU for the interfaces.
 	// +kubebuilder:validation:Minimum=1500
 	// +kubebuilder:validation:Maximum=9000
 	MTU int `json:"mtu,omitempty"`
+
+	// Routed networks reachable via the LLDP peer. Networks are given either
+	// in CIDR notation, e.g. "10.192.0.0/12", or as a prefix length that is applied
+	// to the interface address, e.g. "/20". Defaults to "/16" for IPv4 and "/64" for IPv6.
+	// Only valid when layer is 'L3'.
+	RoutedNetworks []string `json:"routedNetworks,omitempty"`
+
+	// Parser for the switch port address received via LLDP. Possible options:
+	// port-description (address as the second token of the port description),
+	// port-description-last (address as the last token of the port description),
+	// key-value (e.g. "ip=10.1.2.1/30" in the port description),
+	// org-tlv (address in an organizationally specific TLV) and
+	// mgmt-address (LLDP management address). Only valid when layer is 'L3'.
+	// +kubebuilder:validation:Enum=port-description;port-description-last;key-value;org-tlv;mgmt-address
+	LLDPAddressParser string `json:"lldpAddressParser,omitempty"`
+
+	// Use the LLDP management address of the switch when the parser finds no address.
+	// The management address must be a host address of a point-to-point network that
+	// is not routed via another interface. Only valid when layer is 'L3'.
+	LLDPMgmtAddressFallback bool `json:"lldpMgmtAddressFallback,omitempty"`
+
+	// Port for the Prometheus metrics endpoint of the configuration Pods on the
+	// worker nodes, served over HTTPS on the node's address to clients
+	// authorized to get /metrics. Metrics are disabled when unset. The Pods use
+	// the host network, policies targeting the same nodes need different ports.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+
+	// Select which of the interfaces to configure, e.g. to leave port 0 for management.
+	InterfaceSelection InterfaceSelection `json:"interfaceSelection,omitempty"`
+}
+
+// GaudiScaleOutSpec defines the desired state of GaudiScaleOut
+type GaudiScaleOutSpec struct {
+	LinkDiscoverySpec `json:",inline"`
+
+	// Allocate the interface addresses from an address pool instead of reading
+	// them from LLDP, for fabrics where the switches don't advertise the addresses.
+	// The allocations are kept in the NetworkNodeState of each node.
+	// Only valid when layer is 'L3'.
+	// +optional
+	AddressPool *AddressPool `json:"addressPool,omitempty"`
+}
+
+// HostNICSpec defines the desired state of host NICs used for scale-out, e.g. RoCE capable NICs
+type HostNICSpec struct {
+	// PCI driver of the NICs to configure, e.g. "ice". Either the driver or the PCI IDs need to be set.
+	// When both are set, the NICs need to match both.
+	Driver string `json:"driver,omitempty"`
+
+	// PCI IDs of the NICs to configure in "vendor:device" notation, e.g. "8086:1593".
+	PCIIDs []string `json:"pciIDs,omitempty"`
+
+	LinkDiscoverySpec `json:",inline"`
+}
+
+// InterfaceFilter matches interfaces by their driver, PCI address, name and port index.
+// An interface matches when it matches all the given criteria and any of the values
+// given for each criterion.
+type InterfaceFilter struct {
+	// Names of the drivers the PCI devices are bound to, e.g. "habanalabs".
+	Drivers []string `json:"drivers,omitempty"`
+
+	// PCI addresses of the devices as glob patterns, e.g. "0000:b3:00.*".
+	PCIAddresses []string `json:"pciAddresses,omitempty"`
+
+	// Regular expressions for the interface names, e.g. "^ens1f[0-3]$".
+	Names []string `json:"names,omitempty"`
+
+	// Port indexes of the interfaces on their PCI device, either single indexes
+	// or ranges, e.g. "0" or "1-23".
+	Ports []string `json:"ports,omitempty"`
 }
 
+// InterfaceSelection selects the interfaces to configure among the interfaces of the
+// devices. Without filters all the interfaces are configured.
+type InterfaceSelection struct {
+	// Only configure the interfaces matching the filter.
+	Include InterfaceFilter `json:"include,omitempty"`
+
+	// Do not configure the interfaces matching the filter.
+	Exclude InterfaceFilter `json:"exclude,omitempty"`
+}
+
+const (
+	// ConditionAvailable is true when the configuration Pods are ready and
+	// have configured all targeted nodes.
+	ConditionAvailable = "Available"
+	// ConditionProgressing is true while the configuration DaemonSet is rolled out.
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded is true when nodes report errors or unreachable LLDP peers.
+	ConditionDegraded = "Degraded"
+)
+
 // NetworkClusterPolicyStatus defines the observed state of NetworkClusterPolicy
 type NetworkClusterPolicyStatus struct {
 	Targets    int32    `json:"targets"`
 	ReadyNodes int32    `json:"ready"`
 	State      string   `json:"state"`
 	Errors     []string `json:"errors"`
+	// Interfaces whose LLDP peer is not reachable, in "node/interface" notation.
+	UnreachableInterfaces []string `json:"unreachableInterfaces,omitempty"`
+
+	// Generation of the policy that was last processed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions of the policy. Known condition types are Available, Progressing and Degraded.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
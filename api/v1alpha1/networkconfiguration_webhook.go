@@ -15,7 +15,10 @@
 package v1alpha1
 
 import (
+	"net"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -30,6 +33,12 @@ var netpolicylog = logf.Log.WithName("nicclusterpolicy-resource")
 
 const (
 	gaudiScaleOut = "gaudi-so"
+	hostNIC       = "host-nic"
+
+	networkManagerBackend = "networkmanager"
+
+	defaultLinkDiscoveryImage = "intel/intel-network-linkdiscovery:latest"
+	defaultPullPolicy         = "IfNotPresent"
 )
 
 type emptyNodeSelectorError struct{}
@@ -44,6 +53,62 @@ func (e invalidNodeSelector) Error() string {
 	return "invalid node selector"
 }
 
+type invalidRoutedNetworkError struct {
+	network string
+}
+
+func (e invalidRoutedNetworkError) Error() string {
+	return "invalid routed network '" + e.network + "'"
+}
+
+type invalidAddressPoolError struct {
+	pool string
+}
+
+func (e invalidAddressPoolError) Error() string {
+	return "invalid address pool '" + e.pool + "'"
+}
+
+type addressPoolNotSupportedError struct{}
+
+func (e addressPoolNotSupportedError) Error() string {
+	return "addressPool is only supported with the gaudi-so configuration type"
+}
+
+type noDeviceSelectorError struct{}
+
+func (e noDeviceSelectorError) Error() string {
+	return "driver or PCI IDs are required"
+}
+
+type invalidPCIIDError struct {
+	id string
+}
+
+func (e invalidPCIIDError) Error() string {
+	return "invalid PCI ID '" + e.id + "'"
+}
+
+type invalidInterfaceFilterError struct {
+	value string
+}
+
+func (e invalidInterfaceFilterError) Error() string {
+	return "invalid interface filter '" + e.value + "'"
+}
+
+type networkManagerConfigError struct{}
+
+func (e networkManagerConfigError) Error() string {
+	return "networkManagerConfig requires disableNetworkManager"
+}
+
+type networkManagerBackendError struct{}
+
+func (e networkManagerBackendError) Error() string {
+	return "networkmanager backend cannot be used with disableNetworkManager"
+}
+
 type unknownConfigurationError struct{}
 
 func (e unknownConfigurationError) Error() string {
@@ -67,9 +132,19 @@ func (r *NetworkClusterPolicy) Default() {
 
 	switch r.Spec.ConfigurationType {
 	case gaudiScaleOut:
-		if len(r.Spec.GaudiScaleOut.Image) == 0 {
-			r.Spec.GaudiScaleOut.Image = "intel/intel-network-linkdiscovery:latest"
-		}
+		defaultLinkDiscoverySpec(&r.Spec.GaudiScaleOut.LinkDiscoverySpec)
+	case hostNIC:
+		defaultLinkDiscoverySpec(&r.Spec.HostNIC.LinkDiscoverySpec)
+	}
+}
+
+func defaultLinkDiscoverySpec(s *LinkDiscoverySpec) {
+	if len(s.Image) == 0 {
+		s.Image = defaultLinkDiscoveryImage
+	}
+
+	if len(s.PullPolicy) == 0 {
+		s.PullPolicy = defaultPullPolicy
 	}
 }
 
@@ -83,11 +158,137 @@ var _ webhook.Validator = &NetworkClusterPolicy{}
 var labelHostRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_\.]*)?[A-Za-z0-9]$`)
 var labelPathRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-\._\/]*)?[A-Za-z0-9]$`)
 var labelValueRegex = regexp.MustCompile(`^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?$`)
+var pciIDRegex = regexp.MustCompile(`^[0-9A-Fa-f]{4}:[0-9A-Fa-f]{4}$`)
+var portRangeRegex = regexp.MustCompile(`^([0-9]+)(-([0-9]+))?$`)
+
+func validateRoutedNetwork(network string) error {
+	if !strings.Contains(strings.TrimPrefix(network, "/"), "/") {
+		prefixLen, err := strconv.Atoi(strings.TrimPrefix(network, "/"))
+		if err != nil || prefixLen < 1 || prefixLen > 128 {
+			return invalidRoutedNetworkError{network: network}
+		}
+
+		return nil
+	}
+
+	if _, _, err := net.ParseCIDR(network); err != nil {
+		return invalidRoutedNetworkError{network: network}
+	}
 
-func validateGaudiSoSpec(s GaudiScaleOutSpec) error {
 	return nil
 }
 
+func validateAddressPool(pool *AddressPool) error {
+	_, network, err := net.ParseCIDR(pool.CIDR)
+	if err != nil {
+		return invalidAddressPoolError{pool: pool.CIDR}
+	}
+
+	poolLen, bits := network.Mask.Size()
+
+	prefixLen := pool.PrefixLength
+
+	switch {
+	case prefixLen == 0 && bits == 32:
+		prefixLen = 30
+	case prefixLen == 0:
+		prefixLen = 127
+	case bits == 32 && prefixLen != 30,
+		bits == 128 && prefixLen != 126 && prefixLen != 127:
+		return invalidAddressPoolError{pool: pool.CIDR}
+	}
+
+	if poolLen > prefixLen {
+		return invalidAddressPoolError{pool: pool.CIDR}
+	}
+
+	return nil
+}
+
+func validateInterfaceFilter(f InterfaceFilter) error {
+	for _, pattern := range f.PCIAddresses {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return invalidInterfaceFilterError{value: pattern}
+		}
+	}
+
+	for _, name := range f.Names {
+		if _, err := regexp.Compile(name); err != nil {
+			return invalidInterfaceFilterError{value: name}
+		}
+	}
+
+	for _, port := range f.Ports {
+		m := portRangeRegex.FindStringSubmatch(port)
+		if m == nil {
+			return invalidInterfaceFilterError{value: port}
+		}
+
+		if m[3] != "" {
+			first, _ := strconv.Atoi(m[1])
+			last, _ := strconv.Atoi(m[3])
+
+			if last < first {
+				return invalidInterfaceFilterError{value: port}
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateInterfaceSelection(s InterfaceSelection) error {
+	if err := validateInterfaceFilter(s.Include); err != nil {
+		return err
+	}
+
+	return validateInterfaceFilter(s.Exclude)
+}
+
+// validateLinkDiscoverySpec validates the settings shared by the
+// configuration types.
+func validateLinkDiscoverySpec(s LinkDiscoverySpec) error {
+	if s.NetworkManagerConfig != "" && !s.DisableNetworkManager {
+		return networkManagerConfigError{}
+	}
+
+	if s.Backend == networkManagerBackend && s.DisableNetworkManager {
+		return networkManagerBackendError{}
+	}
+
+	for _, network := range s.RoutedNetworks {
+		if err := validateRoutedNetwork(network); err != nil {
+			return err
+		}
+	}
+
+	return validateInterfaceSelection(s.InterfaceSelection)
+}
+
+func validateGaudiSoSpec(s GaudiScaleOutSpec) error {
+	if s.AddressPool != nil {
+		if err := validateAddressPool(s.AddressPool); err != nil {
+			return err
+		}
+	}
+
+	return validateLinkDiscoverySpec(s.LinkDiscoverySpec)
+}
+
+func validateHostNICSpec(s HostNICSpec) error {
+	if len(s.Driver) == 0 && len(s.PCIIDs) == 0 {
+		return noDeviceSelectorError{}
+	}
+
+	for _, id := range s.PCIIDs {
+		if !pciIDRegex.MatchString(id) {
+			return invalidPCIIDError{id: id}
+		}
+	}
+
+	return validateLinkDiscoverySpec(s.LinkDiscoverySpec)
+}
+
 func validateNodeSelector(nodeSelector map[string]string) error {
 	if len(nodeSelector) == 0 {
 		return emptyNodeSelectorError{}
@@ -123,9 +324,15 @@ func validateSpec(s NetworkClusterPolicySpec) (admission.Warnings, error) {
 		return nil, err
 	}
 
+	if s.ConfigurationType != gaudiScaleOut && s.GaudiScaleOut.AddressPool != nil {
+		return nil, addressPoolNotSupportedError{}
+	}
+
 	switch s.ConfigurationType {
 	case gaudiScaleOut:
 		return nil, validateGaudiSoSpec(s.GaudiScaleOut)
+	case hostNIC:
+		return nil, validateHostNICSpec(s.HostNIC)
 	default:
 		return nil, unknownConfigurationError{}
 	}
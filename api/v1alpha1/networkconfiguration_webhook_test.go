@@ -32,6 +32,30 @@ var _ = Describe("NetworkClusterPolicy Webhook", func() {
 			nc.Default()
 
 			Expect(nc.Spec.GaudiScaleOut.Image).To(BeEquivalentTo("intel/intel-network-linkdiscovery:latest"))
+			Expect(nc.Spec.GaudiScaleOut.PullPolicy).To(BeEquivalentTo("IfNotPresent"))
+		})
+
+		It("Should fill in the default image for host NICs", func() {
+			nc := NetworkClusterPolicy{}
+
+			nc.Spec.ConfigurationType = hostNIC
+			nc.Spec.HostNIC.Layer = "L3"
+
+			nc.Default()
+
+			Expect(nc.Spec.HostNIC.Image).To(BeEquivalentTo("intel/intel-network-linkdiscovery:latest"))
+			Expect(nc.Spec.GaudiScaleOut.Image).To(BeEmpty())
+		})
+
+		It("Should keep the given pull policy", func() {
+			nc := NetworkClusterPolicy{}
+
+			nc.Spec.ConfigurationType = hostNIC
+			nc.Spec.HostNIC.PullPolicy = "Always"
+
+			nc.Default()
+
+			Expect(nc.Spec.HostNIC.PullPolicy).To(BeEquivalentTo("Always"))
 		})
 	})
 
@@ -60,7 +84,9 @@ var _ = Describe("NetworkClusterPolicy Webhook", func() {
 				Spec: NetworkClusterPolicySpec{
 					ConfigurationType: gaudiScaleOut,
 					GaudiScaleOut: GaudiScaleOutSpec{
-						Layer: "L3BGP",
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer: "L3BGP",
+						},
 					},
 					NodeSelector: map[string]string{},
 				},
@@ -83,7 +109,9 @@ var _ = Describe("NetworkClusterPolicy Webhook", func() {
 				Spec: NetworkClusterPolicySpec{
 					ConfigurationType: gaudiScaleOut,
 					GaudiScaleOut: GaudiScaleOutSpec{
-						Layer: "L3",
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer: "L3",
+						},
 					},
 					NodeSelector: map[string]string{
 						"foobar.com?foo": "bar",
@@ -117,7 +145,9 @@ var _ = Describe("NetworkClusterPolicy Webhook", func() {
 				Spec: NetworkClusterPolicySpec{
 					ConfigurationType: gaudiScaleOut,
 					GaudiScaleOut: GaudiScaleOutSpec{
-						Layer: "L3",
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer: "L3",
+						},
 					},
 					NodeSelector: map[string]string{
 						"foo": "bar",
@@ -135,12 +165,263 @@ var _ = Describe("NetworkClusterPolicy Webhook", func() {
 			Expect(nc2.ValidateUpdate(&nc)).Error().NotTo(BeNil())
 		})
 
+		It("Should validate routed networks InputVal", func() {
+			nc := NetworkClusterPolicy{
+				Spec: NetworkClusterPolicySpec{
+					ConfigurationType: gaudiScaleOut,
+					GaudiScaleOut: GaudiScaleOutSpec{
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer: "L3",
+						},
+					},
+					NodeSelector: map[string]string{
+						"foo": "bar",
+					},
+				},
+			}
+
+			goodValues := [][]string{
+				{"10.192.0.0/12"},
+				{"/12", "/20"},
+				{"16"},
+				{"fd00:10::/48", "/64"},
+			}
+
+			for _, v := range goodValues {
+				nc.Spec.GaudiScaleOut.RoutedNetworks = v
+
+				Expect(nc.ValidateCreate()).Error().To(BeNil(), "routed networks: %+v", v)
+			}
+
+			badValues := [][]string{
+				{"10.192.0.0"},
+				{"/0"},
+				{"/129"},
+				{"10.192.0.0/33"},
+				{"/12", "foo"},
+			}
+
+			for _, v := range badValues {
+				nc.Spec.GaudiScaleOut.RoutedNetworks = v
+
+				Expect(nc.ValidateCreate()).Error().To(Not(BeNil()), "routed networks: %+v", v)
+			}
+		})
+
+		It("Should validate interface selection InputVal", func() {
+			nc := NetworkClusterPolicy{
+				Spec: NetworkClusterPolicySpec{
+					ConfigurationType: gaudiScaleOut,
+					GaudiScaleOut: GaudiScaleOutSpec{
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer: "L3",
+						},
+					},
+					NodeSelector: map[string]string{
+						"foo": "bar",
+					},
+				},
+			}
+
+			goodValues := []InterfaceFilter{
+				{Ports: []string{"0"}},
+				{Ports: []string{"1-23", "25"}},
+				{Drivers: []string{"habanalabs"}, PCIAddresses: []string{"0000:b3:00.*"}},
+				{Names: []string{"^ens1f[0-3]$"}},
+			}
+
+			for _, v := range goodValues {
+				nc.Spec.GaudiScaleOut.InterfaceSelection.Exclude = v
+
+				Expect(nc.ValidateCreate()).Error().To(BeNil(), "filter: %+v", v)
+			}
+
+			badValues := []InterfaceFilter{
+				{Ports: []string{"a"}},
+				{Ports: []string{"-1"}},
+				{Ports: []string{"23-1"}},
+				{PCIAddresses: []string{"0000:b3:00.["}},
+				{Names: []string{"ens1f(0"}},
+			}
+
+			for _, v := range badValues {
+				nc.Spec.GaudiScaleOut.InterfaceSelection.Exclude = InterfaceFilter{}
+				nc.Spec.GaudiScaleOut.InterfaceSelection.Include = v
+
+				Expect(nc.ValidateCreate()).Error().To(Not(BeNil()), "filter: %+v", v)
+			}
+		})
+
+		It("Should validate host NIC device selection InputVal", func() {
+			nc := NetworkClusterPolicy{
+				Spec: NetworkClusterPolicySpec{
+					ConfigurationType: hostNIC,
+					HostNIC: HostNICSpec{
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer: "L3",
+						},
+					},
+					NodeSelector: map[string]string{
+						"foo": "bar",
+					},
+				},
+			}
+
+			Expect(nc.ValidateCreate()).Error().To(BeEquivalentTo(noDeviceSelectorError{}))
+
+			nc.Spec.HostNIC.Driver = "ice"
+			Expect(nc.ValidateCreate()).Error().To(BeNil())
+
+			nc.Spec.HostNIC.Driver = ""
+			nc.Spec.HostNIC.PCIIDs = []string{"8086:1593", "8086:159b"}
+			Expect(nc.ValidateCreate()).Error().To(BeNil())
+
+			badValues := [][]string{
+				{"8086"},
+				{"8086:1593:1"},
+				{"0x8086:0x1593"},
+				{"8086:1593", "foo"},
+			}
+
+			for _, v := range badValues {
+				nc.Spec.HostNIC.PCIIDs = v
+
+				Expect(nc.ValidateCreate()).Error().To(Not(BeNil()), "PCI IDs: %+v", v)
+			}
+
+			nc.Spec.HostNIC.PCIIDs = []string{"8086:1593"}
+			nc.Spec.HostNIC.RoutedNetworks = []string{"/0"}
+			Expect(nc.ValidateCreate()).Error().To(Not(BeNil()))
+
+			nc.Spec.HostNIC.RoutedNetworks = nil
+			nc.Spec.HostNIC.InterfaceSelection.Exclude.Ports = []string{"x"}
+			Expect(nc.ValidateCreate()).Error().To(Not(BeNil()))
+		})
+
+		It("Should validate the address pool InputVal", func() {
+			nc := NetworkClusterPolicy{
+				Spec: NetworkClusterPolicySpec{
+					ConfigurationType: gaudiScaleOut,
+					GaudiScaleOut: GaudiScaleOutSpec{
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer: "L3",
+						},
+						AddressPool: &AddressPool{
+							CIDR: "10.210.0.0/16",
+						},
+					},
+					NodeSelector: map[string]string{
+						"foo": "bar",
+					},
+				},
+			}
+
+			Expect(nc.ValidateCreate()).Error().To(BeNil())
+
+			nc.Spec.GaudiScaleOut.AddressPool.PrefixLength = 30
+			Expect(nc.ValidateCreate()).Error().To(BeNil())
+
+			nc.Spec.GaudiScaleOut.AddressPool.PrefixLength = 29
+			Expect(nc.ValidateCreate()).Error().To(BeEquivalentTo(invalidAddressPoolError{pool: "10.210.0.0/16"}))
+
+			nc.Spec.GaudiScaleOut.AddressPool = &AddressPool{CIDR: "fd00:10::/64", PrefixLength: 126}
+			Expect(nc.ValidateCreate()).Error().To(BeNil())
+
+			nc.Spec.GaudiScaleOut.AddressPool.PrefixLength = 30
+			Expect(nc.ValidateCreate()).Error().To(Not(BeNil()))
+
+			nc.Spec.GaudiScaleOut.AddressPool = &AddressPool{CIDR: "10.210.0.1/31"}
+			Expect(nc.ValidateCreate()).Error().To(Not(BeNil()))
+
+			nc.Spec.GaudiScaleOut.AddressPool = &AddressPool{CIDR: "10.210.0.0"}
+			Expect(nc.ValidateCreate()).Error().To(Not(BeNil()))
+
+			nc.Spec.GaudiScaleOut.AddressPool = &AddressPool{CIDR: "10.210.0.0/16"}
+			nc.Spec.ConfigurationType = hostNIC
+			nc.Spec.HostNIC = HostNICSpec{Driver: "ice"}
+			Expect(nc.ValidateCreate()).Error().To(BeEquivalentTo(addressPoolNotSupportedError{}))
+		})
+
+		It("Should require disabling NetworkManager for its configuration InputVal", func() {
+			nc := NetworkClusterPolicy{
+				Spec: NetworkClusterPolicySpec{
+					ConfigurationType: gaudiScaleOut,
+					GaudiScaleOut: GaudiScaleOutSpec{
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer:                "L3",
+							NetworkManagerConfig: "mac",
+						},
+					},
+					NodeSelector: map[string]string{
+						"foo": "bar",
+					},
+				},
+			}
+
+			Expect(nc.ValidateCreate()).Error().To(BeEquivalentTo(networkManagerConfigError{}))
+
+			nc.Spec.GaudiScaleOut.DisableNetworkManager = true
+			Expect(nc.ValidateCreate()).Error().To(BeNil())
+
+			nc.Spec.ConfigurationType = hostNIC
+			nc.Spec.HostNIC = HostNICSpec{
+				Driver: "ice",
+				LinkDiscoverySpec: LinkDiscoverySpec{
+					Layer:                "L3",
+					NetworkManagerConfig: "interface-name",
+				},
+			}
+			Expect(nc.ValidateCreate()).Error().To(BeEquivalentTo(networkManagerConfigError{}))
+
+			nc.Spec.HostNIC.DisableNetworkManager = true
+			Expect(nc.ValidateCreate()).Error().To(BeNil())
+		})
+
+		It("Should not allow the NetworkManager backend with NetworkManager disabled InputVal", func() {
+			nc := NetworkClusterPolicy{
+				Spec: NetworkClusterPolicySpec{
+					ConfigurationType: gaudiScaleOut,
+					GaudiScaleOut: GaudiScaleOutSpec{
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer:                 "L3",
+							Backend:               "networkmanager",
+							DisableNetworkManager: true,
+						},
+					},
+					NodeSelector: map[string]string{
+						"foo": "bar",
+					},
+				},
+			}
+
+			Expect(nc.ValidateCreate()).Error().To(BeEquivalentTo(networkManagerBackendError{}))
+
+			nc.Spec.GaudiScaleOut.DisableNetworkManager = false
+			Expect(nc.ValidateCreate()).Error().To(BeNil())
+
+			nc.Spec.ConfigurationType = hostNIC
+			nc.Spec.HostNIC = HostNICSpec{
+				Driver: "ice",
+				LinkDiscoverySpec: LinkDiscoverySpec{
+					Layer:                 "L3",
+					Backend:               "networkmanager",
+					DisableNetworkManager: true,
+				},
+			}
+			Expect(nc.ValidateCreate()).Error().To(BeEquivalentTo(networkManagerBackendError{}))
+
+			nc.Spec.HostNIC.DisableNetworkManager = false
+			Expect(nc.ValidateCreate()).Error().To(BeNil())
+		})
+
 		It("Should always accept delete", func() {
 			nc := NetworkClusterPolicy{
 				Spec: NetworkClusterPolicySpec{
 					ConfigurationType: gaudiScaleOut,
 					GaudiScaleOut: GaudiScaleOutSpec{
-						Layer: "L3BGP",
+						LinkDiscoverySpec: LinkDiscoverySpec{
+							Layer: "L3BGP",
+						},
 					},
 					NodeSelector: map[string]string{
 						"foo": "bar",
@@ -0,0 +1,202 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// NodeStateConfigured is reported when all interfaces were configured.
+	NodeStateConfigured = "Configured"
+	// NodeStateFailed is reported when the configuration failed.
+	NodeStateFailed = "Failed"
+	// NodeStatePending is reported while the node waits for its interface addresses.
+	NodeStatePending = "Pending"
+	// NodeStateCleanedUp is reported when the configuration was removed from the node.
+	NodeStateCleanedUp = "CleanedUp"
+
+	// NodeStatePolicyLabel identifies the policy of a NetworkNodeState.
+	NodeStatePolicyLabel = "intel.com/policy"
+	// NodeStateNodeLabel identifies the node of a NetworkNodeState.
+	NodeStateNodeLabel = "intel.com/node"
+
+	nameHashLength = 10
+)
+
+// NetworkNodeStateSpec defines the node and policy the state belongs to
+type NetworkNodeStateSpec struct {
+	// Name of the node the state is reported for.
+	NodeName string `json:"nodeName"`
+
+	// Name of the NetworkClusterPolicy that configures the node.
+	Policy string `json:"policy"`
+
+	// Teardown is set when the policy is being deleted. The node removes the
+	// configuration done for the policy and reports the CleanedUp state.
+	// +optional
+	Teardown bool `json:"teardown,omitempty"`
+
+	// Addresses allocated for the interfaces of the node from the address
+	// pool of the policy.
+	// +optional
+	Addresses []AddressAllocation `json:"addresses,omitempty"`
+}
+
+// AddressAllocation holds the address allocated for an interface
+type AddressAllocation struct {
+	// Name of the interface.
+	Interface string `json:"interface"`
+	// Address of the interface in CIDR notation.
+	Address string `json:"address"`
+	// Gateway of the interface, the switch port on the point-to-point network.
+	Gateway string `json:"gateway"`
+}
+
+// LLDPPeerState holds the information received from the LLDP peer
+type LLDPPeerState struct {
+	SysName         string `json:"sysName,omitempty"`
+	PortDescription string `json:"portDescription,omitempty"`
+	MAC             string `json:"mac,omitempty"`
+	Address         string `json:"address,omitempty"`
+}
+
+// PeerReachability holds the result of the last reachability probe of the LLDP peer
+type PeerReachability struct {
+	// Peer resolved to the MAC address received via LLDP and answered to ICMP echo requests.
+	Reachable bool `json:"reachable"`
+	// Peer address resolved to the MAC address received via LLDP.
+	NeighborResolved bool `json:"neighborResolved"`
+	// Peer answered to ICMP echo requests.
+	EchoReply bool `json:"echoReply"`
+	// Reason why the peer is not reachable.
+	Message string `json:"message,omitempty"`
+	// Time of the last probe.
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+}
+
+// InterfaceState defines the observed state of a single network interface
+type InterfaceState struct {
+	Name string `json:"name"`
+	MAC  string `json:"mac,omitempty"`
+	// Link flags as reported by the kernel, e.g. "up|broadcast|multicast".
+	Flags string `json:"flags,omitempty"`
+	MTU   int    `json:"mtu,omitempty"`
+	// Address configured from LLDP in CIDR notation.
+	Address string `json:"address,omitempty"`
+	// Routes configured for the interface in "destination via gateway" notation.
+	Routes []string `json:"routes,omitempty"`
+	// Peer information received via LLDP.
+	LLDPPeer *LLDPPeerState `json:"lldpPeer,omitempty"`
+	// Reachability of the LLDP peer, not set if the peer is not probed.
+	Reachability *PeerReachability `json:"reachability,omitempty"`
+	// NetworkManager state of the interface, "unmanaged" if disabled by the operator.
+	NetworkManager string `json:"networkManager,omitempty"`
+	// Last error that occurred when configuring the interface.
+	Error string `json:"error,omitempty"`
+}
+
+// NetworkNodeStateStatus defines the observed state of NetworkNodeState
+type NetworkNodeStateStatus struct {
+	// Overall configuration state of the node. Possible values: Pending, Configured, Failed, CleanedUp.
+	State string `json:"state,omitempty"`
+
+	// Interfaces found and configured on the node.
+	Interfaces []InterfaceState `json:"interfaces,omitempty"`
+
+	// Last error that prevented the node from being configured.
+	LastError string `json:"lastError,omitempty"`
+
+	// Time of the last status update from the node.
+	LastUpdate metav1.Time `json:"lastUpdate,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+//+kubebuilder:printcolumn:name="Policy",type=string,JSONPath=`.spec.policy`
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+
+// NetworkNodeState is the Schema for the networknodestates API
+type NetworkNodeState struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkNodeStateSpec   `json:"spec,omitempty"`
+	Status NetworkNodeStateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NetworkNodeStateList contains a list of NetworkNodeState
+type NetworkNodeStateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkNodeState `json:"items"`
+}
+
+// hashedName appends a hash of the key to the name and shortens the name to
+// keep the result within maxLength characters.
+func hashedName(name, key string, maxLength int) string {
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])[:nameHashLength]
+
+	if limit := maxLength - nameHashLength - 1; len(name) > limit {
+		name = strings.TrimRight(name[:limit], "-.")
+	}
+
+	return name + "-" + hash
+}
+
+// NetworkNodeStateName returns the name of the NetworkNodeState object for
+// the given policy and node. The hash suffix tells apart e.g. policy "a-b"
+// on node "c" from policy "a" on node "b-c" and keeps shortened names unique.
+// The object should be looked up with NetworkNodeStateLabels.
+func NetworkNodeStateName(policy, node string) string {
+	// the slash is valid in neither name
+	return hashedName(policy+"-"+node, policy+"/"+node, validation.DNS1123SubdomainMaxLength)
+}
+
+// NetworkNodeStateLabels returns the labels of the NetworkNodeState object
+// for the given policy and node. Names too long for a label value are
+// shortened, the spec tells the objects apart.
+func NetworkNodeStateLabels(policy, node string) map[string]string {
+	labels := map[string]string{
+		NodeStatePolicyLabel: policy,
+		NodeStateNodeLabel:   node,
+	}
+
+	for key, value := range labels {
+		if len(value) > validation.LabelValueMaxLength {
+			labels[key] = hashedName(value, value, validation.LabelValueMaxLength)
+		}
+	}
+
+	return labels
+}
+
+// IsFor returns true when the state belongs to the given policy and node.
+func (s *NetworkNodeState) IsFor(policy, node string) bool {
+	return s.Spec.Policy == policy && s.Spec.NodeName == node
+}
+
+func init() {
+	SchemeBuilder.Register(&NetworkNodeState{}, &NetworkNodeStateList{})
+}
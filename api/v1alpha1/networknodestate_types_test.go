@@ -0,0 +1,50 @@
+// Copyright 2024 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var _ = Describe("NetworkNodeState names", func() {
+	It("should tell apart policy and node names with dashes", func() {
+		Expect(NetworkNodeStateName("a-b", "c")).NotTo(Equal(NetworkNodeStateName("a", "b-c")))
+		Expect(NetworkNodeStateName("a-b", "c")).To(HavePrefix("a-b-c-"))
+	})
+
+	It("should keep long names and labels valid", func() {
+		policy := strings.Repeat("p", 200)
+		node := strings.Repeat("n", 200)
+
+		name := NetworkNodeStateName(policy, node)
+		Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+		Expect(name).NotTo(Equal(NetworkNodeStateName(policy, node+"x")))
+
+		labels := NetworkNodeStateLabels(policy, node)
+		for _, value := range labels {
+			Expect(validation.IsValidLabelValue(value)).To(BeEmpty())
+		}
+		Expect(labels[NodeStateNodeLabel]).NotTo(Equal(NetworkNodeStateLabels(policy, node+"x")[NodeStateNodeLabel]))
+
+		Expect(NetworkNodeStateLabels("policy", "node")).To(Equal(map[string]string{
+			NodeStatePolicyLabel: "policy",
+			NodeStateNodeLabel:   "node",
+		}))
+	})
+})
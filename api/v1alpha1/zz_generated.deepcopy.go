@@ -21,12 +21,49 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressAllocation) DeepCopyInto(out *AddressAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressAllocation.
+func (in *AddressAllocation) DeepCopy() *AddressAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressPool) DeepCopyInto(out *AddressPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressPool.
+func (in *AddressPool) DeepCopy() *AddressPool {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GaudiScaleOutSpec) DeepCopyInto(out *GaudiScaleOutSpec) {
 	*out = *in
+	in.LinkDiscoverySpec.DeepCopyInto(&out.LinkDiscoverySpec)
+	if in.AddressPool != nil {
+		in, out := &in.AddressPool, &out.AddressPool
+		*out = new(AddressPool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GaudiScaleOutSpec.
@@ -39,6 +76,145 @@ func (in *GaudiScaleOutSpec) DeepCopy() *GaudiScaleOutSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostNICSpec) DeepCopyInto(out *HostNICSpec) {
+	*out = *in
+	if in.PCIIDs != nil {
+		in, out := &in.PCIIDs, &out.PCIIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LinkDiscoverySpec.DeepCopyInto(&out.LinkDiscoverySpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostNICSpec.
+func (in *HostNICSpec) DeepCopy() *HostNICSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostNICSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceFilter) DeepCopyInto(out *InterfaceFilter) {
+	*out = *in
+	if in.Drivers != nil {
+		in, out := &in.Drivers, &out.Drivers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PCIAddresses != nil {
+		in, out := &in.PCIAddresses, &out.PCIAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceFilter.
+func (in *InterfaceFilter) DeepCopy() *InterfaceFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceSelection) DeepCopyInto(out *InterfaceSelection) {
+	*out = *in
+	in.Include.DeepCopyInto(&out.Include)
+	in.Exclude.DeepCopyInto(&out.Exclude)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceSelection.
+func (in *InterfaceSelection) DeepCopy() *InterfaceSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceState) DeepCopyInto(out *InterfaceState) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LLDPPeer != nil {
+		in, out := &in.LLDPPeer, &out.LLDPPeer
+		*out = new(LLDPPeerState)
+		**out = **in
+	}
+	if in.Reachability != nil {
+		in, out := &in.Reachability, &out.Reachability
+		*out = new(PeerReachability)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceState.
+func (in *InterfaceState) DeepCopy() *InterfaceState {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LLDPPeerState) DeepCopyInto(out *LLDPPeerState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LLDPPeerState.
+func (in *LLDPPeerState) DeepCopy() *LLDPPeerState {
+	if in == nil {
+		return nil
+	}
+	out := new(LLDPPeerState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkDiscoverySpec) DeepCopyInto(out *LinkDiscoverySpec) {
+	*out = *in
+	if in.RoutedNetworks != nil {
+		in, out := &in.RoutedNetworks, &out.RoutedNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.InterfaceSelection.DeepCopyInto(&out.InterfaceSelection)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkDiscoverySpec.
+func (in *LinkDiscoverySpec) DeepCopy() *LinkDiscoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkDiscoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkClusterPolicy) DeepCopyInto(out *NetworkClusterPolicy) {
 	*out = *in
@@ -108,7 +284,8 @@ func (in *NetworkClusterPolicySpec) DeepCopyInto(out *NetworkClusterPolicySpec)
 			(*out)[key] = val
 		}
 	}
-	out.GaudiScaleOut = in.GaudiScaleOut
+	in.GaudiScaleOut.DeepCopyInto(&out.GaudiScaleOut)
+	in.HostNIC.DeepCopyInto(&out.HostNIC)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkClusterPolicySpec.
@@ -129,6 +306,18 @@ func (in *NetworkClusterPolicyStatus) DeepCopyInto(out *NetworkClusterPolicyStat
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.UnreachableInterfaces != nil {
+		in, out := &in.UnreachableInterfaces, &out.UnreachableInterfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkClusterPolicyStatus.
@@ -140,3 +329,121 @@ func (in *NetworkClusterPolicyStatus) DeepCopy() *NetworkClusterPolicyStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkNodeState) DeepCopyInto(out *NetworkNodeState) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkNodeState.
+func (in *NetworkNodeState) DeepCopy() *NetworkNodeState {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkNodeState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkNodeState) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkNodeStateList) DeepCopyInto(out *NetworkNodeStateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetworkNodeState, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkNodeStateList.
+func (in *NetworkNodeStateList) DeepCopy() *NetworkNodeStateList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkNodeStateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkNodeStateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkNodeStateSpec) DeepCopyInto(out *NetworkNodeStateSpec) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]AddressAllocation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkNodeStateSpec.
+func (in *NetworkNodeStateSpec) DeepCopy() *NetworkNodeStateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkNodeStateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkNodeStateStatus) DeepCopyInto(out *NetworkNodeStateStatus) {
+	*out = *in
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]InterfaceState, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastUpdate.DeepCopyInto(&out.LastUpdate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkNodeStateStatus.
+func (in *NetworkNodeStateStatus) DeepCopy() *NetworkNodeStateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkNodeStateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeerReachability) DeepCopyInto(out *PeerReachability) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeerReachability.
+func (in *PeerReachability) DeepCopy() *PeerReachability {
+	if in == nil {
+		return nil
+	}
+	out := new(PeerReachability)
+	in.DeepCopyInto(out)
+	return out
+}
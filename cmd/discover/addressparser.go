@@ -0,0 +1,224 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	addressParserPortDescription     = "port-description"
+	addressParserPortDescriptionLast = "port-description-last"
+	addressParserKeyValue            = "key-value"
+	addressParserOrgTLV              = "org-tlv"
+	addressParserMgmtAddress         = "mgmt-address"
+)
+
+// addressParser extracts the switch port address and network from the
+// LLDP information received on an interface.
+type addressParser func(nwconfig *networkConfiguration) (net.IP, *net.IPNet, error)
+
+var addressParsers = map[string]addressParser{
+	addressParserPortDescription:     parsePortDescription,
+	addressParserPortDescriptionLast: parsePortDescriptionLast,
+	addressParserKeyValue:            parseKeyValue,
+	addressParserOrgTLV:              parseOrgTLV,
+	addressParserMgmtAddress:         parseMgmtAddress,
+}
+
+// address keys accepted by the key-value parser, e.g. "ip=10.1.2.1/30"
+var addressKeys = []string{"ip", "ipv4", "ipv6", "addr", "address"}
+
+func getAddressParser(name string) (addressParser, error) {
+	parser, ok := addressParsers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown LLDP address parser '%s', valid parsers: %s",
+			name, strings.Join(addressParserNames(), ", "))
+	}
+
+	return parser, nil
+}
+
+func addressParserNames() []string {
+	names := make([]string, 0, len(addressParsers))
+	for name := range addressParsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// parsePortDescription expects the address as the second token in the
+// port description, e.g. "no-alert 10.1.2.1/30".
+func parsePortDescription(nwconfig *networkConfiguration) (net.IP, *net.IPNet, error) {
+	substrings := strings.Split(nwconfig.portDescription, " ")
+	if len(substrings) < 2 {
+		return nil, nil, fmt.Errorf("interface '%s' could not split string '%s'",
+			nwconfig.link.Attrs().Name, nwconfig.portDescription)
+	}
+
+	peeraddr, peerNetwork, err := net.ParseCIDR(substrings[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("interface '%s' could not parse '%s': %v",
+			nwconfig.link.Attrs().Name, nwconfig.portDescription, err)
+	}
+
+	return peeraddr, peerNetwork, nil
+}
+
+// parsePortDescriptionLast expects the address as the last token in the
+// port description, e.g. "Eth1/1 to gaudi-01 10.1.2.1/30".
+func parsePortDescriptionLast(nwconfig *networkConfiguration) (net.IP, *net.IPNet, error) {
+	substrings := strings.Fields(nwconfig.portDescription)
+	if len(substrings) == 0 {
+		return nil, nil, fmt.Errorf("interface '%s' has an empty port description",
+			nwconfig.link.Attrs().Name)
+	}
+
+	peeraddr, peerNetwork, err := net.ParseCIDR(substrings[len(substrings)-1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("interface '%s' could not parse '%s': %v",
+			nwconfig.link.Attrs().Name, nwconfig.portDescription, err)
+	}
+
+	return peeraddr, peerNetwork, nil
+}
+
+// parseKeyValue expects the address as a key-value pair anywhere in the
+// port description, e.g. "uplink ip=10.1.2.1/30 vlan=10". Pairs can be
+// separated by spaces, commas or semicolons.
+func parseKeyValue(nwconfig *networkConfiguration) (net.IP, *net.IPNet, error) {
+	fields := strings.FieldsFunc(nwconfig.portDescription, func(r rune) bool {
+		return r == ' ' || r == ',' || r == ';'
+	})
+
+	for _, field := range fields {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			key, value, found = strings.Cut(field, ":")
+		}
+		if !found {
+			continue
+		}
+
+		for _, addressKey := range addressKeys {
+			if !strings.EqualFold(key, addressKey) {
+				continue
+			}
+
+			peeraddr, peerNetwork, err := net.ParseCIDR(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("interface '%s' could not parse '%s': %v",
+					nwconfig.link.Attrs().Name, nwconfig.portDescription, err)
+			}
+
+			return peeraddr, peerNetwork, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("interface '%s' has no address key in '%s'",
+		nwconfig.link.Attrs().Name, nwconfig.portDescription)
+}
+
+// parseOrgTLV expects the address in CIDR notation as the payload of an
+// organizationally specific LLDP TLV.
+func parseOrgTLV(nwconfig *networkConfiguration) (net.IP, *net.IPNet, error) {
+	for _, tlv := range nwconfig.orgTLVs {
+		info := strings.Trim(string(tlv.Info), " \x00")
+
+		if peeraddr, peerNetwork, err := net.ParseCIDR(info); err == nil {
+			return peeraddr, peerNetwork, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("interface '%s' has no address in %d organizationally specific TLVs",
+		nwconfig.link.Attrs().Name, len(nwconfig.orgTLVs))
+}
+
+// parseMgmtAddress uses the first LLDP management address as the switch
+// port address. The TLV carries no prefix length, so the point-to-point
+// network is assumed to be a /30 for IPv4 and a /127 for IPv6.
+func parseMgmtAddress(nwconfig *networkConfiguration) (net.IP, *net.IPNet, error) {
+	if len(nwconfig.mgmtAddresses) == 0 {
+		return nil, nil, fmt.Errorf("interface '%s' has no LLDP management address",
+			nwconfig.link.Attrs().Name)
+	}
+
+	peeraddr := nwconfig.mgmtAddresses[0]
+
+	mask := net.CIDRMask(int(RouteMaskPointToPoint), ipv4Bits)
+	if peeraddr.To4() == nil {
+		mask = net.CIDRMask(int(RouteMaskPointToPointIPv6), ipv6Bits)
+	}
+
+	if err := checkPointToPointPeer(nwconfig, peeraddr); err != nil {
+		return nil, nil, err
+	}
+
+	return peeraddr, &net.IPNet{IP: peeraddr.Mask(mask), Mask: mask}, nil
+}
+
+// checkPointToPointPeer returns an error if the management address can't be
+// the switch side of a point-to-point network on the link: if it isn't a
+// host address of a /30 or if it is in a network of another interface, like
+// the out-of-band management network of the switches. The routes discover
+// added and the routes of the other selected interfaces, e.g. to the routed
+// networks over a sibling port, don't count.
+func checkPointToPointPeer(nwconfig *networkConfiguration, peeraddr net.IP) error {
+	name := nwconfig.link.Attrs().Name
+
+	if addr4 := peeraddr.To4(); addr4 != nil {
+		if host := addr4[3] & 0x3; host == 0 || host == 0x3 {
+			return fmt.Errorf("interface '%s' management address %s is not a host address of a /%d",
+				name, peeraddr, RouteMaskPointToPoint)
+		}
+	}
+
+	family := netlink.FAMILY_V4
+	if peeraddr.To4() == nil {
+		family = netlink.FAMILY_V6
+	}
+
+	routes, err := networkLink.RouteList(nil, family)
+	if err != nil {
+		return fmt.Errorf("cannot list the routes to check the management address of interface '%s': %v", name, err)
+	}
+
+	selected := map[int]bool{nwconfig.link.Attrs().Index: true}
+	for _, sibling := range nwconfig.selected {
+		selected[sibling.link.Attrs().Index] = true
+	}
+
+	for _, route := range routes {
+		if route.Dst == nil || route.Protocol == routeProtocolDiscover || selected[route.LinkIndex] {
+			continue
+		}
+
+		if ones, _ := route.Dst.Mask.Size(); ones > 0 && route.Dst.Contains(peeraddr) {
+			return fmt.Errorf("interface '%s' management address %s is in the network %s of another interface",
+				name, peeraddr, route.Dst)
+		}
+	}
+
+	return nil
+}
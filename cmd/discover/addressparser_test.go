@@ -0,0 +1,306 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/intel/network-operator/pkg/lldp"
+)
+
+func TestGetAddressParser(t *testing.T) {
+	for _, name := range addressParserNames() {
+		if _, err := getAddressParser(name); err != nil {
+			t.Errorf("parser '%s' not found: %v", name, err)
+		}
+	}
+
+	if _, err := getAddressParser("foo"); err == nil {
+		t.Error("unknown parser found")
+	}
+}
+
+func TestAddressParsers(t *testing.T) {
+	tcases := []struct {
+		name            string
+		parser          string
+		portDescription string
+		orgTLVs         []lldp.OrgTLV
+		mgmtAddresses   []net.IP
+		expectedPeer    string
+		expectErr       bool
+	}{
+		{
+			name:            "second token",
+			parser:          addressParserPortDescription,
+			portDescription: "no-alert 10.1.2.1/30",
+			expectedPeer:    "10.1.2.1",
+		},
+		{
+			name:            "second token missing",
+			parser:          addressParserPortDescription,
+			portDescription: "10.1.2.1/30",
+			expectErr:       true,
+		},
+		{
+			name:            "last token",
+			parser:          addressParserPortDescriptionLast,
+			portDescription: "Eth1/1 to gaudi-01 10.1.2.1/30",
+			expectedPeer:    "10.1.2.1",
+		},
+		{
+			name:            "last token IPv6",
+			parser:          addressParserPortDescriptionLast,
+			portDescription: "uplink fd00::1/127",
+			expectedPeer:    "fd00::1",
+		},
+		{
+			name:      "last token empty",
+			parser:    addressParserPortDescriptionLast,
+			expectErr: true,
+		},
+		{
+			name:            "key-value",
+			parser:          addressParserKeyValue,
+			portDescription: "uplink ip=10.1.2.1/30 vlan=10",
+			expectedPeer:    "10.1.2.1",
+		},
+		{
+			name:            "key-value with separators",
+			parser:          addressParserKeyValue,
+			portDescription: "vlan=10;IPv6:fd00::1/127",
+			expectedPeer:    "fd00::1",
+		},
+		{
+			name:            "key-value invalid address",
+			parser:          addressParserKeyValue,
+			portDescription: "ip=10.1.2.1",
+			expectErr:       true,
+		},
+		{
+			name:            "key-value missing key",
+			parser:          addressParserKeyValue,
+			portDescription: "no-alert 10.1.2.1/30",
+			expectErr:       true,
+		},
+		{
+			name:   "org tlv",
+			parser: addressParserOrgTLV,
+			orgTLVs: []lldp.OrgTLV{
+				{OUI: 0x0080c2, SubType: 1, Info: []byte{0x00, 0x0a}},
+				{OUI: 0x001b21, SubType: 1, Info: []byte("10.1.2.1/30\x00")},
+			},
+			expectedPeer: "10.1.2.1",
+		},
+		{
+			name:   "org tlv missing",
+			parser: addressParserOrgTLV,
+			orgTLVs: []lldp.OrgTLV{
+				{OUI: 0x0080c2, SubType: 1, Info: []byte{0x00, 0x0a}},
+			},
+			expectErr: true,
+		},
+		{
+			name:   "management address",
+			parser: addressParserMgmtAddress,
+			mgmtAddresses: []net.IP{
+				net.IPv4(10, 1, 2, 1).To4(),
+				net.ParseIP("fd00::1"),
+			},
+			expectedPeer: "10.1.2.1",
+		},
+		{
+			name:      "management address missing",
+			parser:    addressParserMgmtAddress,
+			expectErr: true,
+		},
+	}
+
+	networkLink.RouteList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		return nil, nil
+	}
+
+	for _, tc := range tcases {
+		parser, err := getAddressParser(tc.parser)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		nwconfig := networkConfiguration{
+			link: &fakeLink{
+				fakeAttrs: netlink.LinkAttrs{
+					Name: "eth_a",
+				},
+			},
+			portDescription: tc.portDescription,
+			orgTLVs:         tc.orgTLVs,
+			mgmtAddresses:   tc.mgmtAddresses,
+		}
+
+		peer, _, err := parser(&nwconfig)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got peer %s", tc.name, peer)
+			}
+			continue
+		}
+
+		if err != nil || peer.String() != tc.expectedPeer {
+			t.Errorf("%s: expected peer %s, got %s: %v", tc.name, tc.expectedPeer, peer, err)
+		}
+	}
+}
+
+func TestSelectPointToPointL3AddressParser(t *testing.T) {
+	parser, _ := getAddressParser(addressParserKeyValue)
+
+	nwconfig := networkConfiguration{
+		link: &fakeLink{
+			fakeAttrs: netlink.LinkAttrs{
+				Name: "eth_a",
+			},
+		},
+		portDescription: "ip=10.210.8.122/30",
+		addressParser:   parser,
+	}
+
+	_, localaddr, _, err := selectPointToPointL3Address(&nwconfig)
+	if err != nil || localaddr.String() != "10.210.8.121" {
+		t.Errorf("expected local address 10.210.8.121, got %s: %v", localaddr, err)
+	}
+}
+
+func TestSelectPointToPointL3AddressMgmtFallback(t *testing.T) {
+	nwconfig := networkConfiguration{
+		link: &fakeLink{
+			fakeAttrs: netlink.LinkAttrs{
+				Name: "eth_a",
+			},
+		},
+		portDescription: "no-alert",
+		mgmtAddresses:   []net.IP{net.ParseIP("fd00:10:210::1")},
+	}
+
+	networkLink.RouteList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		return nil, nil
+	}
+
+	// the fallback is opt-in
+	if _, _, _, err := selectPointToPointL3Address(&nwconfig); err == nil {
+		t.Error("management address used without the fallback")
+	}
+
+	nwconfig.mgmtFallback = true
+
+	peeraddr, localaddr, prefixlen, err := selectPointToPointL3Address(&nwconfig)
+	if err != nil || peeraddr.String() != "fd00:10:210::1" ||
+		localaddr.String() != "fd00:10:210::" || prefixlen != 127 {
+		t.Errorf("unexpected fallback addresses %s, %s/%d: %v", peeraddr, localaddr, prefixlen, err)
+	}
+
+	nwconfig.mgmtAddresses = nil
+	if _, _, _, err := selectPointToPointL3Address(&nwconfig); err == nil {
+		t.Error("address selection succeeded without an address")
+	}
+}
+
+func TestParseMgmtAddressPointToPoint(t *testing.T) {
+	nwconfig := networkConfiguration{
+		link: &fakeLink{
+			fakeAttrs: netlink.LinkAttrs{
+				Name:  "eth_a",
+				Index: 3,
+			},
+		},
+	}
+
+	_, oob, _ := net.ParseCIDR("10.1.0.0/16")
+	_, p2p, _ := net.ParseCIDR("10.2.0.0/30")
+
+	networkLink.RouteList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		return []netlink.Route{
+			{Dst: oob, LinkIndex: 2},
+			{Dst: p2p, LinkIndex: 3},
+			{LinkIndex: 2},
+		}, nil
+	}
+
+	tcases := []struct {
+		addr      net.IP
+		expectErr bool
+	}{
+		{addr: net.IPv4(10, 2, 0, 1)},
+		{addr: net.IPv4(10, 3, 0, 2)},
+		{addr: net.IPv4(10, 3, 0, 3), expectErr: true},
+		{addr: net.IPv4(10, 3, 0, 4), expectErr: true},
+		{addr: net.IPv4(10, 1, 2, 1), expectErr: true},
+		{addr: net.ParseIP("fd00::1")},
+	}
+
+	for _, tc := range tcases {
+		nwconfig.mgmtAddresses = []net.IP{tc.addr}
+
+		_, _, err := parseMgmtAddress(&nwconfig)
+		if tc.expectErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", tc.addr, tc.expectErr, err)
+		}
+	}
+}
+
+func TestParseMgmtAddressSiblingRoutes(t *testing.T) {
+	nwconfigs := map[string]*networkConfiguration{
+		"eth_a": {link: &fakeLink{fakeAttrs: netlink.LinkAttrs{Name: "eth_a", Index: 3}}},
+		"eth_b": {link: &fakeLink{fakeAttrs: netlink.LinkAttrs{Name: "eth_b", Index: 4}}},
+	}
+
+	_, routed, _ := net.ParseCIDR("10.210.0.0/16")
+
+	// a previous run added the route to the routed networks over the sibling port
+	networkLink.RouteList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		return []netlink.Route{
+			{Dst: routed, LinkIndex: 4, Protocol: routeProtocolDiscover},
+		}, nil
+	}
+
+	nwconfig := nwconfigs["eth_a"]
+	nwconfig.mgmtAddresses = []net.IP{net.IPv4(10, 210, 8, 122)}
+
+	if _, _, err := parseMgmtAddress(nwconfig); err != nil {
+		t.Errorf("management address rejected for a discover route: %v", err)
+	}
+
+	// the same route added by someone else on a sibling port
+	networkLink.RouteList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		return []netlink.Route{
+			{Dst: routed, LinkIndex: 4, Protocol: unix.RTPROT_STATIC},
+		}, nil
+	}
+
+	if _, _, err := parseMgmtAddress(nwconfig); err == nil {
+		t.Error("management address accepted in the network of another interface")
+	}
+
+	nwconfig.selected = nwconfigs
+
+	if _, _, err := parseMgmtAddress(nwconfig); err != nil {
+		t.Errorf("management address rejected for a route of a sibling port: %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"k8s.io/klog/v2"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+const (
+	addressSourceLLDP = "lldp"
+	addressSourcePool = "pool"
+
+	addressPoolPollInterval = 5 * time.Second
+)
+
+// parseAddressAllocation returns the gateway, the local address and the
+// prefix length of the point-to-point network of an allocation.
+func parseAddressAllocation(allocation networkv1alpha1.AddressAllocation) (*net.IP, *net.IP, int, error) {
+	localAddr, network, err := net.ParseCIDR(allocation.Address)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("interface '%s' has an invalid address allocation: %v", allocation.Interface, err)
+	}
+
+	gateway := net.ParseIP(allocation.Gateway)
+	if gateway == nil || !network.Contains(gateway) || gateway.Equal(localAddr) {
+		return nil, nil, 0, fmt.Errorf("interface '%s' has an invalid gateway '%s' for %s",
+			allocation.Interface, allocation.Gateway, allocation.Address)
+	}
+
+	prefixLen, _ := network.Mask.Size()
+
+	return &gateway, &localAddr, prefixLen, nil
+}
+
+func allocationChanged(nwconfig *networkConfiguration, gateway, localAddr *net.IP, prefixLen int) bool {
+	return nwconfig.localAddr == nil || !nwconfig.localAddr.Equal(*localAddr) ||
+		nwconfig.lldpPeer == nil || !nwconfig.lldpPeer.Equal(*gateway) || nwconfig.prefixLen != prefixLen
+}
+
+// setAddressAllocations sets the allocated addresses of the interfaces and
+// returns the number of interfaces with an address.
+func setAddressAllocations(networkConfigs map[string]*networkConfiguration, allocations []networkv1alpha1.AddressAllocation) int {
+	for _, allocation := range allocations {
+		nwconfig, ok := networkConfigs[allocation.Interface]
+		if !ok {
+			continue
+		}
+
+		gateway, localAddr, prefixLen, err := parseAddressAllocation(allocation)
+		if err != nil {
+			klog.Warning(err)
+			nwconfig.configErr = err
+			nwconfig.setStepError(stepAddress, err)
+
+			continue
+		}
+
+		nwconfig.lldpPeer = gateway
+		nwconfig.localAddr = localAddr
+		nwconfig.prefixLen = prefixLen
+		nwconfig.configErr = nil
+		nwconfig.setStepError(stepAddress, nil)
+	}
+
+	allocated := 0
+
+	for _, nwconfig := range networkConfigs {
+		if nwconfig.localAddr != nil {
+			allocated++
+		}
+	}
+
+	return allocated
+}
+
+// waitAddressAllocations reports the interfaces to the operator and waits
+// until it has allocated addresses for all of them from the address pool.
+// Returns true if any of the interfaces got an address.
+func waitAddressAllocations(config *cmdConfig, reporter *nodeStateReporter, networkConfigs map[string]*networkConfiguration) bool {
+	klog.Infof("Waiting for addresses from the address pool...")
+
+	// the operator allocates the addresses for the reported interfaces
+	reporter.pending = true
+	defer func() { reporter.pending = false }()
+
+	timeoutctx, cancelctx := context.WithTimeout(config.ctx, config.timeout)
+	defer cancelctx()
+
+	poll := time.NewTicker(addressPoolPollInterval)
+	defer poll.Stop()
+
+	reported := false
+
+wait:
+	for {
+		if !reported {
+			reported = reportNodeState(config, reporter, networkConfigs, nil)
+		}
+
+		allocations, err := reporter.addressAllocations(config.ctx)
+		if err != nil {
+			klog.Warningf("Could not get address allocations: %v", err)
+		} else if setAddressAllocations(networkConfigs, allocations) == len(networkConfigs) {
+			break
+		}
+
+		select {
+		case <-timeoutctx.Done():
+			break wait
+		case <-poll.C:
+		}
+	}
+
+	found := false
+
+	for ifname, nwconfig := range networkConfigs {
+		if nwconfig.localAddr != nil {
+			found = true
+
+			continue
+		}
+
+		if nwconfig.configErr == nil {
+			nwconfig.configErr = fmt.Errorf("no address allocated for interface '%s'", ifname)
+			nwconfig.setStepError(stepAddress, nwconfig.configErr)
+		}
+
+		klog.Warning(nwconfig.configErr)
+	}
+
+	return found
+}
+
+// reconcileAddressAllocations applies the changes in the address allocations
+// and returns the names of the changed interfaces.
+func reconcileAddressAllocations(config *cmdConfig, reporter *nodeStateReporter, networkConfigs map[string]*networkConfiguration) []string {
+	allocations, err := reporter.addressAllocations(config.ctx)
+	if err != nil {
+		klog.Warningf("Could not get address allocations: %v", err)
+
+		return nil
+	}
+
+	changed := []string{}
+	allocated := map[string]bool{}
+
+	for _, allocation := range allocations {
+		nwconfig, ok := networkConfigs[allocation.Interface]
+		if !ok {
+			continue
+		}
+
+		allocated[allocation.Interface] = true
+
+		gateway, localAddr, prefixLen, err := parseAddressAllocation(allocation)
+		if err != nil {
+			klog.Warningf("%v, keeping the current configuration", err)
+			nwconfig.configErr = err
+
+			continue
+		}
+
+		if !allocationChanged(nwconfig, gateway, localAddr, prefixLen) {
+			continue
+		}
+
+		klog.Infof("Address allocation changed for interface '%s'", allocation.Interface)
+
+		deconfigureInterface(nwconfig)
+
+		nwconfig.lldpPeer = gateway
+		nwconfig.localAddr = localAddr
+		nwconfig.prefixLen = prefixLen
+
+		single := map[string]*networkConfiguration{allocation.Interface: nwconfig}
+
+		configureInterfaces(single)
+		resolveGateways(config.ctx, single)
+
+		changed = append(changed, allocation.Interface)
+	}
+
+	// the address was released, e.g. the pool was removed from the policy
+	for ifname, nwconfig := range networkConfigs {
+		if allocated[ifname] || nwconfig.localAddr == nil {
+			continue
+		}
+
+		klog.Infof("Address allocation removed for interface '%s'", ifname)
+
+		deconfigureInterface(nwconfig)
+		nwconfig.peerHWAddr = nil
+
+		changed = append(changed, ifname)
+	}
+
+	return changed
+}
+
+// resolveGateways resolves the MAC addresses of the gateways for the
+// gaudinet file, as there's no LLDP peer to take them from.
+func resolveGateways(ctx context.Context, networkConfigs map[string]*networkConfiguration) {
+	for ifname, nwconfig := range networkConfigs {
+		if nwconfig.lldpPeer == nil || nwconfig.localAddr == nil || nwconfig.configErr != nil {
+			continue
+		}
+
+		// the echo request triggers the address resolution
+		echoctx, cancel := context.WithTimeout(ctx, probeTimeout)
+		if err := icmpEcho(echoctx, ifname, *nwconfig.lldpPeer); err != nil {
+			klog.V(3).Infof("No echo reply from gateway %s of interface '%s': %v", nwconfig.lldpPeer, ifname, err)
+		}
+		cancel()
+
+		neighs, err := networkLink.NeighList(nwconfig.link.Attrs().Index, addrFamily(nwconfig))
+		if err != nil {
+			klog.Warningf("Cannot list neighbors of interface '%s': %v", ifname, err)
+
+			continue
+		}
+
+		nwconfig.peerHWAddr = nil
+
+		for _, neigh := range neighs {
+			if !neigh.IP.Equal(*nwconfig.lldpPeer) || len(neigh.HardwareAddr) == 0 ||
+				neigh.State&(netlink.NUD_INCOMPLETE|netlink.NUD_FAILED) != 0 {
+				continue
+			}
+
+			hwAddr := neigh.HardwareAddr
+			nwconfig.peerHWAddr = &hwAddr
+
+			break
+		}
+
+		if nwconfig.peerHWAddr == nil {
+			klog.Warningf("Gateway %s of interface '%s' is not resolved", nwconfig.lldpPeer, ifname)
+		}
+	}
+}
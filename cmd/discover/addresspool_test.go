@@ -0,0 +1,303 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+func TestParseAddressAllocation(t *testing.T) {
+	gateway, localAddr, prefixLen, err := parseAddressAllocation(networkv1alpha1.AddressAllocation{
+		Interface: "eth_a", Address: "10.210.0.2/30", Gateway: "10.210.0.1",
+	})
+	if err != nil || gateway.String() != "10.210.0.1" || localAddr.String() != "10.210.0.2" || prefixLen != 30 {
+		t.Errorf("unexpected allocation %s, %s, %d: %v", gateway, localAddr, prefixLen, err)
+	}
+
+	_, localAddr, prefixLen, err = parseAddressAllocation(networkv1alpha1.AddressAllocation{
+		Interface: "eth_a", Address: "fd00:10::1/127", Gateway: "fd00:10::",
+	})
+	if err != nil || localAddr.String() != "fd00:10::1" || prefixLen != 127 {
+		t.Errorf("unexpected IPv6 allocation %s, %d: %v", localAddr, prefixLen, err)
+	}
+
+	invalid := []networkv1alpha1.AddressAllocation{
+		{Interface: "eth_a", Address: "10.210.0.2", Gateway: "10.210.0.1"},
+		{Interface: "eth_a", Address: "10.210.0.2/30", Gateway: "10.210.0.5"},
+		{Interface: "eth_a", Address: "10.210.0.2/30", Gateway: "10.210.0.2"},
+		{Interface: "eth_a", Address: "10.210.0.2/30", Gateway: ""},
+	}
+
+	for _, allocation := range invalid {
+		if _, _, _, err := parseAddressAllocation(allocation); err == nil {
+			t.Errorf("invalid allocation %+v accepted", allocation)
+		}
+	}
+}
+
+func TestAddressAllocations(t *testing.T) {
+	var (
+		addedAddrs   []string
+		deletedAddrs []string
+		addedRoutes  []string
+	)
+
+	gatewayMAC := net.HardwareAddr{0x01, 0x01, 0x02, 0x02, 0x03, 0x03}
+
+	networkLink.LinkByName = fakeLinkByName
+	networkLink.AddrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return []netlink.Addr{}, nil
+	}
+	networkLink.AddrAdd = func(link netlink.Link, addr *netlink.Addr) error {
+		addedAddrs = append(addedAddrs, addr.IPNet.String())
+		return nil
+	}
+	networkLink.AddrDel = func(link netlink.Link, addr *netlink.Addr) error {
+		deletedAddrs = append(deletedAddrs, addr.IPNet.String())
+		return nil
+	}
+	networkLink.RouteAppend = func(route *netlink.Route) error {
+		addedRoutes = append(addedRoutes, route.Dst.String()+" via "+route.Gw.String())
+		return nil
+	}
+	networkLink.RouteDel = func(route *netlink.Route) error {
+		return nil
+	}
+	networkLink.RouteList = fakeRouteList
+	networkLink.NeighList = func(linkIndex, family int) ([]netlink.Neigh, error) {
+		return []netlink.Neigh{
+			{IP: net.IPv4(10, 210, 0, 5), HardwareAddr: gatewayMAC, State: netlink.NUD_REACHABLE},
+		}, nil
+	}
+
+	icmpEcho = func(ctx context.Context, ifname string, peer net.IP) error {
+		return nil
+	}
+	defer func() { icmpEcho = sendICMPEcho }()
+
+	scheme := runtime.NewScheme()
+	if err := networkv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot add scheme: %v", err)
+	}
+
+	state := &networkv1alpha1.NetworkNodeState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      networkv1alpha1.NetworkNodeStateName("policy", "node"),
+			Namespace: "ns",
+			Labels:    networkv1alpha1.NetworkNodeStateLabels("policy", "node"),
+		},
+		Spec: networkv1alpha1.NetworkNodeStateSpec{
+			NodeName: "node",
+			Policy:   "policy",
+			Addresses: []networkv1alpha1.AddressAllocation{
+				{Interface: "eth_a", Address: "10.210.0.2/30", Gateway: "10.210.0.1"},
+				{Interface: "eth_b", Address: "10.210.0.6/30", Gateway: "10.210.0.5"},
+				{Interface: "eth_c", Address: "10.210.0.10/30", Gateway: "10.210.0.9"},
+			},
+		},
+	}
+
+	reporter := &nodeStateReporter{
+		client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(state).
+			WithStatusSubresource(state).
+			Build(),
+		policy:    "policy",
+		node:      "node",
+		namespace: state.Namespace,
+	}
+
+	ctx := context.Background()
+	config := &cmdConfig{ctx: ctx, mode: L3, addressSource: addressSourcePool}
+	nwconfigs := getFakeNetworkDataConfigs()
+
+	if !waitAddressAllocations(config, reporter, nwconfigs) {
+		t.Fatal("no addresses allocated")
+	}
+
+	reported := &networkv1alpha1.NetworkNodeState{}
+	if err := reporter.client.Get(ctx, client.ObjectKeyFromObject(state), reported); err != nil {
+		t.Fatalf("cannot get node state: %v", err)
+	}
+
+	if reported.Status.State != networkv1alpha1.NodeStatePending || len(reported.Status.Interfaces) != len(nwconfigs) {
+		t.Errorf("interfaces not reported before waiting: '%s', %d interfaces", reported.Status.State, len(reported.Status.Interfaces))
+	}
+
+	if reporter.pending {
+		t.Error("node state still pending")
+	}
+
+	for name, nwconfig := range nwconfigs {
+		if nwconfig.localAddr == nil || nwconfig.lldpPeer == nil || nwconfig.prefixLen != 30 {
+			t.Errorf("no address allocation for '%s'", name)
+		}
+	}
+
+	configured, total := configureInterfaces(nwconfigs)
+	if configured != total {
+		t.Errorf("expected all interfaces to be configured, got %d/%d", configured, total)
+	}
+
+	resolveGateways(context.Background(), nwconfigs)
+
+	if nwconfigs["eth_b"].peerHWAddr == nil || nwconfigs["eth_b"].peerHWAddr.String() != gatewayMAC.String() {
+		t.Errorf("gateway of eth_b was not resolved: %v", nwconfigs["eth_b"].peerHWAddr)
+	}
+	if nwconfigs["eth_a"].peerHWAddr != nil {
+		t.Errorf("unexpected gateway MAC for eth_a: %v", nwconfigs["eth_a"].peerHWAddr)
+	}
+
+	// unchanged allocations
+	if changed := reconcileAddressAllocations(config, reporter, nwconfigs); len(changed) != 0 {
+		t.Errorf("unchanged allocations reconciled: %v", changed)
+	}
+
+	// re-allocated address and removed allocation
+	reported.Spec.Addresses = []networkv1alpha1.AddressAllocation{
+		{Interface: "eth_a", Address: "10.210.0.14/30", Gateway: "10.210.0.13"},
+		{Interface: "eth_b", Address: "10.210.0.6/30", Gateway: "10.210.0.5"},
+	}
+	if err := reporter.client.Update(ctx, reported); err != nil {
+		t.Fatalf("cannot update node state: %v", err)
+	}
+
+	addedAddrs, deletedAddrs = nil, nil
+
+	changed := reconcileAddressAllocations(config, reporter, nwconfigs)
+	slices.Sort(changed)
+
+	if !slices.Equal(changed, []string{"eth_a", "eth_c"}) {
+		t.Errorf("unexpected changed interfaces %v", changed)
+	}
+	if !slices.Equal(addedAddrs, []string{"10.210.0.14/30"}) {
+		t.Errorf("unexpected added addresses %v", addedAddrs)
+	}
+
+	slices.Sort(deletedAddrs)
+
+	if !slices.Equal(deletedAddrs, []string{"10.210.0.10/30", "10.210.0.2/30"}) {
+		t.Errorf("unexpected deleted addresses %v", deletedAddrs)
+	}
+	if nwconfigs["eth_c"].localAddr != nil {
+		t.Error("released address still set for eth_c")
+	}
+}
+
+func TestWaitAddressAllocationsTimeout(t *testing.T) {
+	networkLink.LinkByName = fakeLinkByName
+	networkLink.AddrList = fakeLinkAddrList
+	networkLink.RouteList = fakeRouteList
+
+	scheme := runtime.NewScheme()
+	if err := networkv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot add scheme: %v", err)
+	}
+
+	state := &networkv1alpha1.NetworkNodeState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      networkv1alpha1.NetworkNodeStateName("policy", "node"),
+			Namespace: "ns",
+			Labels:    networkv1alpha1.NetworkNodeStateLabels("policy", "node"),
+		},
+		Spec: networkv1alpha1.NetworkNodeStateSpec{
+			NodeName: "node",
+			Policy:   "policy",
+		},
+	}
+
+	reporter := &nodeStateReporter{
+		client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(state).
+			WithStatusSubresource(state).
+			Build(),
+		policy:    "policy",
+		node:      "node",
+		namespace: state.Namespace,
+	}
+
+	config := &cmdConfig{ctx: context.Background()}
+	nwconfigs := getFakeNetworkDataConfigs()
+
+	if waitAddressAllocations(config, reporter, nwconfigs) {
+		t.Error("addresses found without allocations")
+	}
+
+	for name, nwconfig := range nwconfigs {
+		if nwconfig.configErr == nil {
+			t.Errorf("no error for '%s' without an allocation", name)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	config = &cmdConfig{ctx: ctx, timeout: time.Hour}
+	start := time.Now()
+
+	if waitAddressAllocations(config, reporter, getFakeNetworkDataConfigs()) {
+		t.Error("addresses found without allocations")
+	}
+	if time.Since(start) > addressPoolPollInterval {
+		t.Error("waiting for the allocations didn't stop when the context was cancelled")
+	}
+}
+
+func TestSanitizeAddressSource(t *testing.T) {
+	config := &cmdConfig{
+		mode:           L3,
+		mtu:            1500,
+		addressParser:  addressParserPortDescription,
+		driver:         defaultDriver,
+		lldpTxInterval: 10 * time.Second,
+		addressSource:  addressSourcePool,
+	}
+
+	if err := sanitizeInput(config); err == nil {
+		t.Error("address pool accepted without a policy")
+	}
+
+	config.policy = "policy"
+	if err := sanitizeInput(config); err != nil {
+		t.Errorf("sanitizing input failed: %v", err)
+	}
+
+	config.mode = L2
+	if err := sanitizeInput(config); err == nil {
+		t.Error("address pool accepted in L2 mode")
+	}
+
+	config.mode = L3
+	config.addressSource = "dhcp"
+	if err := sanitizeInput(config); err == nil {
+		t.Error("unknown address source accepted")
+	}
+}
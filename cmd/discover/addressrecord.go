@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// addressRecord keeps the addresses discover added to the interfaces, so that
+// only those are removed. With a path, the record is kept on the host for the
+// addresses added before a restart of the daemon.
+type addressRecord struct {
+	path       string
+	Interfaces map[string][]string `json:"interfaces"`
+}
+
+// ownedAddresses are the addresses added by discover, only kept in memory
+// unless the record is loaded from a file.
+var ownedAddresses = &addressRecord{Interfaces: map[string][]string{}}
+
+// loadAddressRecord reads the record at path, an empty record if the file
+// doesn't exist or the path is empty.
+func loadAddressRecord(path string) (*addressRecord, error) {
+	record := &addressRecord{path: path, Interfaces: map[string][]string{}}
+	if path == "" {
+		return record, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return record, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read address record: %v", err)
+	}
+
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("cannot parse address record %s: %v", path, err)
+	}
+
+	if record.Interfaces == nil {
+		record.Interfaces = map[string][]string{}
+	}
+
+	return record, nil
+}
+
+// save writes the record to its path, the file is removed when the record is
+// empty.
+func (r *addressRecord) save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	if len(r.Interfaces) == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("cannot remove address record: %v", err)
+		}
+
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("cannot create address record directory: %v", err)
+	}
+
+	tmpPath := r.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("cannot write address record: %v", err)
+	}
+
+	return os.Rename(tmpPath, r.path)
+}
+
+func (r *addressRecord) contains(ifname string, addr *net.IPNet) bool {
+	return slices.Contains(r.Interfaces[ifname], addr.String())
+}
+
+// add records the address added to the interface.
+func (r *addressRecord) add(ifname string, addr *net.IPNet) error {
+	if r.contains(ifname, addr) {
+		return nil
+	}
+
+	r.Interfaces[ifname] = append(r.Interfaces[ifname], addr.String())
+
+	return r.save()
+}
+
+// remove drops the address removed from the interface from the record.
+func (r *addressRecord) remove(ifname string, addr *net.IPNet) error {
+	if !r.contains(ifname, addr) {
+		return nil
+	}
+
+	r.Interfaces[ifname] = slices.DeleteFunc(r.Interfaces[ifname], func(a string) bool {
+		return a == addr.String()
+	})
+
+	if len(r.Interfaces[ifname]) == 0 {
+		delete(r.Interfaces, ifname)
+	}
+
+	return r.save()
+}
+
+// forget drops the interface from the record once its addresses are removed.
+func (r *addressRecord) forget(ifname string) error {
+	if _, ok := r.Interfaces[ifname]; !ok {
+		return nil
+	}
+
+	delete(r.Interfaces, ifname)
+
+	return r.save()
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddressRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "addresses", "policy.json")
+
+	record, err := loadAddressRecord(path)
+	if err != nil {
+		t.Fatalf("cannot load a missing address record: %v", err)
+	}
+
+	_, addr, _ := net.ParseCIDR("10.210.8.120/30")
+	addr.IP = net.IPv4(10, 210, 8, 121).To4()
+
+	if err := record.add("eth_a", addr); err != nil {
+		t.Fatalf("cannot record address: %v", err)
+	}
+
+	// a restarted daemon knows the addresses it added before
+	restarted, err := loadAddressRecord(path)
+	if err != nil || !restarted.contains("eth_a", addr) || restarted.contains("eth_b", addr) {
+		t.Fatalf("unexpected address record %v: %v", restarted.Interfaces, err)
+	}
+
+	if err := restarted.remove("eth_a", addr); err != nil {
+		t.Fatalf("cannot remove address: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("empty address record was not removed: %v", err)
+	}
+}
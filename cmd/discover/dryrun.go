@@ -0,0 +1,403 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+
+	nm "github.com/intel/network-operator/internal/nm"
+)
+
+const (
+	dryRunFormatText = "text"
+	dryRunFormatJSON = "json"
+)
+
+// mtuChange is a planned change of the interface MTU.
+type mtuChange struct {
+	Current int `json:"current"`
+	Planned int `json:"planned"`
+}
+
+// interfacePlan holds the changes a run would make to an interface.
+type interfacePlan struct {
+	Name              string     `json:"name"`
+	SetUp             bool       `json:"setUp,omitempty"`
+	MTU               *mtuChange `json:"mtu,omitempty"`
+	RemoveRoutes      []string   `json:"removeRoutes,omitempty"`
+	RemoveAddresses   []string   `json:"removeAddresses,omitempty"`
+	AddAddresses      []string   `json:"addAddresses,omitempty"`
+	AddRoutes         []string   `json:"addRoutes,omitempty"`
+	ConnectionProfile string     `json:"connectionProfile,omitempty"`
+	Error             string     `json:"error,omitempty"`
+}
+
+// filePlan is a file a run would write.
+type filePlan struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// dryRunPlan holds the changes a run with the same flags would make to the
+// node.
+type dryRunPlan struct {
+	Interfaces []*interfacePlan `json:"interfaces"`
+	// interfaces that would be set unmanaged in NetworkManager
+	NetworkManagerUnmanage []string   `json:"networkManagerUnmanage,omitempty"`
+	Files                  []filePlan `json:"files,omitempty"`
+	Errors                 []string   `json:"errors,omitempty"`
+}
+
+// planInterface returns the changes bringing the interface up, setting the
+// MTU and removing the existing addresses would make.
+func planInterface(config *cmdConfig, name string, nwconfig *networkConfiguration) *interfacePlan {
+	plan := &interfacePlan{Name: name}
+
+	attrs := nwconfig.link.Attrs()
+
+	plan.SetUp = attrs.Flags&net.FlagUp == 0
+
+	if attrs.MTU != config.mtu {
+		plan.MTU = &mtuChange{Current: attrs.MTU, Planned: config.mtu}
+	}
+
+	routes, err := discoverRoutes(nwconfig.link)
+	if err != nil {
+		plan.Error = fmt.Sprintf("cannot list routes: %v", err)
+
+		return plan
+	}
+
+	for _, route := range routes {
+		plan.RemoveRoutes = append(plan.RemoveRoutes, routeString(route))
+	}
+
+	addrs, err := networkLink.AddrList(nwconfig.link, netlink.FAMILY_ALL)
+	if err != nil {
+		plan.Error = fmt.Sprintf("cannot list addresses: %v", err)
+
+		return plan
+	}
+
+	for _, addr := range addrs {
+		if !removableAddress(nwconfig, addr, config.flushAddresses) {
+			continue
+		}
+
+		plan.RemoveAddresses = append(plan.RemoveAddresses, addr.IPNet.String())
+	}
+
+	return plan
+}
+
+// planL3 adds the addresses and routes derived from the LLDP information or
+// the address allocations to the interface plan.
+func planL3(config *cmdConfig, plan *interfacePlan, nwconfig *networkConfiguration) {
+	if nwconfig.localAddr == nil {
+		switch {
+		case plan.Error != "":
+		case nwconfig.link.Attrs().Flags&net.FlagUp == 0:
+			plan.Error = "link is down, no LLDP information without bringing it up"
+		case config.addressSource == addressSourcePool:
+			plan.Error = "no address allocated"
+		default:
+			plan.Error = "no address derived from LLDP"
+		}
+
+		return
+	}
+
+	local := &net.IPNet{IP: *nwconfig.localAddr, Mask: pointToPointMask(nwconfig)}
+	plan.AddAddresses = append(plan.AddAddresses, local.String())
+
+	if nwconfig.lldpPeer != nil {
+		for _, dst := range routedNetworkDestinations(nwconfig) {
+			plan.AddRoutes = append(plan.AddRoutes, dst.String()+" via "+nwconfig.lldpPeer.String())
+		}
+	}
+
+	if config.backend == backendNetworkManager {
+		plan.ConnectionProfile = nm.ProfileName(plan.Name)
+	}
+}
+
+// planNetworkManager adds the interfaces NetworkManager would stop managing
+// and its configuration snippet to the plan.
+func planNetworkManager(config *cmdConfig, plan *dryRunPlan, interfaces []string, networkConfigs map[string]*networkConfiguration) {
+	nmapi, err := nm.NewNetworkManager()
+	if err != nil {
+		plan.Errors = append(plan.Errors, fmt.Sprintf("cannot connect to NetworkManager: %v", err))
+
+		return
+	}
+
+	managed, err := nm.ManagedInterfaces(nmapi, interfaces)
+	if err != nil {
+		plan.Errors = append(plan.Errors, fmt.Sprintf("cannot get NetworkManager devices: %v", err))
+	}
+
+	sort.Strings(managed)
+	plan.NetworkManagerUnmanage = managed
+
+	if config.nmConfig == "" {
+		return
+	}
+
+	unmanaged := make([]nm.UnmanagedInterface, 0, len(networkConfigs))
+	for name, nwconfig := range networkConfigs {
+		unmanaged = append(unmanaged, nm.UnmanagedInterface{
+			Name: name,
+			MAC:  nwconfig.link.Attrs().HardwareAddr.String(),
+		})
+	}
+
+	sort.Slice(unmanaged, func(i, j int) bool { return unmanaged[i].Name < unmanaged[j].Name })
+
+	path, content, err := nm.UnmanagedConfig(config.nmConfDir, config.nmConfFile, unmanaged, config.nmConfig)
+	if err != nil {
+		plan.Errors = append(plan.Errors, fmt.Sprintf("cannot create NetworkManager configuration: %v", err))
+
+		return
+	}
+
+	plan.Files = append(plan.Files, filePlan{Path: path, Content: string(content)})
+}
+
+// planFiles adds the gaudinet, systemd-networkd and NFD label files to the plan.
+func planFiles(config *cmdConfig, plan *dryRunPlan, names []string, networkConfigs map[string]*networkConfiguration) {
+	if config.mode == L3 && config.gaudinetfile != "" {
+		if content, err := GenerateGaudiNet(networkConfigs); err != nil {
+			plan.Errors = append(plan.Errors, err.Error())
+		} else {
+			plan.Files = append(plan.Files, filePlan{Path: config.gaudinetfile, Content: string(content)})
+		}
+	}
+
+	if config.mode == L3 && config.networkd != "" {
+		for _, name := range names {
+			nwconfig := networkConfigs[name]
+			if checkNetworkConfig(name, nwconfig) != nil {
+				continue
+			}
+
+			plan.Files = append(plan.Files, filePlan{
+				Path:    networkdFilename(config.networkd, name),
+				Content: networkdConfig(name, nwconfig),
+			})
+		}
+	}
+
+	if config.configure && config.keepRunning {
+		if s, err := os.Stat(nfdFeatureDir); err == nil && s.IsDir() {
+			plan.Files = append(plan.Files, filePlan{Path: nfdLabelFile, Content: nfdScaleOutReadyLabel + "\n"})
+		}
+	}
+}
+
+// planSnapshot adds the snapshot of the interface state to the plan if it
+// would change.
+func planSnapshot(config *cmdConfig, plan *dryRunPlan, networkConfigs map[string]*networkConfiguration) {
+	var nmManaged []string
+	if config.disableNM {
+		nmManaged = append([]string{}, plan.NetworkManagerUnmanage...)
+	}
+
+	snapshot, changed, err := updateSnapshot(config.snapshot, networkConfigs, nmManaged)
+	if err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+
+		return
+	}
+
+	if !changed {
+		return
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		plan.Errors = append(plan.Errors, err.Error())
+
+		return
+	}
+
+	plan.Files = append(plan.Files, filePlan{Path: config.snapshot, Content: string(content)})
+}
+
+// planAddressAllocations reads the address allocations of the node without
+// reporting the node state.
+func planAddressAllocations(config *cmdConfig, plan *dryRunPlan, networkConfigs map[string]*networkConfiguration) {
+	reporter, err := newNodeStateReporter(config.policy)
+	if err != nil {
+		plan.Errors = append(plan.Errors, fmt.Sprintf("cannot read address allocations: %v", err))
+
+		return
+	}
+
+	allocations, err := reporter.addressAllocations(config.ctx)
+	if err != nil {
+		plan.Errors = append(plan.Errors, fmt.Sprintf("cannot read address allocations: %v", err))
+
+		return
+	}
+
+	setAddressAllocations(networkConfigs, allocations)
+}
+
+// dryRun prints the changes a run with the same flags would make. Only the
+// interface state is read and LLDP listened to, nothing on the node is
+// changed.
+func dryRun(config *cmdConfig, w io.Writer) error {
+	allInterfaces, err := selectedInterfaces(config)
+	if err != nil {
+		return err
+	}
+
+	networkConfigs := getNetworkConfigs(allInterfaces)
+	if len(networkConfigs) < len(allInterfaces) {
+		return fmt.Errorf("Not all interfaces were found in the system")
+	}
+
+	if ownedAddresses, err = loadAddressRecord(config.addressRecord); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(networkConfigs))
+	for name := range networkConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	plan := &dryRunPlan{}
+	interfacePlans := map[string]*interfacePlan{}
+
+	for _, name := range names {
+		interfacePlans[name] = planInterface(config, name, networkConfigs[name])
+		plan.Interfaces = append(plan.Interfaces, interfacePlans[name])
+	}
+
+	if config.disableNM {
+		planNetworkManager(config, plan, allInterfaces, networkConfigs)
+	}
+
+	if config.snapshot != "" {
+		planSnapshot(config, plan, networkConfigs)
+	}
+
+	if config.mode == L3 {
+		parser, _ := getAddressParser(config.addressParser)
+
+		for _, nwconfig := range networkConfigs {
+			nwconfig.routedNetworks = config.parsedRoutedNetworks
+			nwconfig.addressParser = parser
+			nwconfig.mgmtFallback = config.mgmtAddressFallback
+			nwconfig.selected = networkConfigs
+		}
+
+		if config.addressSource == addressSourcePool {
+			planAddressAllocations(config, plan, networkConfigs)
+		} else {
+			detectLLDP(config, networkConfigs)
+			checkPeerMTU(networkConfigs, config.mtu)
+			lldpResults(networkConfigs)
+		}
+
+		if config.configure {
+			for _, name := range names {
+				planL3(config, interfacePlans[name], networkConfigs[name])
+			}
+		}
+	}
+
+	planFiles(config, plan, names, networkConfigs)
+
+	return writePlan(w, plan, config.dryRunFormat)
+}
+
+func writePlan(w io.Writer, plan *dryRunPlan, format string) error {
+	if format == dryRunFormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(plan)
+	}
+
+	var b strings.Builder
+
+	for _, iface := range plan.Interfaces {
+		fmt.Fprintf(&b, "Interface %s:\n", iface.Name)
+
+		if iface.SetUp {
+			b.WriteString("  set link up\n")
+		}
+
+		if iface.MTU != nil {
+			fmt.Fprintf(&b, "  set MTU %d -> %d\n", iface.MTU.Current, iface.MTU.Planned)
+		}
+
+		for _, route := range iface.RemoveRoutes {
+			fmt.Fprintf(&b, "  remove route %s\n", route)
+		}
+
+		for _, addr := range iface.RemoveAddresses {
+			fmt.Fprintf(&b, "  remove address %s\n", addr)
+		}
+
+		for _, addr := range iface.AddAddresses {
+			fmt.Fprintf(&b, "  add address %s\n", addr)
+		}
+
+		for _, route := range iface.AddRoutes {
+			fmt.Fprintf(&b, "  add route %s\n", route)
+		}
+
+		if iface.ConnectionProfile != "" {
+			fmt.Fprintf(&b, "  apply NetworkManager connection profile %s\n", iface.ConnectionProfile)
+		}
+
+		if iface.Error != "" {
+			fmt.Fprintf(&b, "  error: %s\n", iface.Error)
+		}
+	}
+
+	if len(plan.NetworkManagerUnmanage) > 0 {
+		fmt.Fprintf(&b, "Set unmanaged in NetworkManager: %s\n", strings.Join(plan.NetworkManagerUnmanage, ", "))
+	}
+
+	for _, file := range plan.Files {
+		fmt.Fprintf(&b, "Write file %s:\n", file.Path)
+
+		for _, line := range strings.Split(strings.TrimSuffix(file.Content, "\n"), "\n") {
+			fmt.Fprintf(&b, "  | %s\n", line)
+		}
+	}
+
+	for _, err := range plan.Errors {
+		fmt.Fprintf(&b, "Error: %s\n", err)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
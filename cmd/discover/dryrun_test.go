@@ -0,0 +1,206 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("SYSFS_ROOT", t.TempDir())
+
+	networkLink.LinkByName = fakeLinkByName
+	networkLink.AddrList = fakeLinkAddrList
+	networkLink.AddrAdd = func(link netlink.Link, addr *netlink.Addr) error {
+		t.Errorf("address %s added in dry-run", addr.IPNet)
+		return nil
+	}
+	networkLink.AddrDel = func(link netlink.Link, addr *netlink.Addr) error {
+		t.Errorf("address %s removed in dry-run", addr.IPNet)
+		return nil
+	}
+	networkLink.RouteList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		if link.Attrs().Name != "eth_a" {
+			return []netlink.Route{}, nil
+		}
+
+		_, routed, _ := net.ParseCIDR("10.210.0.0/16")
+		_, local, _ := net.ParseCIDR("192.192.192.0/24")
+
+		return []netlink.Route{
+			{Dst: routed, Gw: net.IPv4(10, 210, 8, 122), Protocol: routeProtocolDiscover},
+			{Dst: local, Protocol: unix.RTPROT_KERNEL},
+		}, nil
+	}
+	networkLink.RouteDel = func(route *netlink.Route) error {
+		t.Errorf("route %s removed in dry-run", route.Dst)
+		return nil
+	}
+	networkLink.LinkSetUp = func(link netlink.Link) error {
+		t.Errorf("link %s set up in dry-run", link.Attrs().Name)
+		return nil
+	}
+	networkLink.LinkSetMTU = func(link netlink.Link, mtu int) error {
+		t.Errorf("MTU of link %s set in dry-run", link.Attrs().Name)
+		return nil
+	}
+
+	config := &cmdConfig{
+		ctx:            context.Background(),
+		mode:           L2,
+		addressParser:  addressParserPortDescription,
+		mtu:            8000,
+		driver:         defaultDriver,
+		ifaces:         "eth_a,eth_b,eth_c",
+		configure:      true,
+		lldpTxInterval: 10 * time.Second,
+		dryRun:         true,
+		// the fake addresses weren't added by discover
+		flushAddresses: true,
+	}
+
+	if err := sanitizeInput(config); err != nil {
+		t.Fatalf("sanitizing input failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := dryRun(config, &out); err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+
+	for _, line := range []string{
+		"Interface eth_a:\n  set link up\n  set MTU 0 -> 8000\n  remove route 10.210.0.0/16 via 10.210.8.122\n  remove address 192.192.192.1/24\n",
+		"Interface eth_c:\n  set link up\n  set MTU 0 -> 8000\n  remove address 10.210.8.125/30\n",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("plan does not contain %q:\n%s", line, out.String())
+		}
+	}
+
+	config.dryRunFormat = dryRunFormatJSON
+	out.Reset()
+
+	if err := dryRun(config, &out); err != nil {
+		t.Fatalf("dry-run failed: %v", err)
+	}
+
+	plan := dryRunPlan{}
+	if err := json.Unmarshal(out.Bytes(), &plan); err != nil {
+		t.Fatalf("cannot parse JSON plan: %v", err)
+	}
+
+	if len(plan.Interfaces) != 3 || plan.Interfaces[1].Name != "eth_b" ||
+		len(plan.Interfaces[1].RemoveAddresses) != 0 || plan.Interfaces[1].MTU.Planned != 8000 ||
+		!slices.Equal(plan.Interfaces[0].RemoveRoutes, []string{"10.210.0.0/16 via 10.210.8.122"}) {
+		t.Errorf("unexpected JSON plan %s", out.String())
+	}
+}
+
+func TestDryRunL3Plan(t *testing.T) {
+	networkLink.AddrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return []netlink.Addr{}, nil
+	}
+
+	networkd := filepath.Join(t.TempDir(), "network")
+	gaudinet := filepath.Join(t.TempDir(), "gaudinet.json")
+
+	config := &cmdConfig{
+		mode:         L3,
+		mtu:          1500,
+		configure:    true,
+		backend:      backendNetworkManager,
+		networkd:     networkd,
+		gaudinetfile: gaudinet,
+	}
+
+	networkConfigs := getFakeNetworkDataConfigs()
+	lldpResults(networkConfigs)
+
+	names := []string{"eth_a", "eth_b", "eth_c"}
+	plan := &dryRunPlan{}
+
+	for _, name := range names {
+		iface := planInterface(config, name, networkConfigs[name])
+		planL3(config, iface, networkConfigs[name])
+		plan.Interfaces = append(plan.Interfaces, iface)
+	}
+
+	ethA := plan.Interfaces[0]
+	if !slices.Equal(ethA.AddAddresses, []string{"10.210.8.121/30"}) ||
+		!slices.Equal(ethA.AddRoutes, []string{"10.210.0.0/16 via 10.210.8.122"}) ||
+		ethA.ConnectionProfile == "" {
+		t.Errorf("unexpected plan for eth_a: %+v", ethA)
+	}
+
+	if ethB := plan.Interfaces[1]; len(ethB.AddAddresses) != 0 || ethB.Error == "" {
+		t.Errorf("unexpected plan for eth_b: %+v", ethB)
+	}
+
+	planFiles(config, plan, names, networkConfigs)
+
+	paths := []string{}
+	for _, file := range plan.Files {
+		paths = append(paths, file.Path)
+	}
+
+	expected := []string{gaudinet, networkdFilename(networkd, "eth_a"), networkdFilename(networkd, "eth_c")}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("expected files %v, got %v", expected, paths)
+	}
+
+	if !strings.Contains(plan.Files[1].Content, "Address=10.210.8.121/30") {
+		t.Errorf("unexpected networkd configuration:\n%s", plan.Files[1].Content)
+	}
+
+	for _, path := range []string{networkd, gaudinet} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("'%s' written in dry-run", path)
+		}
+	}
+}
+
+func TestSanitizeDryRunFormat(t *testing.T) {
+	config := &cmdConfig{
+		mode:           L2,
+		addressParser:  addressParserPortDescription,
+		mtu:            1500,
+		driver:         defaultDriver,
+		lldpTxInterval: 10 * time.Second,
+		dryRunFormat:   "yaml",
+	}
+
+	if err := sanitizeInput(config); err == nil {
+		t.Error("unknown dry-run format accepted")
+	}
+
+	config.dryRunFormat = ""
+	if err := sanitizeInput(config); err != nil || config.dryRunFormat != dryRunFormatText {
+		t.Errorf("unexpected dry-run format '%s': %v", config.dryRunFormat, err)
+	}
+}
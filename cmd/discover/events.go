@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+const (
+	eventComponent = "intel-network-linkdiscovery"
+
+	reasonLLDPTimeout                 = "LLDPTimeout"
+	reasonInvalidPeerAddress          = "InvalidPeerAddress"
+	reasonAddressConfigFailed         = "AddressConfigurationFailed"
+	reasonRouteConfigFailed           = "RouteConfigurationFailed"
+	reasonNetworkManagerDisableFailed = "NetworkManagerDisableFailed"
+	reasonNetworkManagerRestoreFailed = "NetworkManagerRestoreFailed"
+)
+
+// nodeEventRecorder emits events for the node the daemon runs on. Events
+// are dropped when no recorder is set up.
+type nodeEventRecorder struct {
+	recorder record.EventRecorder
+	node     *corev1.ObjectReference
+	stop     func()
+}
+
+var nodeEvents = &nodeEventRecorder{}
+
+func newNodeEventRecorder() (*nodeEventRecorder, error) {
+	nodeName := os.Getenv("NODE_NAME")
+	if nodeName == "" {
+		return nil, fmt.Errorf("NODE_NAME needs to be set for node events")
+	}
+
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get in-cluster config: %v", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create clientset: %v", err)
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+
+	return &nodeEventRecorder{
+		recorder: broadcaster.NewRecorder(runtime.NewScheme(), corev1.EventSource{Component: eventComponent, Host: nodeName}),
+		// events for nodes are recorded in the default namespace, like the
+		// kubelet does, with the node name as the UID
+		node: &corev1.ObjectReference{
+			Kind: "Node",
+			Name: nodeName,
+			UID:  types.UID(nodeName),
+		},
+		stop: broadcaster.Shutdown,
+	}, nil
+}
+
+func (e *nodeEventRecorder) warningf(reason, messageFmt string, args ...interface{}) {
+	if e.recorder == nil {
+		return
+	}
+
+	e.recorder.Eventf(e.node, corev1.EventTypeWarning, reason, messageFmt, args...)
+}
+
+// shutdown flushes the pending events.
+func (e *nodeEventRecorder) shutdown() {
+	if e.stop != nil {
+		e.stop()
+	}
+}
+
+func setupNodeEvents() {
+	recorder, err := newNodeEventRecorder()
+	if err != nil {
+		klog.Warningf("Node events are disabled: %v", err)
+		return
+	}
+
+	nodeEvents = recorder
+}
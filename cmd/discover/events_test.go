@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func fakeNodeEvents(t *testing.T) *record.FakeRecorder {
+	recorder := record.NewFakeRecorder(10)
+
+	nodeEvents = &nodeEventRecorder{
+		recorder: recorder,
+		node:     &corev1.ObjectReference{Kind: "Node", Name: "node"},
+	}
+
+	t.Cleanup(func() { nodeEvents = &nodeEventRecorder{} })
+
+	return recorder
+}
+
+func expectEvent(t *testing.T, recorder *record.FakeRecorder, reason string) {
+	t.Helper()
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.HasPrefix(event, corev1.EventTypeWarning+" "+reason+" ") {
+			t.Errorf("expected a %s event, got '%s'", reason, event)
+		}
+	default:
+		t.Errorf("expected a %s event", reason)
+	}
+}
+
+func TestNodeEventsDisabled(t *testing.T) {
+	// events are dropped without a recorder
+	nodeEvents.warningf(reasonLLDPTimeout, "no LLDP")
+	nodeEvents.shutdown()
+}
+
+func TestConfigureInterfacesEvents(t *testing.T) {
+	recorder := fakeNodeEvents(t)
+
+	networkLink.AddrList = fakeLinkAddrList
+	networkLink.AddrAdd = fakeLinkAddrAddErr
+
+	nwconfigs := getFakeNetworkDataConfigs()
+	nwconfig := nwconfigs["eth_a"]
+
+	if !lldpResults(map[string]*networkConfiguration{"eth_a": nwconfig}) {
+		t.Fatal("expected an LLDP peer for eth_a")
+	}
+
+	configureInterfaces(map[string]*networkConfiguration{"eth_a": nwconfig})
+
+	expectEvent(t, recorder, reasonAddressConfigFailed)
+}
+
+func TestLLDPResultsEvents(t *testing.T) {
+	recorder := fakeNodeEvents(t)
+
+	nwconfigs := getFakeNetworkDataConfigs()
+
+	// LLDP received with an unexpected port description
+	_ = lldpResults(map[string]*networkConfiguration{"eth_b": nwconfigs["eth_b"]})
+
+	expectEvent(t, recorder, reasonInvalidPeerAddress)
+
+	// no LLDP received, reported as a timeout instead
+	nwconfigs["eth_b"].peerHWAddr = nil
+	_ = lldpResults(map[string]*networkConfiguration{"eth_b": nwconfigs["eth_b"]})
+
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("unexpected event '%s'", event)
+	default:
+	}
+}
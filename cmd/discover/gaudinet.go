@@ -19,6 +19,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 
@@ -57,14 +58,14 @@ func GenerateGaudiNet(networkConfigs map[string]*networkConfiguration) ([]byte,
 			continue
 		}
 
-		net := GaudiNetEntry{
+		entry := GaudiNetEntry{
 			Mac:        nwconfig.link.Attrs().HardwareAddr.String(),
 			IP:         nwconfig.localAddr.String(),
-			Mask:       "255.255.255.252",
+			Mask:       net.IP(pointToPointMask(nwconfig)).String(),
 			GatewayMac: nwconfig.peerHWAddr.String(),
 		}
 
-		gaudinet.Config = append(gaudinet.Config, net)
+		gaudinet.Config = append(gaudinet.Config, entry)
 	}
 
 	gaudinetContents, err := JsonMarshal(gaudinet)
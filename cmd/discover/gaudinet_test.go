@@ -58,6 +58,25 @@ func TestGenerateGaudiNet(t *testing.T) {
 	}
 }
 
+func TestGenerateGaudiNetIPv6(t *testing.T) {
+	nwconfigs, _ := fakenetworkconfigs()
+
+	lldpPeer := net.ParseIP("fd00:10:120::1")
+	localAddr := net.ParseIP("fd00:10:120::")
+	nwconfigs["eth1234"].lldpPeer = &lldpPeer
+	nwconfigs["eth1234"].localAddr = &localAddr
+	nwconfigs["eth1234"].prefixLen = 127
+
+	expectedoutput := "{\"NIC_NET_CONFIG\":[{\"NIC_MAC\":\"01:02:03:04:05:06\"," +
+		"\"NIC_IP\":\"fd00:10:120::\",\"SUBNET_MASK\":\"ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe\"," +
+		"\"GATEWAY_MAC\":\"06:05:04:03:02:01\"}]}"
+
+	json, err := GenerateGaudiNet(nwconfigs)
+	if string(json) != expectedoutput {
+		t.Errorf("Expected result '%s', returned '%s': %v", expectedoutput, json, err)
+	}
+}
+
 func TestGenerateGaudiNetMissingLocalAddr(t *testing.T) {
 	nwconfigs, _ := fakenetworkconfigs()
 
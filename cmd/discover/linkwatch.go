@@ -0,0 +1,249 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// time to wait for further netlink events before re-applying the
+	// configuration, a driver reload produces a burst of events
+	linkWatchSettleTime = 2 * time.Second
+)
+
+// linkWatcher follows netlink link, address and route events of the
+// configured interfaces and re-applies the configuration when a link comes
+// back up or the configured addresses or routes are removed.
+type linkWatcher struct {
+	config         *cmdConfig
+	networkConfigs map[string]*networkConfiguration
+
+	linkUpdates  chan netlink.LinkUpdate
+	addrUpdates  chan netlink.AddrUpdate
+	routeUpdates chan netlink.RouteUpdate
+	done         chan struct{}
+
+	// interfaces to re-apply the configuration for
+	dirty map[string]bool
+	// interfaces whose link is down or missing
+	degraded map[string]bool
+}
+
+func newLinkWatcher(config *cmdConfig, networkConfigs map[string]*networkConfiguration) (*linkWatcher, error) {
+	w := &linkWatcher{
+		config:         config,
+		networkConfigs: networkConfigs,
+		linkUpdates:    make(chan netlink.LinkUpdate, 16),
+		addrUpdates:    make(chan netlink.AddrUpdate, 16),
+		routeUpdates:   make(chan netlink.RouteUpdate, 16),
+		done:           make(chan struct{}),
+		dirty:          map[string]bool{},
+		degraded:       map[string]bool{},
+	}
+
+	// start from the current link state, the configuration may hold the
+	// state from before the interfaces were set up
+	for ifname, nwconfig := range networkConfigs {
+		if link, err := networkLink.LinkByName(ifname); err == nil {
+			nwconfig.link = link
+		}
+	}
+
+	if err := networkLink.LinkSubscribe(w.linkUpdates, w.done); err != nil {
+		close(w.done)
+		return nil, fmt.Errorf("cannot subscribe to link updates: %v", err)
+	}
+
+	if err := networkLink.AddrSubscribe(w.addrUpdates, w.done); err != nil {
+		close(w.done)
+		return nil, fmt.Errorf("cannot subscribe to address updates: %v", err)
+	}
+
+	if err := networkLink.RouteSubscribe(w.routeUpdates, w.done); err != nil {
+		close(w.done)
+		return nil, fmt.Errorf("cannot subscribe to route updates: %v", err)
+	}
+
+	return w, nil
+}
+
+func (w *linkWatcher) stop() {
+	close(w.done)
+}
+
+func (w *linkWatcher) configByIndex(index int) *networkConfiguration {
+	for _, nwconfig := range w.networkConfigs {
+		if nwconfig.link.Attrs().Index == index {
+			return nwconfig
+		}
+	}
+
+	return nil
+}
+
+func linkUp(link netlink.Link) bool {
+	attrs := link.Attrs()
+
+	return attrs.Flags&net.FlagUp != 0 &&
+		(attrs.OperState == netlink.OperUp || attrs.OperState == netlink.OperUnknown)
+}
+
+// handleLinkUpdate returns true when the update concerns a watched interface.
+func (w *linkWatcher) handleLinkUpdate(update netlink.LinkUpdate) bool {
+	ifname := update.Link.Attrs().Name
+
+	nwconfig, exists := w.networkConfigs[ifname]
+	if !exists {
+		return false
+	}
+
+	if update.Header.Type == unix.RTM_DELLINK {
+		klog.Warningf("Interface '%s' was removed", ifname)
+
+		w.degraded[ifname] = true
+
+		return true
+	}
+
+	if nwconfig.link.Attrs().Index != update.Link.Attrs().Index {
+		// driver was rebound, the interface re-appeared with a new index
+		klog.Infof("Interface '%s' re-appeared with index %d", ifname, update.Link.Attrs().Index)
+
+		hwAddr := update.Link.Attrs().HardwareAddr
+		nwconfig.localHwAddr = &hwAddr
+	}
+
+	wasUp := linkUp(nwconfig.link)
+	nwconfig.link = update.Link
+
+	if !linkUp(update.Link) {
+		if !w.degraded[ifname] {
+			klog.Warningf("Interface '%s' is down", ifname)
+		}
+
+		w.degraded[ifname] = true
+		// an administratively down interface needs to be set up again
+		if update.Link.Attrs().Flags&net.FlagUp == 0 {
+			w.dirty[ifname] = true
+		}
+
+		return true
+	}
+
+	if !wasUp || w.degraded[ifname] {
+		klog.Infof("Interface '%s' is up", ifname)
+
+		w.dirty[ifname] = true
+	}
+
+	return true
+}
+
+// handleAddrUpdate returns true when a configured address was removed.
+func (w *linkWatcher) handleAddrUpdate(update netlink.AddrUpdate) bool {
+	if update.NewAddr {
+		return false
+	}
+
+	nwconfig := w.configByIndex(update.LinkIndex)
+	if nwconfig == nil || nwconfig.localAddr == nil || !update.LinkAddress.IP.Equal(*nwconfig.localAddr) {
+		return false
+	}
+
+	klog.Warningf("Address %s was removed from interface '%s'", update.LinkAddress.String(), nwconfig.link.Attrs().Name)
+
+	w.dirty[nwconfig.link.Attrs().Name] = true
+
+	return true
+}
+
+// handleRouteUpdate returns true when a route via the LLDP peer was removed.
+func (w *linkWatcher) handleRouteUpdate(update netlink.RouteUpdate) bool {
+	if update.Type != unix.RTM_DELROUTE {
+		return false
+	}
+
+	nwconfig := w.configByIndex(update.LinkIndex)
+	if nwconfig == nil || nwconfig.lldpPeer == nil || !update.Gw.Equal(*nwconfig.lldpPeer) {
+		return false
+	}
+
+	klog.Warningf("Route %s was removed from interface '%s'", update.Dst, nwconfig.link.Attrs().Name)
+
+	w.dirty[nwconfig.link.Attrs().Name] = true
+
+	return true
+}
+
+// reapply re-applies the configuration of the interfaces marked dirty and
+// returns true when the state of any interface changed.
+func (w *linkWatcher) reapply() bool {
+	changed := false
+
+	for ifname := range w.dirty {
+		delete(w.dirty, ifname)
+
+		nwconfig := w.networkConfigs[ifname]
+
+		if nwconfig.link.Attrs().Flags&net.FlagUp == 0 {
+			if err := networkLink.LinkSetUp(nwconfig.link); err != nil {
+				klog.Warningf("Cannot set link '%s' up: %v", ifname, err)
+			}
+			// the link update will mark the interface dirty again
+			continue
+		}
+
+		if err := networkLink.LinkSetMTU(nwconfig.link, w.config.mtu); err != nil {
+			klog.Warningf("Could not set MTU %d for interface '%s': %v", w.config.mtu, ifname, err)
+		}
+
+		if w.degraded[ifname] {
+			delete(w.degraded, ifname)
+			nwconfig.configErr = nil
+			changed = true
+		}
+
+		if w.config.mode == L3 && nwconfig.localAddr != nil {
+			klog.Infof("Re-applying configuration for interface '%s'", ifname)
+
+			configureInterfaces(map[string]*networkConfiguration{ifname: nwconfig})
+
+			changed = true
+		}
+	}
+
+	for ifname := range w.degraded {
+		nwconfig := w.networkConfigs[ifname]
+		if nwconfig.configErr == nil {
+			nwconfig.configErr = fmt.Errorf("link is down")
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func (w *linkWatcher) isDegraded() bool {
+	return len(w.degraded) > 0
+}
@@ -0,0 +1,235 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func fakeWatchedLink(name string, index int, flags net.Flags, state netlink.LinkOperState) *fakeLink {
+	return &fakeLink{
+		fakeAttrs: netlink.LinkAttrs{
+			Name:         name,
+			Index:        index,
+			Flags:        flags,
+			OperState:    state,
+			HardwareAddr: net.HardwareAddr{0x0a, 0x0b, 0x0c, 0x0d, 0x0e, byte(index)},
+		},
+	}
+}
+
+func newFakeLinkWatcher(t *testing.T) *linkWatcher {
+	localAddr := net.IPv4(10, 210, 8, 121)
+	peerAddr := net.IPv4(10, 210, 8, 122)
+
+	nwconfigs := map[string]*networkConfiguration{
+		"eth_a": {
+			link:      fakeWatchedLink("eth_a", 1, net.FlagUp, netlink.OperUp),
+			localAddr: &localAddr,
+			lldpPeer:  &peerAddr,
+			prefixLen: 30,
+		},
+		"eth_b": {
+			link: fakeWatchedLink("eth_b", 2, net.FlagUp, netlink.OperUp),
+		},
+	}
+
+	networkLink.LinkByName = func(name string) (netlink.Link, error) {
+		if nwconfig, ok := nwconfigs[name]; ok {
+			return nwconfig.link, nil
+		}
+		return nil, fmt.Errorf("no link '%s'", name)
+	}
+	networkLink.LinkSubscribe = func(ch chan<- netlink.LinkUpdate, done <-chan struct{}) error {
+		return nil
+	}
+	networkLink.AddrSubscribe = func(ch chan<- netlink.AddrUpdate, done <-chan struct{}) error {
+		return nil
+	}
+	networkLink.RouteSubscribe = func(ch chan<- netlink.RouteUpdate, done <-chan struct{}) error {
+		return nil
+	}
+
+	w, err := newLinkWatcher(&cmdConfig{mode: L3, mtu: 8000}, nwconfigs)
+	if err != nil {
+		t.Fatalf("cannot create link watcher: %v", err)
+	}
+
+	return w
+}
+
+func linkUpdate(msgType uint16, link netlink.Link) netlink.LinkUpdate {
+	update := netlink.LinkUpdate{Link: link}
+	update.Header.Type = msgType
+
+	return update
+}
+
+func TestLinkWatcherSubscribeErrors(t *testing.T) {
+	_ = newFakeLinkWatcher(t)
+
+	networkLink.RouteSubscribe = func(ch chan<- netlink.RouteUpdate, done <-chan struct{}) error {
+		return fmt.Errorf("no route updates")
+	}
+
+	if _, err := newLinkWatcher(&cmdConfig{}, map[string]*networkConfiguration{}); err == nil {
+		t.Error("link watcher created without route updates")
+	}
+
+	networkLink.AddrSubscribe = func(ch chan<- netlink.AddrUpdate, done <-chan struct{}) error {
+		return fmt.Errorf("no address updates")
+	}
+
+	if _, err := newLinkWatcher(&cmdConfig{}, map[string]*networkConfiguration{}); err == nil {
+		t.Error("link watcher created without address updates")
+	}
+}
+
+func TestLinkWatcherFlap(t *testing.T) {
+	w := newFakeLinkWatcher(t)
+	defer w.stop()
+
+	var addrsAdded []*netlink.Addr
+
+	networkLink.LinkSetMTU = func(link netlink.Link, mtu int) error {
+		return nil
+	}
+	networkLink.AddrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return nil, nil
+	}
+	networkLink.AddrAdd = func(link netlink.Link, addr *netlink.Addr) error {
+		addrsAdded = append(addrsAdded, addr)
+		return nil
+	}
+	networkLink.RouteAppend = func(route *netlink.Route) error {
+		return nil
+	}
+
+	if w.handleLinkUpdate(linkUpdate(unix.RTM_NEWLINK, fakeWatchedLink("eth_x", 9, net.FlagUp, netlink.OperUp))) {
+		t.Error("update for an unknown interface was handled")
+	}
+
+	// carrier lost
+	if !w.handleLinkUpdate(linkUpdate(unix.RTM_NEWLINK, fakeWatchedLink("eth_a", 1, net.FlagUp, netlink.OperDown))) {
+		t.Fatal("link down was not handled")
+	}
+
+	if !w.reapply() || !w.isDegraded() || w.networkConfigs["eth_a"].configErr == nil {
+		t.Fatal("interface with a link down should be degraded")
+	}
+
+	if len(addrsAdded) != 0 {
+		t.Errorf("configuration applied to a down link: %v", addrsAdded)
+	}
+
+	// carrier back
+	if !w.handleLinkUpdate(linkUpdate(unix.RTM_NEWLINK, fakeWatchedLink("eth_a", 1, net.FlagUp, netlink.OperUp))) {
+		t.Fatal("link up was not handled")
+	}
+
+	if !w.reapply() || w.isDegraded() || w.networkConfigs["eth_a"].configErr != nil {
+		t.Error("interface with a link up should not be degraded")
+	}
+
+	if len(addrsAdded) != 1 || !addrsAdded[0].IP.Equal(net.IPv4(10, 210, 8, 121)) {
+		t.Errorf("expected the address to be re-applied, got %v", addrsAdded)
+	}
+
+	// nothing changed since
+	if w.reapply() {
+		t.Error("reapply without events reported changes")
+	}
+}
+
+func TestLinkWatcherDriverRebind(t *testing.T) {
+	w := newFakeLinkWatcher(t)
+	defer w.stop()
+
+	var setUp []string
+
+	networkLink.LinkSetUp = func(link netlink.Link) error {
+		setUp = append(setUp, link.Attrs().Name)
+		return nil
+	}
+
+	if !w.handleLinkUpdate(linkUpdate(unix.RTM_DELLINK, w.networkConfigs["eth_b"].link)) {
+		t.Fatal("link removal was not handled")
+	}
+
+	// interface re-appears administratively down with a new index
+	if !w.handleLinkUpdate(linkUpdate(unix.RTM_NEWLINK, fakeWatchedLink("eth_b", 12, 0, netlink.OperDown))) {
+		t.Fatal("new link was not handled")
+	}
+
+	if w.networkConfigs["eth_b"].link.Attrs().Index != 12 {
+		t.Error("link was not replaced")
+	}
+
+	w.reapply()
+
+	if len(setUp) != 1 || setUp[0] != "eth_b" {
+		t.Errorf("expected eth_b to be set up, got %v", setUp)
+	}
+
+	if !w.isDegraded() {
+		t.Error("interface should be degraded until the link is up")
+	}
+}
+
+func TestLinkWatcherAddrRouteRemoval(t *testing.T) {
+	w := newFakeLinkWatcher(t)
+	defer w.stop()
+
+	localAddr := netlink.AddrUpdate{
+		LinkAddress: net.IPNet{IP: net.IPv4(10, 210, 8, 121), Mask: net.CIDRMask(30, 32)},
+		LinkIndex:   1,
+	}
+
+	if w.handleAddrUpdate(netlink.AddrUpdate{LinkAddress: localAddr.LinkAddress, LinkIndex: 1, NewAddr: true}) {
+		t.Error("new address was handled")
+	}
+
+	if w.handleAddrUpdate(netlink.AddrUpdate{LinkAddress: localAddr.LinkAddress, LinkIndex: 2}) {
+		t.Error("address removal on another interface was handled")
+	}
+
+	if !w.handleAddrUpdate(localAddr) || !w.dirty["eth_a"] {
+		t.Error("address removal was not handled")
+	}
+
+	delete(w.dirty, "eth_a")
+
+	_, dst, _ := net.ParseCIDR("10.210.0.0/16")
+
+	route := netlink.RouteUpdate{
+		Type:  unix.RTM_DELROUTE,
+		Route: netlink.Route{LinkIndex: 1, Dst: dst, Gw: net.IPv4(10, 210, 8, 122)},
+	}
+
+	if w.handleRouteUpdate(netlink.RouteUpdate{Type: unix.RTM_NEWROUTE, Route: route.Route}) {
+		t.Error("new route was handled")
+	}
+
+	if !w.handleRouteUpdate(route) || !w.dirty["eth_a"] {
+		t.Error("route removal was not handled")
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/intel/network-operator/pkg/lldp"
+)
+
+const (
+	// LLDP TTL is by default four times the transmit interval
+	lldpTxHoldMultiplier = 4
+)
+
+func lldpAdvertisement(nwconfig *networkConfiguration, hostname, nodeName string) lldp.Advertisement {
+	adv := lldp.Advertisement{
+		ChassisID:       nodeName,
+		SysName:         hostname,
+		SysDescription:  "network-operator",
+		PortDescription: nwconfig.link.Attrs().Name,
+		InterfaceIndex:  nwconfig.link.Attrs().Index,
+	}
+
+	if nodeName == "" {
+		adv.ChassisID = hostname
+	} else {
+		adv.SysDescription = fmt.Sprintf("Kubernetes node %s", nodeName)
+	}
+
+	if nwconfig.localAddr != nil {
+		adv.MgmtAddresses = []net.IP{*nwconfig.localAddr}
+	}
+
+	return adv
+}
+
+// lldpTransmitter is a running transmitter and the link it was started on.
+type lldpTransmitter struct {
+	*lldp.Transmitter
+	ifindex int
+	cancel  context.CancelFunc
+}
+
+// transmitLLDP starts LLDP transmitters for all interfaces that are up.
+// The first returned function updates the advertisement of an interface
+// after its configuration has changed. It also starts the transmitter of an
+// interface that has come up since and restarts the one of an interface that
+// has been recreated. The second one stops the transmitters and waits for
+// them to send their shutdown frames.
+func transmitLLDP(config *cmdConfig, networkConfigs map[string]*networkConfiguration) (func(*networkConfiguration), func()) {
+	var wg sync.WaitGroup
+
+	transmitters := map[string]*lldpTransmitter{}
+
+	ctx, cancel := context.WithCancel(config.ctx)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		klog.Warningf("Could not get hostname for LLDP: %v", err)
+	}
+	nodeName := os.Getenv("NODE_NAME")
+
+	start := func(nwconfig *networkConfiguration) bool {
+		ifname := nwconfig.link.Attrs().Name
+
+		link, err := networkLink.LinkByName(ifname)
+		if err != nil || link.Attrs().Flags&net.FlagUp == 0 {
+			return false
+		}
+
+		txctx, txcancel := context.WithCancel(ctx)
+
+		transmitter := &lldpTransmitter{
+			Transmitter: lldp.NewTransmitter(txctx, ifname, *nwconfig.localHwAddr,
+				config.lldpTxInterval, config.lldpTTL),
+			ifindex: link.Attrs().Index,
+			cancel:  txcancel,
+		}
+		transmitter.SetAdvertisement(lldpAdvertisement(nwconfig, hostname, nodeName))
+		transmitters[ifname] = transmitter
+
+		wg.Add(1)
+		go func() {
+			if err := transmitter.Start(); err != nil {
+				klog.Warningf("LLDP transmit failed: %v", err)
+			}
+			wg.Done()
+		}()
+
+		klog.Infof("Started LLDP transmit for '%s'", ifname)
+
+		return true
+	}
+
+	for _, nwconfig := range networkConfigs {
+		if !start(nwconfig) {
+			klog.Infof("Link '%s' is not up, cannot start LLDP transmit", nwconfig.link.Attrs().Name)
+		}
+	}
+
+	update := func(nwconfig *networkConfiguration) {
+		ifname := nwconfig.link.Attrs().Name
+
+		transmitter, ok := transmitters[ifname]
+		if ok && transmitter.ifindex == nwconfig.link.Attrs().Index {
+			transmitter.SetAdvertisement(lldpAdvertisement(nwconfig, hostname, nodeName))
+			return
+		}
+
+		if ok {
+			klog.Infof("Link '%s' was recreated, restarting LLDP transmit", ifname)
+
+			transmitter.cancel()
+			delete(transmitters, ifname)
+		}
+
+		start(nwconfig)
+	}
+
+	stop := func() {
+		cancel()
+		wg.Wait()
+	}
+
+	return update, stop
+}
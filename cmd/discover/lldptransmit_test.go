@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+func TestLLDPAdvertisement(t *testing.T) {
+	nwconfigs := getFakeNetworkDataConfigs()
+	_ = lldpResults(nwconfigs)
+
+	adv := lldpAdvertisement(nwconfigs["eth_a"], "host-1", "node-1")
+	if adv.ChassisID != "node-1" || adv.SysName != "host-1" || adv.PortDescription != "eth_a" ||
+		adv.SysDescription != "Kubernetes node node-1" {
+		t.Errorf("unexpected advertisement %+v", adv)
+	}
+	if len(adv.MgmtAddresses) != 1 || adv.MgmtAddresses[0].String() != "10.210.8.121" {
+		t.Errorf("unexpected management addresses %v", adv.MgmtAddresses)
+	}
+
+	adv = lldpAdvertisement(nwconfigs["eth_b"], "host-1", "")
+	if adv.ChassisID != "host-1" || len(adv.MgmtAddresses) != 0 {
+		t.Errorf("unexpected advertisement %+v", adv)
+	}
+}
+
+func TestSanitizeLLDPTransmit(t *testing.T) {
+	config := &cmdConfig{
+		mode:           L3,
+		mtu:            1500,
+		addressParser:  addressParserPortDescription,
+		driver:         defaultDriver,
+		lldpTxInterval: 10 * time.Second,
+		lldpTTL:        5 * time.Second,
+	}
+
+	if err := sanitizeInput(config); err != nil {
+		t.Fatalf("sanitizing input failed: %v", err)
+	}
+	if config.lldpTTL != 40*time.Second {
+		t.Errorf("expected TTL 40s, got %v", config.lldpTTL)
+	}
+
+	config.lldpTxInterval = 0
+	if err := sanitizeInput(config); err == nil {
+		t.Error("zero transmit interval accepted")
+	}
+}
+
+func TestTransmitLLDPLinkUp(t *testing.T) {
+	link := fakeWatchedLink("eth_x", 1, 0, netlink.OperDown)
+	hwAddr := link.Attrs().HardwareAddr
+	nwconfig := &networkConfiguration{link: link, localHwAddr: &hwAddr}
+
+	lookups := 0
+	networkLink.LinkByName = func(name string) (netlink.Link, error) {
+		lookups++
+		return nwconfig.link, nil
+	}
+
+	config := &cmdConfig{
+		ctx:            context.Background(),
+		lldpTxInterval: time.Hour,
+		lldpTTL:        4 * time.Hour,
+	}
+
+	update, stop := transmitLLDP(config, map[string]*networkConfiguration{"eth_x": nwconfig})
+	defer stop()
+
+	// the link is down, nothing is started and every update checks again
+	update(nwconfig)
+	if lookups != 2 {
+		t.Errorf("expected 2 link lookups while down, got %d", lookups)
+	}
+
+	nwconfig.link = fakeWatchedLink("eth_x", 1, net.FlagUp, netlink.OperUp)
+	update(nwconfig)
+	update(nwconfig)
+	if lookups != 3 {
+		t.Errorf("expected the transmitter to start once after the link came up, got %d lookups", lookups)
+	}
+
+	// a recreated link gets a new transmitter
+	nwconfig.link = fakeWatchedLink("eth_x", 7, net.FlagUp, netlink.OperUp)
+	update(nwconfig)
+	update(nwconfig)
+	if lookups != 4 {
+		t.Errorf("expected the transmitter to restart once after the link was recreated, got %d lookups", lookups)
+	}
+}
@@ -23,12 +23,14 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/vishvananda/netlink"
 	"k8s.io/klog/v2"
 
 	"github.com/intel/network-operator/pkg/lldp"
@@ -43,19 +45,52 @@ const (
 	nfdFeatureDir         = "/etc/kubernetes/node-feature-discovery/features.d/"
 	nfdLabelFile          = nfdFeatureDir + "scale-out-readiness.txt"
 	nfdScaleOutReadyLabel = "intel.feature.node.kubernetes.io/gaudi-scale-out=true"
+
+	nodeStateRetryInterval = 30 * time.Second
 )
 
 type cmdConfig struct {
-	ctx          context.Context
-	timeout      time.Duration
-	configure    bool
-	disableNM    bool
-	gaudinetfile string
-	ifaces       string
-	mode         string
-	keepRunning  bool
-	networkd     string
-	mtu          int
+	ctx                  context.Context
+	timeout              time.Duration
+	configure            bool
+	disableNM            bool
+	nmRecord             string
+	nmConfig             string
+	nmConfDir            string
+	nmConfFile           string
+	backend              string
+	addressSource        string
+	dryRun               bool
+	flushAddresses       bool
+	snapshot             string
+	addressRecord        string
+	output               string
+	reportFile           string
+	dryRunFormat         string
+	gaudinetfile         string
+	ifaces               string
+	mode                 string
+	keepRunning          bool
+	networkd             string
+	mtu                  int
+	policy               string
+	routedNetworks       []string
+	parsedRoutedNetworks []routedNetwork
+	addressParser        string
+	mgmtAddressFallback  bool
+	lldpTransmit         bool
+	lldpTxInterval       time.Duration
+	lldpTTL              time.Duration
+	probeInterval        time.Duration
+	metricsAddr          string
+	metricsSecure        bool
+	driver               string
+	pciIDs               []string
+	parsedPCIIDs         []pciID
+	includeFlags         interfaceFilterFlags
+	excludeFlags         interfaceFilterFlags
+	include              interfaceFilter
+	exclude              interfaceFilter
 }
 
 func sanitizeInput(config *cmdConfig) error {
@@ -78,6 +113,102 @@ func sanitizeInput(config *cmdConfig) error {
 		return fmt.Errorf("Invalid mode '%s'", config.mode)
 	}
 
+	routedNetworks, err := parseRoutedNetworks(config.routedNetworks)
+	if err != nil {
+		return err
+	}
+
+	config.parsedRoutedNetworks = routedNetworks
+
+	pciIDs, err := parsePCIIDs(config.pciIDs)
+	if err != nil {
+		return err
+	}
+
+	config.parsedPCIIDs = pciIDs
+
+	if config.include, err = parseInterfaceFilter(config.includeFlags); err != nil {
+		return err
+	}
+
+	if config.exclude, err = parseInterfaceFilter(config.excludeFlags); err != nil {
+		return err
+	}
+
+	if config.driver == "" && len(config.parsedPCIIDs) == 0 {
+		return fmt.Errorf("Either a driver or PCI IDs are needed to select the interfaces")
+	}
+
+	switch config.nmConfig {
+	case "", nm.MatchInterfaceName, nm.MatchMAC:
+	default:
+		return fmt.Errorf("Invalid NetworkManager configuration match '%s'", config.nmConfig)
+	}
+
+	if config.nmConfig != "" && !config.disableNM {
+		return fmt.Errorf("NetworkManager configuration needs NetworkManager to be disabled for the interfaces")
+	}
+
+	switch config.backend {
+	case "":
+		config.backend = backendNetlink
+	case backendNetlink, backendNetworkManager:
+	default:
+		return fmt.Errorf("Invalid backend '%s'", config.backend)
+	}
+
+	switch config.addressSource {
+	case "":
+		config.addressSource = addressSourceLLDP
+	case addressSourceLLDP:
+	case addressSourcePool:
+		if config.mode != L3 || config.policy == "" {
+			return fmt.Errorf("Address pool needs L3 mode and a policy to read the allocations from")
+		}
+	default:
+		return fmt.Errorf("Invalid address source '%s'", config.addressSource)
+	}
+
+	switch config.output {
+	case "":
+		if config.reportFile != "" {
+			config.output = outputJSON
+		}
+	case outputJSON, outputYAML:
+	default:
+		return fmt.Errorf("Invalid output format '%s'", config.output)
+	}
+
+	switch config.dryRunFormat {
+	case "":
+		config.dryRunFormat = dryRunFormatText
+	case dryRunFormatText, dryRunFormatJSON:
+	default:
+		return fmt.Errorf("Invalid dry-run format '%s'", config.dryRunFormat)
+	}
+
+	if config.backend == backendNetworkManager && config.disableNM {
+		return fmt.Errorf("NetworkManager backend cannot be used with NetworkManager disabled for the interfaces")
+	}
+
+	if _, err := getAddressParser(config.addressParser); err != nil {
+		return err
+	}
+
+	if config.lldpTxInterval <= 0 {
+		return fmt.Errorf("Invalid LLDP transmit interval '%v'", config.lldpTxInterval)
+	}
+
+	if config.probeInterval < 0 {
+		return fmt.Errorf("Invalid probe interval '%v'", config.probeInterval)
+	}
+
+	if config.lldpTTL < config.lldpTxInterval {
+		klog.Infof("Forcing LLDP TTL %v (old %v)", lldpTxHoldMultiplier*config.lldpTxInterval, config.lldpTTL)
+
+		config.lldpTTL = lldpTxHoldMultiplier * config.lldpTxInterval
+	}
+
 	return nil
 }
 
@@ -109,18 +240,39 @@ func detectLLDP(config *cmdConfig, networkConfigs map[string]*networkConfigurati
 
 	wg.Wait()
 
+	received := map[string]bool{}
+
 	for len(lldpResultChan) > 0 {
 		result := <-lldpResultChan
 
 		if nwconfig, exists := networkConfigs[result.InterfaceName]; exists {
-			nwconfig.portDescription = result.PortDescription
+			applyLLDPResult(nwconfig, result)
+			received[result.InterfaceName] = true
+		}
+	}
 
-			var hwaddr net.HardwareAddr = result.PeerMAC
-			nwconfig.peerHWAddr = &hwaddr
+	for ifname, nwconfig := range networkConfigs {
+		if !received[ifname] {
+			nodeEvents.warningf(reasonLLDPTimeout, "No LLDP information received on interface '%s' within %v", ifname, config.timeout)
+			nwconfig.setStepError(stepLLDP, fmt.Errorf("no LLDP information received within %v", config.timeout))
+		} else {
+			nwconfig.setStepError(stepLLDP, nil)
 		}
 	}
 }
 
+func applyLLDPResult(nwconfig *networkConfiguration, result lldp.DiscoveryResult) {
+	nwconfig.portDescription = result.PortDescription
+	nwconfig.sysName = result.SysName
+	nwconfig.orgTLVs = result.OrgTLVs
+	nwconfig.mgmtAddresses = result.MgmtAddresses
+	nwconfig.peerMTU = int(result.MTU)
+	nwconfig.lastLLDPDU = time.Now()
+
+	var hwaddr net.HardwareAddr = result.PeerMAC
+	nwconfig.peerHWAddr = &hwaddr
+}
+
 func preCleanups(config *cmdConfig) error {
 	if _, err := os.Stat(nfdLabelFile); err == nil {
 		klog.Infof("NFD label file already exists, removing it...\n")
@@ -130,6 +282,10 @@ func preCleanups(config *cmdConfig) error {
 		}
 	}
 
+	if err := os.Remove(nfdReachabilityFile); err == nil {
+		klog.Infof("Removed NFD reachability label file")
+	}
+
 	if config.networkd != "" {
 		if err := os.MkdirAll(config.networkd, 0755); err != nil {
 			return fmt.Errorf("Cannot create systemd-networkd directory: %v", err)
@@ -140,59 +296,205 @@ func preCleanups(config *cmdConfig) error {
 	return nil
 }
 
-func postCleanups(networkConfigs map[string]*networkConfiguration) {
-	klog.Info("Clean up before exiting...")
+func writeNFDLabel() error {
+	if s, err := os.Stat(nfdFeatureDir); err == nil && s.IsDir() {
+		content := nfdScaleOutReadyLabel + "\n"
 
-	err := os.Remove(nfdLabelFile)
-	if err != nil {
+		if err := os.WriteFile(nfdLabelFile, []byte(content), 0644); err != nil {
+			return fmt.Errorf("Failed to write NFD label to indicate scale-out readiness: %+v\n", err)
+		}
+	}
+
+	return nil
+}
+
+func removeNFDLabel() {
+	if err := os.Remove(nfdLabelFile); err != nil && !os.IsNotExist(err) {
 		klog.Warningf("Failed to remove NFD label file: %+v\n", err)
 	}
+}
+
+// postCleanups undoes the configuration of the interfaces on exit. The
+// persistent NetworkManager configuration is only removed on teardown.
+func postCleanups(config *cmdConfig, networkConfigs map[string]*networkConfiguration, teardown bool) {
+	klog.Info("Clean up before exiting...")
+
+	removeNFDLabel()
+
+	if err := os.Remove(nfdReachabilityFile); err != nil && !os.IsNotExist(err) {
+		klog.Warningf("Failed to remove NFD reachability label file: %+v\n", err)
+	}
+
+	if nmProfiles != nil {
+		for name := range networkConfigs {
+			nmProfiles.deconfigure(name)
+		}
+	}
 
 	klog.Infof("Restoring interfaces to original state...")
-	if err := removeExistingIPs(networkConfigs); err != nil {
+	if err := removeExistingIPs(networkConfigs, config.flushAddresses); err != nil {
 		klog.Warningf("Failed to remove any existing IPs from interfaces: %+v\n", err)
 	}
 
 	if err := interfacesRestoreDown(networkConfigs); err != nil {
 		klog.Warningf("Failed to restore interfaces to original state: %+v\n", err)
 	}
+
+	// the persistent configuration keeps the interfaces unmanaged until teardown
+	manage := config.nmConfig == "" || teardown
+
+	if err := restoreRecordedState(config, networkConfigs, manage); err != nil {
+		klog.Warningf("Failed to restore the recorded interface state: %+v\n", err)
+	}
+
+	if manage {
+		restoreNetworkManager(config, networkConfigs)
+	}
+}
+
+// selectedInterfaces returns the names of the interfaces to configure. The
+// interfaces listed with --interfaces are added to the discovered ones once
+// and are subject to the same include and exclude filters.
+func selectedInterfaces(config *cmdConfig) ([]string, error) {
+	selector := deviceSelector{
+		driver:  config.driver,
+		pciIDs:  config.parsedPCIIDs,
+		include: config.include,
+		exclude: config.exclude,
+	}
+
+	allInterfaces := getNetworks(selector)
+
+	for _, name := range strings.Split(config.ifaces, ",") {
+		if name == "" || slices.Contains(allInterfaces, name) {
+			continue
+		}
+
+		if !selector.selects(listedInterfaceInfo(name)) {
+			klog.V(3).Infof("Interface '%s' not selected", name)
+			continue
+		}
+
+		allInterfaces = append(allInterfaces, name)
+	}
+
+	if len(allInterfaces) == 0 {
+		return nil, fmt.Errorf("No interfaces found")
+	}
+
+	return allInterfaces, nil
 }
 
-func cmdRun(config *cmdConfig) error {
-	err := sanitizeInput(config)
+func cmdRun(config *cmdConfig) (err error) {
+	var (
+		reporter       *nodeStateReporter
+		networkConfigs map[string]*networkConfiguration
+	)
+
+	err = sanitizeInput(config)
 	if err != nil {
 		return err
 	}
 
+	if config.dryRun {
+		return dryRun(config, os.Stdout)
+	}
+
+	if config.policy != "" {
+		if reporter, err = newNodeStateReporter(config.policy); err != nil {
+			return fmt.Errorf("Failed to set up node state reporting: %v", err)
+		}
+
+		defer func() {
+			if err != nil {
+				_ = reportNodeState(config, reporter, networkConfigs, err)
+			}
+		}()
+
+		setupNodeEvents()
+		defer nodeEvents.shutdown()
+	}
+
 	if err := preCleanups(config); err != nil {
 		return fmt.Errorf("Failed to pre-cleanup: %v", err)
 	}
 
-	allInterfaces := getNetworks()
+	if config.metricsAddr != "" {
+		stopMetrics, err := serveMetrics(config.metricsAddr, config.metricsSecure)
+		if err != nil {
+			return fmt.Errorf("Failed to serve metrics: %v", err)
+		}
 
-	if len(config.ifaces) > 0 {
-		allInterfaces = append(allInterfaces, strings.Split(config.ifaces, ",")...)
+		defer stopMetrics()
 	}
 
-	if len(allInterfaces) == 0 {
-		return fmt.Errorf("No interfaces found")
+	allInterfaces, err := selectedInterfaces(config)
+	if err != nil {
+		return err
 	}
 
-	networkConfigs := getNetworkConfigs(allInterfaces)
+	networkConfigs = getNetworkConfigs(allInterfaces)
 	if len(networkConfigs) < len(allInterfaces) {
 		return fmt.Errorf("Not all interfaces were found in the system")
 	}
 
+	if err := setupProfileBackend(config); err != nil {
+		return err
+	}
+
+	if ownedAddresses, err = loadAddressRecord(config.addressRecord); err != nil {
+		return err
+	}
+
+	// the policy was deleted while the daemon wasn't running
+	if teardownPending(config, reporter) {
+		reporter.cleanedUp = true
+
+		teardownNode(config, networkConfigs)
+		idleAfterTeardown(config, reporter, networkConfigs)
+
+		return nil
+	}
+
+	// record the original state of the interfaces before changing them
+	if config.snapshot != "" {
+		var nmManaged []string
+
+		if config.disableNM {
+			if nmManaged, err = nmManagedInterfaces(allInterfaces); err != nil {
+				return err
+			}
+		}
+
+		if err := snapshotInterfaces(config.snapshot, networkConfigs, nmManaged); err != nil {
+			return fmt.Errorf("Failed to record the state of the interfaces: %v", err)
+		}
+	}
+
 	if config.disableNM {
 		nmapi, err := nm.NewNetworkManager()
 		if err != nil {
 			return fmt.Errorf("Failed to create NetworkManager: %v", err)
 		}
 
-		err = nm.DisableNetworkManagerForInterfaces(nmapi, allInterfaces)
+		err = nm.DisableNetworkManagerForInterfaces(nmapi, allInterfaces, config.nmRecord)
 		if err != nil {
+			nodeEvents.warningf(reasonNetworkManagerDisableFailed, "Failed to disable interfaces in NetworkManager: %v", err)
+
 			return fmt.Errorf("Failed to disable interfaces in NetworkManager: %v", err)
 		}
+
+		for _, nwconfig := range networkConfigs {
+			nwconfig.nmUnmanaged = true
+		}
+
+		if config.nmConfig != "" {
+			if err := writeNetworkManagerConfig(nmapi, config, networkConfigs); err != nil {
+				nodeEvents.warningf(reasonNetworkManagerDisableFailed, "Failed to write NetworkManager configuration: %v", err)
+
+				return fmt.Errorf("Failed to write NetworkManager configuration: %v", err)
+			}
+		}
 	}
 
 	if err := interfacesUp(networkConfigs); err != nil {
@@ -201,17 +503,40 @@ func cmdRun(config *cmdConfig) error {
 
 	interfacesSetMTU(networkConfigs, config.mtu)
 
-	if err := removeExistingIPs(networkConfigs); err != nil {
+	if err := removeExistingIPs(networkConfigs, config.flushAddresses); err != nil {
 		return fmt.Errorf("Failed to remove any existing IPs from interfaces: %+v", err)
 	}
 
 	if config.mode == L3 {
-		detectLLDP(config, networkConfigs)
-		foundpeers := lldpResults(networkConfigs)
+		parser, _ := getAddressParser(config.addressParser)
+
+		for _, nwconfig := range networkConfigs {
+			nwconfig.routedNetworks = config.parsedRoutedNetworks
+			nwconfig.addressParser = parser
+			nwconfig.mgmtFallback = config.mgmtAddressFallback
+			nwconfig.selected = networkConfigs
+		}
+
+		var foundpeers bool
+
+		if config.addressSource == addressSourcePool {
+			foundpeers = waitAddressAllocations(config, reporter, networkConfigs)
+		} else {
+			detectLLDP(config, networkConfigs)
+			checkPeerMTU(networkConfigs, config.mtu)
+			foundpeers = lldpResults(networkConfigs)
+		}
 
 		if config.configure && foundpeers {
 			numConfigured, numTotal := configureInterfaces(networkConfigs)
+
+			if config.addressSource == addressSourcePool {
+				resolveGateways(config.ctx, networkConfigs)
+			}
+
 			if numConfigured < numTotal {
+				writeReport(config, networkConfigs)
+
 				return fmt.Errorf("Not all interfaces were configured (%d/%d).", numConfigured, numTotal)
 			}
 			klog.Infof("Configured %d of %d interfaces\n", numConfigured, numTotal)
@@ -231,33 +556,268 @@ func cmdRun(config *cmdConfig) error {
 	}
 
 	logResults(config, networkConfigs)
+	writeReport(config, networkConfigs)
+
+	interfaceMetrics.update(networkConfigs)
+
+	reported := reportNodeState(config, reporter, networkConfigs, nil)
 
 	if !config.configure {
 		if err := interfacesRestoreDown(networkConfigs); err != nil {
 			return err
 		}
+
+		if err := restoreRecordedState(config, networkConfigs, config.nmConfig == ""); err != nil {
+			return fmt.Errorf("Failed to restore the recorded interface state: %v", err)
+		}
 	} else if config.configure && config.keepRunning {
-		if s, err := os.Stat(nfdFeatureDir); err == nil && s.IsDir() {
-			content := nfdScaleOutReadyLabel + "\n"
+		if err := writeNFDLabel(); err != nil {
+			return err
+		}
+
+		klog.Infof("Configurations done. Idling...")
 
-			if err := os.WriteFile(nfdLabelFile, []byte(content), 0644); err != nil {
-				return fmt.Errorf("Failed to write NFD label to indicate scale-out readiness: %+v\n", err)
+		// teardown cleans up the node before the daemon exits
+		cleanedUp := false
+
+		defer func() {
+			if !cleanedUp {
+				postCleanups(config, networkConfigs, false)
 			}
+		}()
+
+		var updateTransmit func(*networkConfiguration)
+
+		if config.lldpTransmit {
+			var stopTransmit func()
+
+			updateTransmit, stopTransmit = transmitLLDP(config, networkConfigs)
+			defer stopTransmit()
 		}
 
-		klog.Infof("Configurations done. Idling...")
+		// keep listening to LLDP to follow changes in the switch configuration
+		var lldpUpdates <-chan lldp.DiscoveryResult
+
+		if config.mode == L3 && config.addressSource == addressSourceLLDP {
+			var stopMonitor func()
+
+			lldpUpdates, stopMonitor = monitorLLDP(config, networkConfigs)
+			defer stopMonitor()
+		}
+
+		// re-apply the configuration after link flaps and driver reloads
+		var (
+			linkUpdates  <-chan netlink.LinkUpdate
+			addrUpdates  <-chan netlink.AddrUpdate
+			routeUpdates <-chan netlink.RouteUpdate
+		)
+
+		settle := time.NewTimer(linkWatchSettleTime)
+		settle.Stop()
+		defer settle.Stop()
 
-		defer postCleanups(networkConfigs)
+		watcher, err := newLinkWatcher(config, networkConfigs)
+		if err != nil {
+			klog.Warningf("Cannot watch interfaces: %v", err)
+		} else {
+			defer watcher.stop()
+
+			linkUpdates, addrUpdates, routeUpdates = watcher.linkUpdates, watcher.addrUpdates, watcher.routeUpdates
+		}
+
+		// probe the LLDP peers to verify the data path
+		var (
+			probeTicks   <-chan time.Time
+			probeResults <-chan map[string]*peerProbe
+		)
+
+		if config.mode == L3 && config.probeInterval > 0 {
+			probe := time.NewTicker(config.probeInterval)
+			defer probe.Stop()
+
+			probeTicks = probe.C
+		}
+
+		// follow the address allocations of the operator
+		var allocationTicks <-chan time.Time
+
+		if config.mode == L3 && config.addressSource == addressSourcePool {
+			allocationPoll := time.NewTicker(addressPoolPollInterval)
+			defer allocationPoll.Stop()
+
+			allocationTicks = allocationPoll.C
+		}
+
+		// follow the node state for the teardown request on policy deletion
+		var teardownTicks <-chan time.Time
+
+		if reporter != nil {
+			teardownPoll := time.NewTicker(teardownPollInterval)
+			defer teardownPoll.Stop()
+
+			teardownTicks = teardownPoll.C
+		}
 
 		term := make(chan os.Signal, 1)
 
 		signal.Notify(term, os.Interrupt, syscall.SIGTERM)
-		<-term
+
+		// retry reporting until the node state object is available
+		retry := time.NewTicker(nodeStateRetryInterval)
+		defer retry.Stop()
+
+		if reported {
+			retry.Stop()
+		}
+
+	idle:
+		for {
+			select {
+			case <-term:
+				break idle
+			case <-retry.C:
+				if reportNodeState(config, reporter, networkConfigs, nil) {
+					retry.Stop()
+				}
+			case result := <-lldpUpdates:
+				changed := reconcileLLDPResult(config, networkConfigs, result)
+
+				interfaceMetrics.update(networkConfigs)
+
+				if !changed {
+					continue
+				}
+
+				updateConfigFiles(config, networkConfigs, result.InterfaceName)
+
+				if updateTransmit != nil {
+					updateTransmit(networkConfigs[result.InterfaceName])
+				}
+
+				if !reportNodeState(config, reporter, networkConfigs, nil) {
+					retry.Reset(nodeStateRetryInterval)
+				}
+			case <-allocationTicks:
+				changed := reconcileAddressAllocations(config, reporter, networkConfigs)
+				if len(changed) == 0 {
+					continue
+				}
+
+				interfaceMetrics.update(networkConfigs)
+
+				for _, ifname := range changed {
+					updateConfigFiles(config, networkConfigs, ifname)
+
+					if updateTransmit != nil {
+						updateTransmit(networkConfigs[ifname])
+					}
+				}
+
+				if !reportNodeState(config, reporter, networkConfigs, nil) {
+					retry.Reset(nodeStateRetryInterval)
+				}
+			case update := <-linkUpdates:
+				if watcher.handleLinkUpdate(update) {
+					settle.Reset(linkWatchSettleTime)
+				}
+			case update := <-addrUpdates:
+				if watcher.handleAddrUpdate(update) {
+					settle.Reset(linkWatchSettleTime)
+				}
+			case update := <-routeUpdates:
+				if watcher.handleRouteUpdate(update) {
+					settle.Reset(linkWatchSettleTime)
+				}
+			case <-probeTicks:
+				// the previous probes are still running
+				if probeResults != nil {
+					continue
+				}
+
+				probeResults = startProbes(config.ctx, networkConfigs)
+			case probes := <-probeResults:
+				probeResults = nil
+
+				if !applyProbes(networkConfigs, probes) {
+					continue
+				}
+
+				if err := writeReachabilityLabels(networkConfigs); err != nil {
+					klog.Warning(err)
+				}
+
+				if !reportNodeState(config, reporter, networkConfigs, nil) {
+					retry.Reset(nodeStateRetryInterval)
+				}
+			case <-teardownTicks:
+				if !teardownPending(config, reporter) {
+					continue
+				}
+
+				// stop following the peers and the links, the node is no longer configured
+				lldpUpdates, probeTicks, probeResults, teardownTicks, allocationTicks = nil, nil, nil, nil, nil
+				linkUpdates, addrUpdates, routeUpdates = nil, nil, nil
+				settle.Stop()
+
+				reporter.cleanedUp = true
+				teardownNode(config, networkConfigs)
+				cleanedUp = true
+
+				if updateTransmit != nil {
+					for _, nwconfig := range networkConfigs {
+						updateTransmit(nwconfig)
+					}
+				}
+
+				interfaceMetrics.update(networkConfigs)
+
+				if !reportNodeState(config, reporter, networkConfigs, nil) {
+					retry.Reset(nodeStateRetryInterval)
+				}
+			case <-settle.C:
+				changed := watcher.reapply()
+
+				// links that came up or were recreated need a transmitter
+				if updateTransmit != nil {
+					for _, nwconfig := range networkConfigs {
+						updateTransmit(nwconfig)
+					}
+				}
+
+				if !changed {
+					continue
+				}
+
+				interfaceMetrics.update(networkConfigs)
+
+				// scale-out is not ready while a port is down
+				if watcher.isDegraded() {
+					removeNFDLabel()
+				} else if err := writeNFDLabel(); err != nil {
+					klog.Warning(err)
+				}
+
+				if !reportNodeState(config, reporter, networkConfigs, nil) {
+					retry.Reset(nodeStateRetryInterval)
+				}
+			}
+		}
 	}
 
 	return nil
 }
 
+func addInterfaceFilterFlags(cmd *cobra.Command, flags *interfaceFilterFlags, prefix, action string) {
+	cmd.Flags().StringSliceVarP(&flags.drivers, prefix+"-drivers", "", nil,
+		action+" the interfaces of the devices bound to the comma separated drivers")
+	cmd.Flags().StringSliceVarP(&flags.pciAddresses, prefix+"-pci-addresses", "", nil,
+		action+" the interfaces of the devices matching the comma separated PCI address patterns, e.g. '0000:b3:00.*'")
+	cmd.Flags().StringArrayVarP(&flags.names, prefix+"-names", "", nil,
+		action+" the interfaces whose name matches the regular expression, can be repeated")
+	cmd.Flags().StringSliceVarP(&flags.ports, prefix+"-ports", "", nil,
+		action+" the interfaces with the comma separated port indexes or ranges, e.g. '0' or '1-23'")
+}
+
 // error is always nil, but keep the logic incase we want to return it later on.
 // nolint: unparam
 func setupCmd() (*cobra.Command, error) {
@@ -282,8 +842,38 @@ func setupCmd() (*cobra.Command, error) {
 		"'L2' for network layer 2 or 'L3' for network layer 3 (L3) using LLDP")
 	cmd.Flags().BoolVarP(&config.configure, "configure", "", false,
 		"Configure L3 network with LLDP or set interfaces up with L2 networks")
+	cmd.Flags().StringVarP(&config.backend, "backend", "", backendNetlink,
+		"Backend for the L3 configuration, 'netlink' or 'networkmanager' for NetworkManager connection profiles")
+	cmd.Flags().StringVarP(&config.output, "output", "", "",
+		"Print a discovery report of the interfaces in 'json' or 'yaml' format")
+	cmd.Flags().StringVarP(&config.reportFile, "report-file", "", "",
+		"Write the discovery report to the file instead of stdout, in JSON unless --output is set")
+	cmd.Flags().BoolVarP(&config.flushAddresses, "flush-addresses", "", false,
+		"Remove all but the link-local addresses from the interfaces instead of only the ones added by discover")
+	cmd.Flags().StringVarP(&config.addressRecord, "address-record", "", "",
+		"File on the host recording the addresses added by discover, for removing them after a restart. Empty keeps the record in memory")
+	cmd.Flags().StringVarP(&config.snapshot, "snapshot", "", "",
+		"File on the host recording the state of the interfaces before they are changed, restored on exit and teardown. Empty disables the recording")
+	cmd.Flags().BoolVarP(&config.dryRun, "dry-run", "", false,
+		"Only read the interface state and listen to LLDP, then print the changes a run with the same flags would make")
+	cmd.Flags().StringVarP(&config.dryRunFormat, "dry-run-format", "", dryRunFormatText,
+		"Format of the dry-run plan, 'text' or 'json'")
 	cmd.Flags().BoolVarP(&config.disableNM, "disable-networkmanager", "", false,
 		"Disable Host's NetworkManager for interfaces")
+	cmd.Flags().StringVarP(&config.nmRecord, "networkmanager-record", "", nm.DefaultRecordPath,
+		"File on the host recording the interfaces disabled in NetworkManager, restored on exit and teardown")
+	cmd.Flags().StringVarP(&config.nmConfig, "networkmanager-config", "", "",
+		"Keep the interfaces unmanaged across NetworkManager restarts with a configuration file matching them by 'interface-name' or 'mac'")
+	cmd.Flags().StringVarP(&config.nmConfDir, "networkmanager-conf-dir", "", nm.DefaultConfDir,
+		"NetworkManager configuration snippet directory")
+	cmd.Flags().StringVarP(&config.nmConfFile, "networkmanager-conf-file", "", nm.DefaultConfFile,
+		"Name of the NetworkManager configuration snippet, unique for each policy targeting the node")
+	cmd.Flags().StringVarP(&config.driver, "driver", "", defaultDriver,
+		"PCI driver of the network devices to configure, empty to select the devices by PCI IDs only")
+	cmd.Flags().StringSliceVarP(&config.pciIDs, "pci-ids", "", nil,
+		"Comma separated list of PCI 'vendor:device' IDs of the network devices to configure, e.g. '8086:1593'")
+	addInterfaceFilterFlags(cmd, &config.includeFlags, "include", "Only configure")
+	addInterfaceFilterFlags(cmd, &config.excludeFlags, "exclude", "Do not configure")
 	cmd.Flags().StringVarP(&config.ifaces, "interfaces", "", "",
 		"Comma separated list of additional network interfaces")
 	cmd.Flags().DurationVarP(&config.timeout, "wait", "", time.Second*30,
@@ -296,6 +886,30 @@ func setupCmd() (*cobra.Command, error) {
 		"Write systemd networkd configuration files to given directory")
 	cmd.Flags().IntVarP(&config.mtu, "mtu", "", 1500,
 		"MTU value to set for interfaces")
+	cmd.Flags().StringSliceVarP(&config.routedNetworks, "routed-networks", "", nil,
+		"Comma separated list of routed scale-out networks as CIDRs or prefix lengths applied to the local address (default /16 for IPv4, /64 for IPv6)")
+	cmd.Flags().StringVarP(&config.addressSource, "address-source", "", addressSourceLLDP,
+		"Source of the interface addresses in L3 mode, 'lldp' or 'pool' for the addresses allocated by the operator in the node state")
+	cmd.Flags().StringVarP(&config.addressParser, "lldp-address-parser", "", addressParserPortDescription,
+		"Parser for the switch port address received via LLDP, one of: "+strings.Join(addressParserNames(), ", "))
+	cmd.Flags().BoolVarP(&config.mgmtAddressFallback, "lldp-mgmt-address-fallback", "", false,
+		"Use the LLDP management address as the switch port address when the parser finds no address")
+	cmd.Flags().BoolVarP(&config.lldpTransmit, "lldp-transmit", "", false,
+		"Transmit LLDP frames on the interfaces while running with --keep-running")
+	cmd.Flags().DurationVarP(&config.lldpTxInterval, "lldp-transmit-interval", "", time.Second*30,
+		"Interval between transmitted LLDP frames")
+	cmd.Flags().DurationVarP(&config.lldpTTL, "lldp-ttl", "", time.Second*120,
+		"Time to live of the transmitted LLDP information")
+	cmd.Flags().DurationVarP(&config.probeInterval, "probe-interval", "", time.Second*30,
+		"Interval for probing the reachability of the LLDP peers in L3 mode with --keep-running, 0 disables probing")
+	cmd.Flags().StringVarP(&config.metricsAddr, "metrics-bind-address", "", "",
+		"Address to serve Prometheus metrics on, e.g. '10.0.0.1:9501'. Metrics are disabled if empty")
+	cmd.Flags().BoolVarP(&config.metricsSecure, "metrics-secure", "", false,
+		"Serve the metrics over HTTPS to clients authorized to get /metrics in the cluster")
+	cmd.Flags().StringVarP(&config.policy, "policy", "", "",
+		"Name of the NetworkClusterPolicy to report the node state to")
+
+	cmd.AddCommand(setupRestoreCmd())
 
 	return cmd, nil
 }
@@ -0,0 +1,300 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vishvananda/netlink"
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+)
+
+const (
+	metricsNamespace = "intel_network_discover"
+	metricsPath      = "/metrics"
+)
+
+var (
+	configAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "configuration_attempts_total",
+		Help:      "Number of attempts to configure the address and routes of an interface.",
+	}, []string{"interface"})
+
+	configFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "configuration_failures_total",
+		Help:      "Number of failed attempts to configure the address and routes of an interface.",
+	}, []string{"interface"})
+
+	interfaceMetrics = &interfaceCollector{}
+)
+
+var (
+	linkUpDesc = prometheus.NewDesc(metricsNamespace+"_interface_up",
+		"Whether the interface is administratively up.", []string{"interface"}, nil)
+	carrierDesc = prometheus.NewDesc(metricsNamespace+"_interface_carrier",
+		"Whether the interface has a carrier.", []string{"interface"}, nil)
+	mtuDesc = prometheus.NewDesc(metricsNamespace+"_interface_mtu_bytes",
+		"MTU of the interface.", []string{"interface"}, nil)
+	addressDesc = prometheus.NewDesc(metricsNamespace+"_interface_address_configured",
+		"Whether the address selected via LLDP is configured on the interface.", []string{"interface"}, nil)
+	lldpPeerDesc = prometheus.NewDesc(metricsNamespace+"_interface_lldp_peer_seen",
+		"Whether an LLDP peer has been seen on the interface.", []string{"interface"}, nil)
+	lldpAgeDesc = prometheus.NewDesc(metricsNamespace+"_interface_lldp_last_seen_seconds",
+		"Seconds since the last LLDPDU was received on the interface.", []string{"interface"}, nil)
+	rxErrorsDesc = prometheus.NewDesc(metricsNamespace+"_interface_receive_errors_total",
+		"Receive errors reported by the kernel for the interface.", []string{"interface"}, nil)
+	txErrorsDesc = prometheus.NewDesc(metricsNamespace+"_interface_transmit_errors_total",
+		"Transmit errors reported by the kernel for the interface.", []string{"interface"}, nil)
+)
+
+// interfaceSnapshot holds the interface information needed for the metrics,
+// the network configurations are only accessed from the main loop.
+type interfaceSnapshot struct {
+	name       string
+	localAddr  net.IP
+	family     int
+	peerSeen   bool
+	lastLLDPDU time.Time
+}
+
+// interfaceCollector collects per interface metrics. Link state and
+// statistics are read from the kernel on each scrape.
+type interfaceCollector struct {
+	mutex      sync.Mutex
+	interfaces []interfaceSnapshot
+}
+
+func (c *interfaceCollector) update(networkConfigs map[string]*networkConfiguration) {
+	interfaces := make([]interfaceSnapshot, 0, len(networkConfigs))
+
+	for name, nwconfig := range networkConfigs {
+		snapshot := interfaceSnapshot{
+			name:       name,
+			family:     addrFamily(nwconfig),
+			peerSeen:   nwconfig.peerHWAddr != nil,
+			lastLLDPDU: nwconfig.lastLLDPDU,
+		}
+		if nwconfig.localAddr != nil {
+			snapshot.localAddr = *nwconfig.localAddr
+		}
+
+		interfaces = append(interfaces, snapshot)
+	}
+
+	sort.Slice(interfaces, func(i, j int) bool { return interfaces[i].name < interfaces[j].name })
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.interfaces = interfaces
+}
+
+func (c *interfaceCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{linkUpDesc, carrierDesc, mtuDesc, addressDesc,
+		lldpPeerDesc, lldpAgeDesc, rxErrorsDesc, txErrorsDesc} {
+		ch <- desc
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func addressConfigured(link netlink.Link, snapshot *interfaceSnapshot) bool {
+	if snapshot.localAddr == nil {
+		return false
+	}
+
+	addrs, err := networkLink.AddrList(link, snapshot.family)
+	if err != nil {
+		return false
+	}
+
+	for _, addr := range addrs {
+		if addr.IPNet != nil && addr.IPNet.IP.Equal(snapshot.localAddr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *interfaceCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	interfaces := c.interfaces
+	c.mutex.Unlock()
+
+	for i := range interfaces {
+		snapshot := &interfaces[i]
+
+		ch <- prometheus.MustNewConstMetric(lldpPeerDesc, prometheus.GaugeValue, boolToFloat(snapshot.peerSeen), snapshot.name)
+
+		if !snapshot.lastLLDPDU.IsZero() {
+			ch <- prometheus.MustNewConstMetric(lldpAgeDesc, prometheus.GaugeValue,
+				time.Since(snapshot.lastLLDPDU).Seconds(), snapshot.name)
+		}
+
+		link, err := networkLink.LinkByName(snapshot.name)
+		if err != nil {
+			klog.V(3).Infof("Cannot get link '%s' for metrics: %v", snapshot.name, err)
+			continue
+		}
+
+		attrs := link.Attrs()
+
+		ch <- prometheus.MustNewConstMetric(linkUpDesc, prometheus.GaugeValue, boolToFloat(attrs.Flags&net.FlagUp != 0), snapshot.name)
+		ch <- prometheus.MustNewConstMetric(carrierDesc, prometheus.GaugeValue, boolToFloat(attrs.OperState == netlink.OperUp), snapshot.name)
+		ch <- prometheus.MustNewConstMetric(mtuDesc, prometheus.GaugeValue, float64(attrs.MTU), snapshot.name)
+		ch <- prometheus.MustNewConstMetric(addressDesc, prometheus.GaugeValue, boolToFloat(addressConfigured(link, snapshot)), snapshot.name)
+
+		if attrs.Statistics != nil {
+			ch <- prometheus.MustNewConstMetric(rxErrorsDesc, prometheus.CounterValue, float64(attrs.Statistics.RxErrors), snapshot.name)
+			ch <- prometheus.MustNewConstMetric(txErrorsDesc, prometheus.CounterValue, float64(attrs.Statistics.TxErrors), snapshot.name)
+		}
+	}
+}
+
+func newMetricsRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(configAttempts, configFailures, interfaceMetrics)
+
+	return registry
+}
+
+// metricsHandler returns the handler of the metrics endpoint. With secure,
+// the requests need a token authorized to get /metrics, like for the metrics
+// of the operator.
+func metricsHandler(secure bool) (http.Handler, error) {
+	handler := promhttp.HandlerFor(newMetricsRegistry(), promhttp.HandlerOpts{})
+	if !secure {
+		return handler, nil
+	}
+
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := filters.WithAuthenticationAndAuthorization(cfg, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter(klog.Background(), handler)
+}
+
+// metricsTLSConfig returns a TLS configuration with a self-signed
+// certificate for the host of the address.
+func metricsTLSConfig(addr string) (*tls.Config, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if host == "" {
+		host = "localhost"
+	}
+
+	cert, key, err := certutil.GenerateSelfSignedCertKey(host, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate a self-signed certificate: %v", err)
+	}
+
+	keyPair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// HTTP/2 is disabled for the HTTP/2 Stream Cancellation and Rapid Reset CVEs
+	return &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"http/1.1"},
+	}, nil
+}
+
+// serveMetrics serves the metrics on the given address until the returned
+// function is called. With secure, the metrics are served over HTTPS to
+// authenticated and authorized clients.
+func serveMetrics(addr string, secure bool) (func(), error) {
+	handler, err := metricsHandler(secure)
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up metrics authorization: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, handler)
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if secure {
+		if server.TLSConfig, err = metricsTLSConfig(addr); err != nil {
+			return nil, err
+		}
+	}
+
+	go func() {
+		klog.Infof("Serving metrics on %s%s", addr, metricsPath)
+
+		var err error
+
+		if secure {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.Errorf("Metrics server failed: %v", err)
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_ = server.Shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+func gatherMetrics(t *testing.T) map[string]map[string]float64 {
+	families, err := newMetricsRegistry().Gather()
+	if err != nil {
+		t.Fatalf("cannot gather metrics: %v", err)
+	}
+
+	metrics := map[string]map[string]float64{}
+
+	for _, family := range families {
+		values := map[string]float64{}
+
+		for _, metric := range family.GetMetric() {
+			iface := ""
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "interface" {
+					iface = label.GetValue()
+				}
+			}
+
+			switch {
+			case metric.GetGauge() != nil:
+				values[iface] = metric.GetGauge().GetValue()
+			case metric.GetCounter() != nil:
+				values[iface] = metric.GetCounter().GetValue()
+			}
+		}
+
+		metrics[family.GetName()] = values
+	}
+
+	return metrics
+}
+
+func TestInterfaceMetrics(t *testing.T) {
+	localAddr := net.IPv4(10, 210, 8, 121)
+	peerMAC := net.HardwareAddr{0x01, 0x01, 0x02, 0x02, 0x03, 0x03}
+
+	nwconfigs := map[string]*networkConfiguration{
+		"eth_a": {
+			link:       &fakeLink{fakeAttrs: netlink.LinkAttrs{Name: "eth_a"}},
+			localAddr:  &localAddr,
+			peerHWAddr: &peerMAC,
+			lastLLDPDU: time.Now().Add(-10 * time.Second),
+		},
+		"eth_b": {
+			link: &fakeLink{fakeAttrs: netlink.LinkAttrs{Name: "eth_b"}},
+		},
+	}
+
+	networkLink.LinkByName = func(name string) (netlink.Link, error) {
+		return &fakeLink{
+			fakeAttrs: netlink.LinkAttrs{
+				Name:       name,
+				Flags:      net.FlagUp,
+				OperState:  netlink.OperUp,
+				MTU:        8000,
+				Statistics: &netlink.LinkStatistics{RxErrors: 3, TxErrors: 4},
+			},
+		}, nil
+	}
+	networkLink.AddrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return []netlink.Addr{
+			{IPNet: &net.IPNet{IP: localAddr, Mask: net.CIDRMask(30, 32)}},
+		}, nil
+	}
+
+	interfaceMetrics.update(nwconfigs)
+	defer interfaceMetrics.update(nil)
+
+	metrics := gatherMetrics(t)
+
+	expected := map[string]map[string]float64{
+		metricsNamespace + "_interface_up":                    {"eth_a": 1, "eth_b": 1},
+		metricsNamespace + "_interface_carrier":               {"eth_a": 1, "eth_b": 1},
+		metricsNamespace + "_interface_mtu_bytes":             {"eth_a": 8000, "eth_b": 8000},
+		metricsNamespace + "_interface_address_configured":    {"eth_a": 1, "eth_b": 0},
+		metricsNamespace + "_interface_lldp_peer_seen":        {"eth_a": 1, "eth_b": 0},
+		metricsNamespace + "_interface_receive_errors_total":  {"eth_a": 3, "eth_b": 3},
+		metricsNamespace + "_interface_transmit_errors_total": {"eth_a": 4, "eth_b": 4},
+	}
+
+	for name, values := range expected {
+		for iface, value := range values {
+			if got, ok := metrics[name][iface]; !ok || got != value {
+				t.Errorf("%s{interface=%s}: expected %v, got %v", name, iface, value, got)
+			}
+		}
+	}
+
+	age := metrics[metricsNamespace+"_interface_lldp_last_seen_seconds"]
+	if _, ok := age["eth_b"]; ok || age["eth_a"] < 10 {
+		t.Errorf("unexpected LLDP ages %v", age)
+	}
+}
+
+func TestConfigurationMetrics(t *testing.T) {
+	networkLink.AddrList = fakeLinkAddrListErr
+
+	localAddr := net.IPv4(10, 210, 8, 121)
+	nwconfigs := map[string]*networkConfiguration{
+		"eth_metrics": {
+			link:      &fakeLink{fakeAttrs: netlink.LinkAttrs{Name: "eth_metrics"}},
+			localAddr: &localAddr,
+		},
+	}
+
+	configureInterfaces(nwconfigs)
+	configureInterfaces(nwconfigs)
+
+	metrics := gatherMetrics(t)
+
+	if attempts := metrics[metricsNamespace+"_configuration_attempts_total"]["eth_metrics"]; attempts != 2 {
+		t.Errorf("expected 2 configuration attempts, got %v", attempts)
+	}
+	if failures := metrics[metricsNamespace+"_configuration_failures_total"]["eth_metrics"]; failures != 2 {
+		t.Errorf("expected 2 configuration failures, got %v", failures)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/intel/network-operator/pkg/lldp"
+)
+
+const (
+	lldpMonitorRetryInterval = 10 * time.Second
+)
+
+// monitorLLDP keeps listening for LLDP frames on all interfaces and passes
+// every received result to the returned channel. Each interface keeps its
+// capture open, it's reopened only after an error. The returned function
+// stops the monitoring.
+func monitorLLDP(config *cmdConfig, networkConfigs map[string]*networkConfiguration) (<-chan lldp.DiscoveryResult, func()) {
+	ctx, cancel := context.WithCancel(config.ctx)
+	results := make(chan lldp.DiscoveryResult, len(networkConfigs))
+
+	for _, nwconfig := range networkConfigs {
+		ifname := nwconfig.link.Attrs().Name
+		hwAddr := *nwconfig.localHwAddr
+
+		go func() {
+			for ctx.Err() == nil {
+				lldpClient := lldp.NewClient(ctx, ifname, hwAddr)
+				if err := lldpClient.Monitor(results); err != nil {
+					klog.V(3).Infof("Cannot monitor LLDP on '%s': %v", ifname, err)
+
+					select {
+					case <-ctx.Done():
+					case <-time.After(lldpMonitorRetryInterval):
+					}
+				}
+			}
+		}()
+
+		klog.Infof("Started LLDP monitoring for '%s'", ifname)
+	}
+
+	return results, cancel
+}
+
+// lldpResultChanged returns true when the received LLDP information differs
+// from the information the interface was configured with.
+func lldpResultChanged(nwconfig *networkConfiguration, result lldp.DiscoveryResult) bool {
+	if nwconfig.peerHWAddr == nil || !bytes.Equal(*nwconfig.peerHWAddr, result.PeerMAC) {
+		return true
+	}
+
+	return nwconfig.portDescription != result.PortDescription ||
+		nwconfig.sysName != result.SysName ||
+		nwconfig.peerMTU != int(result.MTU) ||
+		!reflect.DeepEqual(nwconfig.mgmtAddresses, result.MgmtAddresses) ||
+		!reflect.DeepEqual(nwconfig.orgTLVs, result.OrgTLVs)
+}
+
+// reconcileLLDPResult updates the interface configuration from changed LLDP
+// information. The existing addresses and routes are replaced only when the
+// new information results in different addresses. It returns true when the
+// LLDP information changed.
+func reconcileLLDPResult(config *cmdConfig, networkConfigs map[string]*networkConfiguration, result lldp.DiscoveryResult) bool {
+	nwconfig, exists := networkConfigs[result.InterfaceName]
+	if !exists {
+		return false
+	}
+
+	nwconfig.lastLLDPDU = time.Now()
+
+	if !lldpResultChanged(nwconfig, result) {
+		return false
+	}
+
+	klog.Infof("LLDP information changed for interface '%s'", result.InterfaceName)
+
+	applyLLDPResult(nwconfig, result)
+	checkPeerMTU(map[string]*networkConfiguration{result.InterfaceName: nwconfig}, config.mtu)
+
+	lldpPeer, localAddr, prefixLen, err := selectPointToPointL3Address(nwconfig)
+	if err != nil {
+		klog.Warningf("%v, keeping the current configuration", err)
+		nodeEvents.warningf(reasonInvalidPeerAddress, "%v, keeping the current configuration", err)
+		nwconfig.configErr = err
+
+		return true
+	}
+
+	if nwconfig.localAddr != nil && nwconfig.localAddr.Equal(*localAddr) &&
+		nwconfig.lldpPeer.Equal(*lldpPeer) && nwconfig.prefixLen == prefixLen {
+		return true
+	}
+
+	deconfigureInterface(nwconfig)
+
+	nwconfig.lldpPeer = lldpPeer
+	nwconfig.localAddr = localAddr
+	nwconfig.prefixLen = prefixLen
+
+	configureInterfaces(map[string]*networkConfiguration{result.InterfaceName: nwconfig})
+
+	return true
+}
+
+// updateConfigFiles rewrites the gaudinet file and the systemd-networkd
+// configuration of the given interface.
+func updateConfigFiles(config *cmdConfig, networkConfigs map[string]*networkConfiguration, ifname string) {
+	if config.gaudinetfile != "" {
+		if err := WriteGaudiNet(config.gaudinetfile, networkConfigs); err != nil {
+			klog.Errorf("Error: %v\n", err)
+		}
+	}
+
+	if config.networkd != "" {
+		nwconfig, exists := networkConfigs[ifname]
+		if !exists {
+			return
+		}
+
+		if nwconfig.localAddr == nil {
+			DeleteSystemdNetworkd(config.networkd, []string{ifname})
+			return
+		}
+
+		if _, err := WriteSystemdNetworkd(config.networkd, map[string]*networkConfiguration{ifname: nwconfig}); err != nil {
+			klog.Errorf("Could not update systemd-networkd configuration: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/intel/network-operator/pkg/lldp"
+)
+
+func fakeLLDPResult(nwconfig *networkConfiguration) lldp.DiscoveryResult {
+	return lldp.DiscoveryResult{
+		InterfaceName:   nwconfig.link.Attrs().Name,
+		SysName:         nwconfig.sysName,
+		PortDescription: nwconfig.portDescription,
+		PeerMAC:         *nwconfig.peerHWAddr,
+		MgmtAddresses:   nwconfig.mgmtAddresses,
+		OrgTLVs:         nwconfig.orgTLVs,
+	}
+}
+
+func TestLLDPResultChanged(t *testing.T) {
+	nwconfig := getFakeNetworkDataConfigs()["eth_a"]
+
+	result := fakeLLDPResult(nwconfig)
+	if lldpResultChanged(nwconfig, result) {
+		t.Error("unchanged LLDP result detected as changed")
+	}
+
+	result.PortDescription = "no-alert 10.210.9.122/30"
+	if !lldpResultChanged(nwconfig, result) {
+		t.Error("changed port description not detected")
+	}
+
+	result = fakeLLDPResult(nwconfig)
+	result.PeerMAC = net.HardwareAddr{0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f}
+	if !lldpResultChanged(nwconfig, result) {
+		t.Error("changed peer MAC not detected")
+	}
+
+	result = fakeLLDPResult(nwconfig)
+	result.MgmtAddresses = []net.IP{net.IPv4(10, 0, 0, 1)}
+	if !lldpResultChanged(nwconfig, result) {
+		t.Error("changed management address not detected")
+	}
+}
+
+func TestReconcileLLDPResult(t *testing.T) {
+	var (
+		addedAddrs    []string
+		deletedAddrs  []string
+		addedRoutes   []string
+		deletedRoutes []string
+	)
+
+	networkLink.AddrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return []netlink.Addr{}, nil
+	}
+	networkLink.AddrAdd = func(link netlink.Link, addr *netlink.Addr) error {
+		addedAddrs = append(addedAddrs, addr.IPNet.String())
+		return nil
+	}
+	networkLink.AddrDel = func(link netlink.Link, addr *netlink.Addr) error {
+		deletedAddrs = append(deletedAddrs, addr.IPNet.String())
+		return nil
+	}
+	networkLink.RouteAppend = func(route *netlink.Route) error {
+		addedRoutes = append(addedRoutes, route.Dst.String()+" via "+route.Gw.String())
+		return nil
+	}
+	networkLink.RouteDel = func(route *netlink.Route) error {
+		deletedRoutes = append(deletedRoutes, route.Dst.String()+" via "+route.Gw.String())
+		return nil
+	}
+
+	config := &cmdConfig{mtu: 1500}
+	nwconfigs := getFakeNetworkDataConfigs()
+	_ = lldpResults(nwconfigs)
+
+	nwconfig := nwconfigs["eth_a"]
+
+	// unchanged information
+	if reconcileLLDPResult(config, nwconfigs, fakeLLDPResult(nwconfig)) {
+		t.Error("unchanged LLDP result reconciled")
+	}
+
+	// unknown interface
+	result := fakeLLDPResult(nwconfig)
+	result.InterfaceName = "foo"
+	if reconcileLLDPResult(config, nwconfigs, result) {
+		t.Error("LLDP result for an unknown interface reconciled")
+	}
+
+	// changed system name only, addresses are kept
+	result = fakeLLDPResult(nwconfig)
+	result.SysName = "switch-2"
+	if !reconcileLLDPResult(config, nwconfigs, result) || len(addedAddrs) != 0 || len(deletedAddrs) != 0 {
+		t.Errorf("unexpected address changes: added %v, deleted %v", addedAddrs, deletedAddrs)
+	}
+
+	// re-addressed switch port
+	result.PortDescription = "no-alert 10.210.9.122/30"
+	if !reconcileLLDPResult(config, nwconfigs, result) {
+		t.Fatal("changed LLDP result not reconciled")
+	}
+
+	if len(deletedAddrs) != 1 || deletedAddrs[0] != "10.210.8.121/30" {
+		t.Errorf("unexpected deleted addresses %v", deletedAddrs)
+	}
+	if len(deletedRoutes) != 1 || deletedRoutes[0] != "10.210.0.0/16 via 10.210.8.122" {
+		t.Errorf("unexpected deleted routes %v", deletedRoutes)
+	}
+	if len(addedAddrs) != 1 || addedAddrs[0] != "10.210.9.121/30" {
+		t.Errorf("unexpected added addresses %v", addedAddrs)
+	}
+	if len(addedRoutes) != 1 || addedRoutes[0] != "10.210.0.0/16 via 10.210.9.122" {
+		t.Errorf("unexpected added routes %v", addedRoutes)
+	}
+	if nwconfig.localAddr.String() != "10.210.9.121" || nwconfig.configErr != nil {
+		t.Errorf("unexpected local address %s: %v", nwconfig.localAddr, nwconfig.configErr)
+	}
+
+	// invalid port description keeps the current configuration
+	result.PortDescription = "no-alert"
+	if !reconcileLLDPResult(config, nwconfigs, result) {
+		t.Fatal("changed LLDP result not reconciled")
+	}
+	if nwconfig.localAddr.String() != "10.210.9.121" || nwconfig.configErr == nil {
+		t.Errorf("unexpected local address %s: %v", nwconfig.localAddr, nwconfig.configErr)
+	}
+}
+
+func TestUpdateConfigFiles(t *testing.T) {
+	testDir, err := os.MkdirTemp("", "networkoperator.")
+	if err != nil {
+		t.Fatalf("cannot create tmp dir: %v", err)
+	}
+	defer os.RemoveAll(testDir)
+
+	config := &cmdConfig{
+		networkd: testDir,
+	}
+
+	nwconfigs := getFakeNetworkDataConfigs()
+	_ = lldpResults(nwconfigs)
+
+	updateConfigFiles(config, nwconfigs, "eth_a")
+	if _, err := os.Stat(networkdFilename(testDir, "eth_a")); err != nil {
+		t.Errorf("networkd file not written: %v", err)
+	}
+	if _, err := os.Stat(networkdFilename(testDir, "eth_c")); err == nil {
+		t.Error("networkd file written for an unchanged interface")
+	}
+
+	nwconfigs["eth_a"].localAddr = nil
+	updateConfigFiles(config, nwconfigs, "eth_a")
+	if _, err := os.Stat(networkdFilename(testDir, "eth_a")); err == nil {
+		t.Error("networkd file not removed")
+	}
+}
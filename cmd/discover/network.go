@@ -22,44 +22,65 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
 	"k8s.io/klog/v2"
+
+	"github.com/intel/network-operator/pkg/lldp"
 )
 
 const (
-	driverPath       = "bus/pci/drivers/habanalabs/"
+	defaultDriver    = "habanalabs"
+	pciDriversPath   = "bus/pci/drivers/"
+	pciDevicesPath   = "bus/pci/devices/"
 	pciDevicePattern = "????:??:??.?"
 	netDevicePattern = "net/*"
 
 	noAddress = "none"
+
+	// routeProtocolDiscover marks the routes added by discover
+	routeProtocolDiscover netlink.RouteProtocol = 201
+
+	ipv4Bits = 32
+	ipv6Bits = 128
 )
 
 type networkLinkFn struct {
-	LinkByName    func(name string) (netlink.Link, error)
-	AddrList      func(link netlink.Link, family int) ([]netlink.Addr, error)
-	AddrAdd       func(link netlink.Link, addr *netlink.Addr) error
-	AddrDel       func(link netlink.Link, addr *netlink.Addr) error
-	LinkSubscribe func(ch chan<- netlink.LinkUpdate, done <-chan struct{}) error
-	RouteAppend   func(route *netlink.Route) error
-	LinkSetUp     func(link netlink.Link) error
-	LinkSetDown   func(link netlink.Link) error
-	LinkSetMTU    func(link netlink.Link, mtu int) error
+	LinkByName     func(name string) (netlink.Link, error)
+	AddrList       func(link netlink.Link, family int) ([]netlink.Addr, error)
+	AddrAdd        func(link netlink.Link, addr *netlink.Addr) error
+	AddrDel        func(link netlink.Link, addr *netlink.Addr) error
+	LinkSubscribe  func(ch chan<- netlink.LinkUpdate, done <-chan struct{}) error
+	AddrSubscribe  func(ch chan<- netlink.AddrUpdate, done <-chan struct{}) error
+	RouteSubscribe func(ch chan<- netlink.RouteUpdate, done <-chan struct{}) error
+	RouteAppend    func(route *netlink.Route) error
+	RouteDel       func(route *netlink.Route) error
+	RouteList      func(link netlink.Link, family int) ([]netlink.Route, error)
+	LinkSetUp      func(link netlink.Link) error
+	LinkSetDown    func(link netlink.Link) error
+	LinkSetMTU     func(link netlink.Link, mtu int) error
+	NeighList      func(linkIndex, family int) ([]netlink.Neigh, error)
 }
 
 var networkLink = networkLinkFn{
-	LinkByName:    netlink.LinkByName,
-	AddrList:      netlink.AddrList,
-	AddrAdd:       netlink.AddrAdd,
-	AddrDel:       netlink.AddrDel,
-	LinkSubscribe: netlink.LinkSubscribe,
-	RouteAppend:   netlink.RouteAppend,
-	LinkSetUp:     netlink.LinkSetUp,
-	LinkSetDown:   netlink.LinkSetDown,
-	LinkSetMTU:    netlink.LinkSetMTU,
+	LinkByName:     netlink.LinkByName,
+	AddrList:       netlink.AddrList,
+	AddrAdd:        netlink.AddrAdd,
+	AddrDel:        netlink.AddrDel,
+	LinkSubscribe:  netlink.LinkSubscribe,
+	AddrSubscribe:  netlink.AddrSubscribe,
+	RouteSubscribe: netlink.RouteSubscribe,
+	RouteAppend:    netlink.RouteAppend,
+	RouteDel:       netlink.RouteDel,
+	RouteList:      netlink.RouteList,
+	LinkSetUp:      netlink.LinkSetUp,
+	LinkSetDown:    netlink.LinkSetDown,
+	LinkSetMTU:     netlink.LinkSetMTU,
+	NeighList:      netlink.NeighList,
 }
 
 type networkConfiguration struct {
@@ -67,10 +88,26 @@ type networkConfiguration struct {
 	origState       net.Flags
 	expectResponse  bool
 	portDescription string
+	sysName         string
 	lldpPeer        *net.IP
 	localAddr       *net.IP
+	prefixLen       int
+	routedNetworks  []routedNetwork
+	addressParser   addressParser
+	mgmtFallback    bool
+	orgTLVs         []lldp.OrgTLV
+	mgmtAddresses   []net.IP
+	peerMTU         int
+	peerProbe       *peerProbe
+	lastLLDPDU      time.Time
 	peerHWAddr      *net.HardwareAddr
 	localHwAddr     *net.HardwareAddr
+	nmUnmanaged     bool
+	configErr       error
+	// errors of the configuration steps for the discovery report
+	stepErrors map[string]error
+	// all the selected interfaces, including this one
+	selected map[string]*networkConfiguration
 }
 
 func getSysfsRoot() string {
@@ -81,19 +118,99 @@ func getSysfsRoot() string {
 	return sysfsRoot
 }
 
-func sysfsDriverPath() string {
-	return filepath.Join(getSysfsRoot(), driverPath)
+func sysfsDriverPath(driver string) string {
+	return filepath.Join(getSysfsRoot(), pciDriversPath, driver)
+}
+
+// pciID identifies a PCI device by its vendor and device IDs, e.g. "8086:1593".
+type pciID struct {
+	vendor string
+	device string
+}
+
+func parsePCIIDs(ids []string) ([]pciID, error) {
+	parsed := make([]pciID, 0, len(ids))
+
+	for _, id := range ids {
+		vendor, device, found := strings.Cut(strings.ToLower(strings.TrimSpace(id)), ":")
+		if !found || !isPCIIDPart(vendor) || !isPCIIDPart(device) {
+			return nil, fmt.Errorf("Invalid PCI ID '%s', expected 'vendor:device', e.g. '8086:1593'", id)
+		}
+
+		parsed = append(parsed, pciID{vendor: vendor, device: device})
+	}
+
+	return parsed, nil
+}
+
+func isPCIIDPart(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+
+	_, err := strconv.ParseUint(s, 16, 16)
+
+	return err == nil
+}
+
+// readPCIIDFile reads a sysfs vendor or device file, e.g. "0x8086".
+func readPCIIDFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(string(data))), "0x")
+}
+
+// deviceSelector selects the PCI devices whose network interfaces are
+// configured, either by their driver, their PCI IDs or both. The interfaces
+// of the devices are further filtered with the include and exclude filters.
+type deviceSelector struct {
+	driver  string
+	pciIDs  []pciID
+	include interfaceFilter
+	exclude interfaceFilter
+}
+
+func (s deviceSelector) selects(iface interfaceInfo) bool {
+	if !s.include.empty() && !s.include.matches(iface) {
+		return false
+	}
+
+	return s.exclude.empty() || !s.exclude.matches(iface)
+}
+
+func (s deviceSelector) matchesPCIID(devicePath string) bool {
+	if len(s.pciIDs) == 0 {
+		return true
+	}
+
+	vendor := readPCIIDFile(filepath.Join(devicePath, "vendor"))
+	device := readPCIIDFile(filepath.Join(devicePath, "device"))
+
+	for _, id := range s.pciIDs {
+		if id.vendor == vendor && id.device == device {
+			return true
+		}
+	}
+
+	return false
 }
 
-func getNetworks() []string {
-	habanaNetDevices := []string{}
+func getNetworks(selector deviceSelector) []string {
+	netDevices := []string{}
+
+	pattern := filepath.Join(getSysfsRoot(), pciDevicesPath, pciDevicePattern)
+	if selector.driver != "" {
+		pattern = filepath.Join(sysfsDriverPath(selector.driver), pciDevicePattern)
+	}
 
-	pattern := filepath.Join(sysfsDriverPath(), pciDevicePattern)
 	paths, err := filepath.Glob(pattern)
 
 	if err != nil {
 		klog.Warningf("no PCI devices found")
-		return habanaNetDevices
+		return netDevices
 	}
 
 	for _, p := range paths {
@@ -103,19 +220,39 @@ func getNetworks() []string {
 			continue
 		}
 
+		if !selector.matchesPCIID(devicesymlinktarget) {
+			continue
+		}
+
+		driver := selector.driver
+		if driver == "" {
+			driver = pciDriver(devicesymlinktarget)
+		}
+
 		netdevicepattern := filepath.Join(devicesymlinktarget, netDevicePattern)
 		netdevices, err := filepath.Glob(netdevicepattern)
 		if err != nil {
 			klog.Warningf("Could not find network device files: %v", err)
 		}
 		for _, n := range netdevices {
-			name := filepath.Base(n)
-			habanaNetDevices = append(habanaNetDevices, name)
+			iface := interfaceInfo{
+				name:       filepath.Base(n),
+				pciAddress: filepath.Base(p),
+				driver:     driver,
+				port:       netDevPort(n),
+			}
+
+			if !selector.selects(iface) {
+				klog.V(3).Infof("Interface '%s' (%s port %d) not selected", iface.name, iface.pciAddress, iface.port)
+				continue
+			}
+
+			netDevices = append(netDevices, iface.name)
 		}
 
 	}
 
-	return habanaNetDevices
+	return netDevices
 }
 
 func getNetworkConfigs(ifacenames []string) map[string]*networkConfiguration {
@@ -138,38 +275,168 @@ func getNetworkConfigs(ifacenames []string) map[string]*networkConfiguration {
 	return links
 }
 
-func selectMask30L3Address(nwconfig *networkConfiguration) (*net.IP, *net.IP, error) {
-	var (
-		peerNetwork *net.IPNet
-		peeraddr    net.IP
-		localaddr   net.IP
-		err         error
-	)
+// toggleLowBits returns a copy of the address with the given lowest bits toggled.
+func toggleLowBits(addr net.IP, bits byte) net.IP {
+	if addr4 := addr.To4(); addr4 != nil {
+		addr = addr4
+	}
+
+	toggled := make(net.IP, len(addr))
+	copy(toggled, addr)
+	toggled[len(toggled)-1] ^= bits
+
+	return toggled
+}
 
-	substrings := strings.Split(nwconfig.portDescription, " ")
-	if len(substrings) < 2 {
-		return nil, nil, fmt.Errorf("interface '%s' could not split string '%s'",
-			nwconfig.link.Attrs().Name, nwconfig.portDescription)
+func selectPointToPointL3Address(nwconfig *networkConfiguration) (*net.IP, *net.IP, int, error) {
+	var localaddr net.IP
+
+	parser := nwconfig.addressParser
+	if parser == nil {
+		parser = parsePortDescription
 	}
 
-	peeraddr, peerNetwork, err = net.ParseCIDR(substrings[1])
+	peeraddr, peerNetwork, err := parser(nwconfig)
+	if err != nil && nwconfig.mgmtFallback && len(nwconfig.mgmtAddresses) > 0 {
+		klog.Warningf("%v, falling back to the LLDP management address", err)
+
+		peeraddr, peerNetwork, err = parseMgmtAddress(nwconfig)
+	}
 	if err != nil {
-		return nil, nil, fmt.Errorf("interface '%s' could not parse '%s': %v",
-			nwconfig.link.Attrs().Name, nwconfig.portDescription, err)
+		return nil, nil, 0, err
 	}
 
-	mask, _ := peerNetwork.Mask.Size()
-	if mask == 30 {
-		// toggle the lowest two bits of the switch IPv4 address to get
+	mask, bits := peerNetwork.Mask.Size()
+
+	switch {
+	case bits == ipv4Bits && mask == int(RouteMaskPointToPoint),
+		bits == ipv6Bits && mask == int(RouteMaskPointToPointIPv6Wide):
+		// toggle the lowest two bits of the switch address to get
 		// the local address
-		peer := peeraddr.To4()
-		localaddr = net.IPv4(peer[0], peer[1], peer[2], peer[3]^0x3)
-	} else {
-		err = fmt.Errorf("interface '%s' mask is %d, not the expected 30",
-			nwconfig.link.Attrs().Name, mask)
+		localaddr = toggleLowBits(peeraddr, 0x3)
+	case bits == ipv6Bits && mask == int(RouteMaskPointToPointIPv6):
+		// a /127 has only two addresses, toggle the lowest bit
+		localaddr = toggleLowBits(peeraddr, 0x1)
+	case bits == ipv4Bits:
+		err = fmt.Errorf("interface '%s' mask is %d, not the expected %d",
+			nwconfig.link.Attrs().Name, mask, RouteMaskPointToPoint)
+	default:
+		err = fmt.Errorf("interface '%s' mask is %d, not the expected %d or %d",
+			nwconfig.link.Attrs().Name, mask, RouteMaskPointToPointIPv6, RouteMaskPointToPointIPv6Wide)
+	}
+
+	return &peeraddr, &localaddr, mask, err
+}
+
+func isIPv6(nwconfig *networkConfiguration) bool {
+	return nwconfig.localAddr != nil && nwconfig.localAddr.To4() == nil
+}
+
+// addrFamily returns the netlink address family of the LLDP derived address,
+// defaulting to IPv4 when no address has been derived.
+func addrFamily(nwconfig *networkConfiguration) int {
+	if isIPv6(nwconfig) {
+		return netlink.FAMILY_V6
+	}
+
+	return netlink.FAMILY_V4
+}
+
+func pointToPointMask(nwconfig *networkConfiguration) net.IPMask {
+	if isIPv6(nwconfig) {
+		if nwconfig.prefixLen == 0 {
+			return net.CIDRMask(int(RouteMaskPointToPointIPv6), ipv6Bits)
+		}
+		return net.CIDRMask(nwconfig.prefixLen, ipv6Bits)
+	}
+
+	if nwconfig.prefixLen == 0 {
+		return net.CIDRMask(int(RouteMaskPointToPoint), ipv4Bits)
+	}
+	return net.CIDRMask(nwconfig.prefixLen, ipv4Bits)
+}
+
+// routedNetwork is a routed scale-out network reachable via the LLDP peer.
+// It is either an explicit destination network or a prefix length that
+// is applied to the local address.
+type routedNetwork struct {
+	prefixLen int
+	network   *net.IPNet
+}
+
+// parseRoutedNetworks parses routed networks given either in CIDR notation,
+// e.g. "10.192.0.0/12", or as a prefix length, e.g. "/20" or "20".
+func parseRoutedNetworks(networks []string) ([]routedNetwork, error) {
+	routed := []routedNetwork{}
+
+	for _, network := range networks {
+		network = strings.TrimSpace(network)
+		if network == "" {
+			continue
+		}
+
+		if !strings.Contains(strings.TrimPrefix(network, "/"), "/") {
+			prefixLen, err := strconv.Atoi(strings.TrimPrefix(network, "/"))
+			if err != nil || prefixLen < 1 || prefixLen > ipv6Bits {
+				return nil, fmt.Errorf("invalid routed network prefix length '%s'", network)
+			}
+
+			routed = append(routed, routedNetwork{prefixLen: prefixLen})
+
+			continue
+		}
+
+		_, ipnet, err := net.ParseCIDR(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid routed network '%s': %v", network, err)
+		}
+
+		routed = append(routed, routedNetwork{network: ipnet})
+	}
+
+	return routed, nil
+}
+
+// routedNetworkDestinations returns the routed network destinations matching
+// the address family of the interface. Without configured routed networks
+// the local address is routed as a /16 for IPv4 and as a /64 for IPv6.
+func routedNetworkDestinations(nwconfig *networkConfiguration) []*net.IPNet {
+	bits := ipv4Bits
+	mask := RouteMaskRoutedNetwork
+
+	if isIPv6(nwconfig) {
+		bits = ipv6Bits
+		mask = RouteMaskRoutedNetworkIPv6
+	}
+
+	routed := nwconfig.routedNetworks
+	if len(routed) == 0 {
+		routed = []routedNetwork{{prefixLen: int(mask)}}
+	}
+
+	destinations := []*net.IPNet{}
+
+	for _, r := range routed {
+		if r.network != nil {
+			if (r.network.IP.To4() == nil) == isIPv6(nwconfig) {
+				destinations = append(destinations, r.network)
+			}
+
+			continue
+		}
+
+		if r.prefixLen > bits {
+			continue
+		}
+
+		networkMask := net.CIDRMask(r.prefixLen, bits)
+		destinations = append(destinations, &net.IPNet{
+			IP:   nwconfig.localAddr.Mask(networkMask),
+			Mask: networkMask,
+		})
 	}
 
-	return &peeraddr, &localaddr, err
+	return destinations
 }
 
 func logResults(config *cmdConfig, networkConfigs map[string]*networkConfiguration) {
@@ -207,7 +474,14 @@ func logResults(config *cmdConfig, networkConfigs map[string]*networkConfigurati
 			if nwconfig.localAddr != nil {
 				addr = nwconfig.localAddr.String()
 			}
-			klog.V(3).Infof("\tLocal /30 LLDP address: %s", addr)
+			klog.V(3).Infof("\tLocal point-to-point LLDP address: %s", addr)
+
+			if len(nwconfig.mgmtAddresses) > 0 {
+				klog.V(3).Infof("\tPeer management addresses: %v", nwconfig.mgmtAddresses)
+			}
+			if nwconfig.peerMTU > 0 {
+				klog.V(3).Infof("\tPeer MTU: %d", nwconfig.peerMTU)
+			}
 		}
 	}
 }
@@ -217,14 +491,22 @@ func lldpResults(networkConfigs map[string]*networkConfiguration) bool {
 
 	for _, nwconfig := range networkConfigs {
 
-		lldpPeer, localAddr, err := selectMask30L3Address(nwconfig)
+		lldpPeer, localAddr, prefixLen, err := selectPointToPointL3Address(nwconfig)
 		if err == nil {
 			nwconfig.lldpPeer = lldpPeer
 			nwconfig.localAddr = localAddr
+			nwconfig.prefixLen = prefixLen
 			foundpeers = true
 		} else {
 			klog.Warning(err.Error())
+
+			// interfaces without LLDP information are reported as timeouts
+			if nwconfig.peerHWAddr != nil {
+				nodeEvents.warningf(reasonInvalidPeerAddress, "%v", err)
+			}
 		}
+		nwconfig.configErr = err
+		nwconfig.setStepError(stepAddress, err)
 	}
 
 	return foundpeers
@@ -272,6 +554,7 @@ func interfacesUp(networkConfigs map[string]*networkConfiguration) error {
 				nwconfig.expectResponse = true
 			} else {
 				klog.Warningf("Cannot set link '%s' up: %v", nwconfig.link.Attrs().Name, err)
+				nwconfig.setStepError(stepLinkUp, err)
 				continue
 			}
 		}
@@ -313,97 +596,275 @@ type RouteMask int
 const (
 	RouteMaskRoutedNetwork RouteMask = 16
 	RouteMaskPointToPoint  RouteMask = 30
+
+	RouteMaskRoutedNetworkIPv6 RouteMask = 64
+	RouteMaskPointToPointIPv6  RouteMask = 127
+	// a /126 is accepted for fabrics that mirror the IPv4 /30 addressing
+	RouteMaskPointToPointIPv6Wide RouteMask = 126
 )
 
-func addRoute(nwconfig *networkConfiguration, mask RouteMask) error {
+type routeType int
+
+const (
+	routeRoutedNetwork routeType = iota
+	routePointToPoint
+)
+
+func addRoute(nwconfig *networkConfiguration, rtype routeType) error {
 	var (
 		err             error
+		destinations    []*net.IPNet
 		networkSrc      net.IP
 		networkGateway  net.IP
 		networkScope    netlink.Scope
 		networkProtocol netlink.RouteProtocol
-		routeStr        string
+		gatewayStr      string
 	)
 
-	networkMask := net.CIDRMask(int(mask), 32)
 	if nwconfig.localAddr == nil {
 		return fmt.Errorf("interface '%s' has no local address", nwconfig.link.Attrs().Name)
 	}
-	networkAddr := nwconfig.localAddr.Mask(networkMask)
 
-	switch mask {
-	case RouteMaskRoutedNetwork:
-		// no protocol set in order to be identical to previous
-		// configuration
+	switch rtype {
+	case routeRoutedNetwork:
+		// tag the routes so that only ours are removed
+		destinations = routedNetworkDestinations(nwconfig)
+		networkProtocol = routeProtocolDiscover
 		networkGateway = *nwconfig.lldpPeer
-		routeStr = " gateway " + networkGateway.String()
-
-	case RouteMaskPointToPoint:
-		// use protocol 'kernel' to create an identical /30 route as
-		// added by the kernel
+		gatewayStr = " gateway " + networkGateway.String()
+
+	case routePointToPoint:
+		// use protocol 'kernel' to create an identical point-to-point
+		// route as added by the kernel
+		networkMask := pointToPointMask(nwconfig)
+		destinations = []*net.IPNet{{
+			IP:   nwconfig.localAddr.Mask(networkMask),
+			Mask: networkMask,
+		}}
 		networkProtocol = unix.RTPROT_KERNEL
-		networkScope = netlink.SCOPE_LINK
 		networkSrc = *nwconfig.localAddr
+		if !isIPv6(nwconfig) {
+			networkScope = netlink.SCOPE_LINK
+		}
 	}
 
-	newRoute := &netlink.Route{
-		LinkIndex: nwconfig.link.Attrs().Index,
-		Scope:     networkScope,
-		Protocol:  networkProtocol,
-		Dst: &net.IPNet{
-			IP:   networkAddr,
-			Mask: networkMask,
-		},
-		Src: networkSrc,
-		Gw:  networkGateway,
-	}
+	for _, dst := range destinations {
+		newRoute := &netlink.Route{
+			LinkIndex: nwconfig.link.Attrs().Index,
+			Scope:     networkScope,
+			Protocol:  networkProtocol,
+			Dst:       dst,
+			Src:       networkSrc,
+			Gw:        networkGateway,
+		}
 
-	routeStr = newRoute.Dst.String() + routeStr
+		routeStr := newRoute.Dst.String() + gatewayStr
 
-	if err = networkLink.RouteAppend(newRoute); err == nil {
-		klog.V(3).Infof("Configured route %s for interface '%s'",
-			routeStr, nwconfig.link.Attrs().Name)
-	} else {
-		if errors.Is(err, os.ErrExist) {
-			var noerr error
-			err = noerr
+		if routeErr := networkLink.RouteAppend(newRoute); routeErr == nil {
+			klog.V(3).Infof("Configured route %s for interface '%s'",
+				routeStr, nwconfig.link.Attrs().Name)
+		} else if errors.Is(routeErr, os.ErrExist) {
 			klog.V(3).Infof("Route %s already exists for interface '%s'",
 				routeStr, nwconfig.link.Attrs().Name)
 		} else {
 			klog.Warningf("Could not add route %s for interface '%s': %v",
-				routeStr, nwconfig.link.Attrs().Name, err)
+				routeStr, nwconfig.link.Attrs().Name, routeErr)
+			nodeEvents.warningf(reasonRouteConfigFailed, "Could not add route %s for interface '%s': %v",
+				routeStr, nwconfig.link.Attrs().Name, routeErr)
+			err = routeErr
 		}
 	}
 
 	return err
 }
 
+// checkPeerMTU warns about interfaces where the switch advertises a smaller
+// MTU than the one configured for the interface.
+func checkPeerMTU(networkConfigurations map[string]*networkConfiguration, mtu int) int {
+	mismatches := 0
+
+	for _, nwconfig := range networkConfigurations {
+		if nwconfig.peerMTU > 0 && nwconfig.peerMTU < mtu {
+			klog.Warningf("Interface '%s' MTU %d is larger than the MTU %d advertised by the switch",
+				nwconfig.link.Attrs().Name, mtu, nwconfig.peerMTU)
+
+			mismatches++
+		}
+	}
+
+	return mismatches
+}
+
 func interfacesSetMTU(networkConfigurations map[string]*networkConfiguration, mtu int) {
 	for _, nwconfig := range networkConfigurations {
-		if err := networkLink.LinkSetMTU(nwconfig.link, mtu); err != nil {
+		err := networkLink.LinkSetMTU(nwconfig.link, mtu)
+		if err != nil {
 			klog.Warningf("Could not set MTU %d for interface '%s': %v",
 				mtu, nwconfig.link.Attrs().Name, err)
 		}
+
+		nwconfig.setStepError(stepMTU, err)
+	}
+}
+
+// ownAddress returns true for the addresses added by discover: the ones in
+// the address record and the address derived for the interface, also when
+// added by an older version without the record.
+func ownAddress(nwconfig *networkConfiguration, addr netlink.Addr) bool {
+	if addr.IPNet == nil {
+		return false
+	}
+
+	if nwconfig.localAddr != nil && addr.IPNet.IP.Equal(*nwconfig.localAddr) {
+		return true
 	}
+
+	return ownedAddresses.contains(nwconfig.link.Attrs().Name, addr.IPNet)
 }
 
-func removeExistingIPs(networkConfigs map[string]*networkConfiguration) error {
+// removableAddress returns true for the addresses removeExistingIPs removes.
+func removableAddress(nwconfig *networkConfiguration, addr netlink.Addr, flush bool) bool {
+	// IPv6 neighbor discovery needs the link-local address
+	if addr.IPNet != nil && addr.IPNet.IP.IsLinkLocalUnicast() {
+		return false
+	}
+
+	return flush || ownAddress(nwconfig, addr)
+}
+
+// routeString returns the destination and the gateway of the route.
+func routeString(route netlink.Route) string {
+	routeStr := "default"
+	if route.Dst != nil {
+		routeStr = route.Dst.String()
+	}
+	if route.Gw != nil {
+		routeStr += " via " + route.Gw.String()
+	}
+
+	return routeStr
+}
+
+// discoverRoutes returns the routes discover has added to the link.
+func discoverRoutes(link netlink.Link) ([]netlink.Route, error) {
+	routes, err := networkLink.RouteList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := []netlink.Route{}
+
+	for _, route := range routes {
+		if route.Protocol == routeProtocolDiscover {
+			owned = append(owned, route)
+		}
+	}
+
+	return owned, nil
+}
+
+// removeExistingIPs removes the routes and addresses added by discover from
+// the interfaces. With flush all addresses but the link-local ones are
+// removed.
+func removeExistingIPs(networkConfigs map[string]*networkConfiguration, flush bool) error {
 	for _, nwconfig := range networkConfigs {
-		addrs, err := networkLink.AddrList(nwconfig.link, netlink.FAMILY_V4)
+		routes, err := discoverRoutes(nwconfig.link)
+		if err != nil {
+			return err
+		}
+
+		for _, route := range routes {
+			if err := networkLink.RouteDel(&route); err != nil && !errors.Is(err, unix.ESRCH) {
+				return err
+			}
+		}
+
+		addrs, err := networkLink.AddrList(nwconfig.link, netlink.FAMILY_ALL)
 		if err != nil {
 			return err
 		}
 
 		for _, addr := range addrs {
+			if !removableAddress(nwconfig, addr, flush) {
+				continue
+			}
+
 			if err := networkLink.AddrDel(nwconfig.link, &addr); err != nil {
 				return err
 			}
 		}
+
+		if err := ownedAddresses.forget(nwconfig.link.Attrs().Name); err != nil {
+			klog.Warningf("Could not update the address record: %v", err)
+		}
 	}
 
 	return nil
 }
 
+// deconfigureInterface removes the LLDP derived address and the routes via
+// the LLDP peer from the interface.
+func deconfigureInterface(nwconfig *networkConfiguration) {
+	if nwconfig.localAddr == nil {
+		return
+	}
+
+	ifname := nwconfig.link.Attrs().Name
+
+	if nmProfiles != nil {
+		nmProfiles.deconfigure(ifname)
+
+		nwconfig.lldpPeer = nil
+		nwconfig.localAddr = nil
+		nwconfig.prefixLen = 0
+
+		return
+	}
+
+	if nwconfig.lldpPeer != nil {
+		for _, dst := range routedNetworkDestinations(nwconfig) {
+			route := &netlink.Route{
+				LinkIndex: nwconfig.link.Attrs().Index,
+				Dst:       dst,
+				Gw:        *nwconfig.lldpPeer,
+			}
+
+			if err := networkLink.RouteDel(route); err != nil && !errors.Is(err, unix.ESRCH) {
+				klog.Warningf("Could not remove route %s for interface '%s': %v", dst, ifname, err)
+			}
+		}
+	}
+
+	addr := &netlink.Addr{
+		IPNet: &net.IPNet{
+			IP:   *nwconfig.localAddr,
+			Mask: pointToPointMask(nwconfig),
+		},
+	}
+
+	// removing the address removes the point-to-point network route as well
+	if err := networkLink.AddrDel(nwconfig.link, addr); err != nil && !errors.Is(err, unix.EADDRNOTAVAIL) {
+		klog.Warningf("Could not remove address %s from interface '%s': %v", addr.IPNet, ifname, err)
+	} else {
+		klog.Infof("Removed address %s from interface '%s'", addr.IPNet, ifname)
+
+		if err := ownedAddresses.remove(ifname, addr.IPNet); err != nil {
+			klog.Warningf("Could not update the address record: %v", err)
+		}
+	}
+
+	nwconfig.lldpPeer = nil
+	nwconfig.localAddr = nil
+	nwconfig.prefixLen = 0
+}
+
+// configFailed records the failure to configure the interface.
+func configFailed(nwconfig *networkConfiguration, err error) {
+	nwconfig.configErr = err
+	nwconfig.setStepError(stepConfigure, err)
+	configFailures.WithLabelValues(nwconfig.link.Attrs().Name).Inc()
+}
+
 func configureInterfaces(networkConfigs map[string]*networkConfiguration) (int, int) {
 	configured := 0
 
@@ -414,10 +875,27 @@ func configureInterfaces(networkConfigs map[string]*networkConfiguration) (int,
 			continue
 		}
 
-		addrs, err := networkLink.AddrList(nwconfig.link, netlink.FAMILY_V4)
 		ifname := nwconfig.link.Attrs().Name
+		configAttempts.WithLabelValues(ifname).Inc()
+
+		if nmProfiles != nil {
+			if err := nmProfiles.configure(nwconfig); err != nil {
+				klog.Warningf("Could not apply connection profile for interface '%s': %v", ifname, err)
+				nodeEvents.warningf(reasonAddressConfigFailed, "Could not apply connection profile for interface '%s': %v", ifname, err)
+				configFailed(nwconfig, err)
+				continue
+			}
+
+			nwconfig.configErr = nil
+			nwconfig.setStepError(stepConfigure, nil)
+			configured++
+			continue
+		}
+
+		addrs, err := networkLink.AddrList(nwconfig.link, addrFamily(nwconfig))
 		if err != nil {
 			klog.Warningf("Could not get addresses for link '%s': %v", ifname, err)
+			configFailed(nwconfig, err)
 			continue
 		}
 
@@ -438,30 +916,46 @@ func configureInterfaces(networkConfigs map[string]*networkConfiguration) (int,
 			newlinkaddr := &netlink.Addr{
 				IPNet: &net.IPNet{
 					IP:   *nwconfig.localAddr,
-					Mask: net.CIDRMask(30, 32),
+					Mask: pointToPointMask(nwconfig),
 				},
 			}
-			// AddrAdd will add the corresponding /30 network route
+			if isIPv6(nwconfig) {
+				// the address is unique on the point-to-point link,
+				// make it usable right away
+				newlinkaddr.Flags = unix.IFA_F_NODAD
+			}
+			// AddrAdd will add the corresponding point-to-point network route
 			if err := networkLink.AddrAdd(nwconfig.link, newlinkaddr); err != nil {
 				klog.Warningf("Could not configure address %s for interface '%s': %v",
 					nwconfig.localAddr.String(), ifname, err)
+				nodeEvents.warningf(reasonAddressConfigFailed, "Could not configure address %s for interface '%s': %v",
+					nwconfig.localAddr.String(), ifname, err)
+				configFailed(nwconfig, err)
 				continue
 			}
 
 			klog.Infof("Configured address and route %s for interface '%s'",
 				newlinkaddr.IPNet.String(), ifname)
+
+			if err := ownedAddresses.add(ifname, newlinkaddr.IPNet); err != nil {
+				klog.Warningf("Could not record the address of interface '%s': %v", ifname, err)
+			}
 		} else {
 			// IP address exists, but we need to ensure the
-			// existence of the corresponding /30 network route
-			if err = addRoute(nwconfig, RouteMaskPointToPoint); err != nil {
+			// existence of the corresponding point-to-point network route
+			if err = addRoute(nwconfig, routePointToPoint); err != nil {
+				configFailed(nwconfig, err)
 				continue
 			}
 		}
 
-		if err = addRoute(nwconfig, RouteMaskRoutedNetwork); err != nil {
+		if err = addRoute(nwconfig, routeRoutedNetwork); err != nil {
+			configFailed(nwconfig, err)
 			continue
 		}
 
+		nwconfig.configErr = nil
+		nwconfig.setStepError(stepConfigure, nil)
 		configured++
 	}
 
@@ -22,9 +22,11 @@ import (
 	"net"
 	"os"
 	"path"
+	"slices"
 	"testing"
 
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -32,7 +34,7 @@ const (
 	netDevicePath   = "net"
 )
 
-func TestSelectMask30L3Address(t *testing.T) {
+func TestSelectPointToPointL3Address(t *testing.T) {
 	expectedpeer := net.IPv4(10, 210, 8, 122)
 	expectedaddr := net.IPv4(10, 210, 8, 121)
 
@@ -45,13 +47,16 @@ func TestSelectMask30L3Address(t *testing.T) {
 		portDescription: "no-alert " + expectedpeer.String() + "/30",
 	}
 
-	peeraddr, localaddr, err := selectMask30L3Address(&nwconfig)
+	peeraddr, localaddr, prefixlen, err := selectPointToPointL3Address(&nwconfig)
 	if !peeraddr.Equal(expectedpeer) {
 		t.Errorf("Peer addresses do not match, expected %s got %s: %v", expectedpeer.String(), peeraddr.String(), err)
 	}
 	if !localaddr.Equal(expectedaddr) {
 		t.Errorf("Local addresses do not match, expected %s got %s: %v", expectedaddr.String(), localaddr.String(), err)
 	}
+	if prefixlen != 30 {
+		t.Errorf("Prefix length does not match, expected 30 got %d", prefixlen)
+	}
 
 	addrmask := "/16"
 	addrtext := "10.210.8.122"
@@ -63,13 +68,153 @@ func TestSelectMask30L3Address(t *testing.T) {
 		},
 		portDescription: "no-alert " + addrtext + addrmask,
 	}
-	peeraddr, localaddr, err = selectMask30L3Address(&nwconfig)
+	peeraddr, localaddr, _, err = selectPointToPointL3Address(&nwconfig)
 	if err == nil || peeraddr.String() != addrtext || localaddr.String() != "<nil>" {
 		t.Errorf("netmask %s unexpectedly returned values '%s', '%s' or no error '%v'",
 			addrmask, peeraddr.String(), localaddr.String(), err)
 	}
 }
 
+func TestSelectPointToPointL3AddressIPv6(t *testing.T) {
+	tcases := []struct {
+		name         string
+		peer         string
+		expectedaddr string
+		expectedlen  int
+		expectErr    bool
+	}{
+		{
+			name:         "/127 upper address",
+			peer:         "fd00:10:210::1/127",
+			expectedaddr: "fd00:10:210::",
+			expectedlen:  127,
+		},
+		{
+			name:         "/127 lower address",
+			peer:         "fd00:10:210::8/127",
+			expectedaddr: "fd00:10:210::9",
+			expectedlen:  127,
+		},
+		{
+			name:         "/126",
+			peer:         "fd00:10:210::2/126",
+			expectedaddr: "fd00:10:210::1",
+			expectedlen:  126,
+		},
+		{
+			name:      "/64",
+			peer:      "fd00:10:210::2/64",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcases {
+		nwconfig := networkConfiguration{
+			link: &fakeLink{
+				fakeAttrs: netlink.LinkAttrs{
+					Name: "eth_a",
+				},
+			},
+			portDescription: "no-alert " + tc.peer,
+		}
+
+		_, localaddr, prefixlen, err := selectPointToPointL3Address(&nwconfig)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+
+		if err != nil || localaddr.String() != tc.expectedaddr || prefixlen != tc.expectedlen {
+			t.Errorf("%s: expected %s/%d, got %s/%d: %v", tc.name,
+				tc.expectedaddr, tc.expectedlen, localaddr.String(), prefixlen, err)
+		}
+	}
+}
+
+func TestAddRouteIPv6(t *testing.T) {
+	fnd := getFakeNetworkData()["eth_a"]
+
+	var routes []*netlink.Route
+	networkLink.RouteAppend = func(route *netlink.Route) error {
+		routes = append(routes, route)
+		return nil
+	}
+
+	peer := net.ParseIP("fd00:10:210::1")
+	local := net.ParseIP("fd00:10:210::")
+	fnd.nwconfig.lldpPeer = &peer
+	fnd.nwconfig.localAddr = &local
+	fnd.nwconfig.prefixLen = 127
+
+	if err := addRoute(&fnd.nwconfig, routePointToPoint); err != nil {
+		t.Errorf("add route failed: %v", err)
+	}
+	if err := addRoute(&fnd.nwconfig, routeRoutedNetwork); err != nil {
+		t.Errorf("add route failed: %v", err)
+	}
+
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(routes))
+	}
+	if routes[0].Dst.String() != "fd00:10:210::/127" || routes[0].Scope != netlink.SCOPE_UNIVERSE {
+		t.Errorf("unexpected point-to-point route %s scope %d", routes[0].Dst, routes[0].Scope)
+	}
+	if routes[1].Dst.String() != "fd00:10:210::/64" || !routes[1].Gw.Equal(peer) {
+		t.Errorf("unexpected routed network route %s via %s", routes[1].Dst, routes[1].Gw)
+	}
+}
+
+func TestParseRoutedNetworks(t *testing.T) {
+	routed, err := parseRoutedNetworks([]string{"10.192.0.0/12", "/20", "24", " ", "fd00:10::/48"})
+	if err != nil {
+		t.Fatalf("parsing routed networks failed: %v", err)
+	}
+
+	if len(routed) != 4 {
+		t.Fatalf("expected 4 routed networks, got %d", len(routed))
+	}
+	if routed[0].network.String() != "10.192.0.0/12" || routed[1].prefixLen != 20 ||
+		routed[2].prefixLen != 24 || routed[3].network.String() != "fd00:10::/48" {
+		t.Errorf("unexpected routed networks %+v", routed)
+	}
+
+	for _, bad := range []string{"10.192.0.0", "/0", "/129", "10.192.0.0/33", "foo"} {
+		if _, err := parseRoutedNetworks([]string{bad}); err == nil {
+			t.Errorf("parsing '%s' succeeded while it shouldn't have", bad)
+		}
+	}
+}
+
+func TestRoutedNetworkDestinations(t *testing.T) {
+	local := net.IPv4(10, 210, 8, 121)
+	nwconfig := networkConfiguration{
+		localAddr: &local,
+	}
+
+	dsts := routedNetworkDestinations(&nwconfig)
+	if len(dsts) != 1 || dsts[0].String() != "10.210.0.0/16" {
+		t.Errorf("unexpected default destinations %v", dsts)
+	}
+
+	nwconfig.routedNetworks, _ = parseRoutedNetworks([]string{"10.192.0.0/12", "/20", "/64", "fd00:10::/48"})
+
+	dsts = routedNetworkDestinations(&nwconfig)
+	if len(dsts) != 2 || dsts[0].String() != "10.192.0.0/12" || dsts[1].String() != "10.210.0.0/20" {
+		t.Errorf("unexpected IPv4 destinations %v", dsts)
+	}
+
+	local6 := net.ParseIP("fd00:10:210:8::")
+	nwconfig.localAddr = &local6
+
+	dsts = routedNetworkDestinations(&nwconfig)
+	if len(dsts) != 3 || dsts[0].String() != "fd00::/20" ||
+		dsts[1].String() != "fd00:10:210:8::/64" || dsts[2].String() != "fd00:10::/48" {
+		t.Errorf("unexpected IPv6 destinations %v", dsts)
+	}
+}
+
 func TestSysFsRoot(t *testing.T) {
 	testSysfsRoot, err := os.MkdirTemp("", "networkoperator.")
 	if err != nil {
@@ -85,14 +230,14 @@ func TestSysFsRoot(t *testing.T) {
 	}
 
 	expectedpath := path.Join(testSysfsRoot, "bus/pci/drivers/habanalabs")
-	if detectedsysfsdriverpath := sysfsDriverPath(); detectedsysfsdriverpath != expectedpath {
+	if detectedsysfsdriverpath := sysfsDriverPath(defaultDriver); detectedsysfsdriverpath != expectedpath {
 		t.Errorf("got sysfs driver path '%s', expected '%s'", detectedsysfsdriverpath, expectedpath)
 	}
 
 }
 
 func writeFakeSysfsEntries(testSysfsRoot string, devices map[string]fakeNetworkTestData, t *testing.T) {
-	driverdir := path.Join(testSysfsRoot, driverPath)
+	driverdir := sysfsDriverPath(defaultDriver)
 	if err := os.MkdirAll(driverdir, 0755); err != nil {
 		t.Errorf("cannot create fake driver dir '%s': %v", driverdir, err)
 	}
@@ -233,14 +378,14 @@ func TestFakeSysfs(t *testing.T) {
 	os.Setenv("SYSFS_ROOT", testSysfsRoot)
 
 	// no devices in the fake sysfs directory
-	for _, d := range getNetworks() {
+	for _, d := range getNetworks(deviceSelector{driver: defaultDriver}) {
 		t.Errorf("no devices should have been found: %s", d)
 	}
 
 	devices := getFakeNetworkData()
 	writeFakeSysfsEntries(testSysfsRoot, devices, t)
 
-	for _, d := range getNetworks() {
+	for _, d := range getNetworks(deviceSelector{driver: defaultDriver}) {
 		if _, exists := devices[d]; !exists {
 			t.Errorf("found unexpected device '%s'", d)
 		}
@@ -251,6 +396,58 @@ func TestFakeSysfs(t *testing.T) {
 	}
 }
 
+func TestPCIIDSelection(t *testing.T) {
+	testSysfsRoot, err := os.MkdirTemp("", "networkoperator.")
+	if err != nil {
+		t.Errorf("cannot create tmp dir: %v", err)
+	}
+	defer os.RemoveAll(testSysfsRoot)
+
+	os.Setenv("SYSFS_ROOT", testSysfsRoot)
+
+	devices := getFakeNetworkData()
+	writeFakeSysfsEntries(testSysfsRoot, devices, t)
+
+	for netdev, fakenwconfig := range devices {
+		vendor, device := "0x1da3", "0x1020"
+		if netdev == "eth_a" {
+			vendor, device = "0x8086", "0x1593"
+		}
+
+		devicedir := path.Join(testSysfsRoot, sysfsDevicePath, fakenwconfig.pcidevice)
+		if err := os.WriteFile(path.Join(devicedir, "vendor"), []byte(vendor+"\n"), 0644); err != nil {
+			t.Fatalf("cannot write vendor file: %v", err)
+		}
+		if err := os.WriteFile(path.Join(devicedir, "device"), []byte(device+"\n"), 0644); err != nil {
+			t.Fatalf("cannot write device file: %v", err)
+		}
+	}
+
+	pciIDs, err := parsePCIIDs([]string{"8086:1593", "8086:159B"})
+	if err != nil {
+		t.Fatalf("cannot parse PCI IDs: %v", err)
+	}
+
+	if devs := getNetworks(deviceSelector{pciIDs: pciIDs}); len(devs) != 1 || devs[0] != "eth_a" {
+		t.Errorf("expected only eth_a to be selected by PCI ID, got %v", devs)
+	}
+
+	// the devices are bound to the default driver only
+	if devs := getNetworks(deviceSelector{driver: "ice", pciIDs: pciIDs}); len(devs) != 0 {
+		t.Errorf("no devices should have been found for driver 'ice': %v", devs)
+	}
+
+	if devs := getNetworks(deviceSelector{driver: defaultDriver, pciIDs: pciIDs}); len(devs) != 1 {
+		t.Errorf("expected one device with driver and PCI ID, got %v", devs)
+	}
+
+	for _, invalid := range []string{"8086", "8086:15930", "808g:1593", ":1593"} {
+		if _, err := parsePCIIDs([]string{invalid}); err == nil {
+			t.Errorf("invalid PCI ID '%s' accepted", invalid)
+		}
+	}
+}
+
 func TestLldpResults(t *testing.T) {
 	nwconfigs := getFakeNetworkDataConfigs()
 	foundpeers := lldpResults(nwconfigs)
@@ -434,7 +631,7 @@ func TestAddRouteErrors(t *testing.T) {
 
 	fnd.nwconfig.localAddr = &ip
 
-	err := addRoute(&fnd.nwconfig, RouteMaskPointToPoint)
+	err := addRoute(&fnd.nwconfig, routePointToPoint)
 
 	if err == nil {
 		t.Error("add route succeeded while it shouldn't have")
@@ -444,7 +641,7 @@ func TestAddRouteErrors(t *testing.T) {
 		return os.ErrExist
 	}
 
-	err = addRoute(&fnd.nwconfig, RouteMaskPointToPoint)
+	err = addRoute(&fnd.nwconfig, routePointToPoint)
 
 	if err != nil {
 		t.Error("add route failed while it shouldn't have")
@@ -452,7 +649,7 @@ func TestAddRouteErrors(t *testing.T) {
 
 	fnd.nwconfig.localAddr = nil
 
-	err = addRoute(&fnd.nwconfig, RouteMaskPointToPoint)
+	err = addRoute(&fnd.nwconfig, routePointToPoint)
 
 	if err == nil {
 		t.Error("add route succeeded while it shouldn't have")
@@ -464,7 +661,7 @@ func TestNoGaudiDevicesErrors(t *testing.T) {
 	os.Setenv("SYSFS_ROOT", "\\\\\\")
 	defer os.Unsetenv("SYSFS_ROOT")
 
-	devs := getNetworks()
+	devs := getNetworks(deviceSelector{driver: defaultDriver})
 	if len(devs) > 0 {
 		t.Errorf("no devices should have been found: %s", devs)
 	}
@@ -599,6 +796,20 @@ func TestSetLinkMTUWarning(_ *testing.T) {
 	interfacesSetMTU(netConfs, 8080)
 }
 
+func TestCheckPeerMTU(t *testing.T) {
+	nwconfigs := getFakeNetworkDataConfigs()
+
+	nwconfigs["eth_a"].peerMTU = 9000
+	nwconfigs["eth_b"].peerMTU = 1500
+
+	if mismatches := checkPeerMTU(nwconfigs, 8000); mismatches != 1 {
+		t.Errorf("expected 1 MTU mismatch, got %d", mismatches)
+	}
+	if mismatches := checkPeerMTU(nwconfigs, 1500); mismatches != 0 {
+		t.Errorf("expected no MTU mismatches, got %d", mismatches)
+	}
+}
+
 func TestRemoveExistingIPs(t *testing.T) {
 	netConfs := getFakeNetworkDataConfigs()
 
@@ -606,19 +817,93 @@ func TestRemoveExistingIPs(t *testing.T) {
 		return nil
 	}
 	networkLink.AddrList = fakeLinkAddrList
+	networkLink.RouteList = fakeRouteList
 
-	err := removeExistingIPs(netConfs)
+	err := removeExistingIPs(netConfs, true)
 	if err != nil {
 		t.Error("removeExistingIPs should have passed")
 	}
 }
 
+func TestRemoveOwnIPs(t *testing.T) {
+	var deletedAddrs, deletedRoutes []string
+
+	nwconfig := &networkConfiguration{
+		link: &fakeLink{fakeAttrs: netlink.LinkAttrs{Name: "eth_a"}},
+	}
+
+	ipnet := func(cidr string) *net.IPNet {
+		ip, network, _ := net.ParseCIDR(cidr)
+		network.IP = ip
+		return network
+	}
+
+	networkLink.AddrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return []netlink.Addr{
+			{IPNet: ipnet("10.210.8.121/30")},
+			{IPNet: ipnet("192.168.0.10/24"), Label: "eth_a"},
+			{IPNet: ipnet("fd00:10::1/127"), Flags: unix.IFA_F_NODAD},
+			{IPNet: ipnet("fd00:20::1/64"), Flags: unix.IFA_F_NODAD},
+			{IPNet: ipnet("fe80::1/64"), Flags: unix.IFA_F_NODAD},
+		}, nil
+	}
+	networkLink.AddrDel = func(link netlink.Link, addr *netlink.Addr) error {
+		deletedAddrs = append(deletedAddrs, addr.IPNet.String())
+		return nil
+	}
+	networkLink.RouteList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		return []netlink.Route{
+			{Dst: ipnet("10.210.0.0/16"), Protocol: routeProtocolDiscover},
+			{Dst: ipnet("10.0.0.0/8"), Protocol: unix.RTPROT_BOOT},
+		}, nil
+	}
+	networkLink.RouteDel = func(route *netlink.Route) error {
+		deletedRoutes = append(deletedRoutes, route.Dst.String())
+		return nil
+	}
+
+	// an address with the nodad flag added by the operator is not ours
+	defer func() { ownedAddresses = &addressRecord{Interfaces: map[string][]string{}} }()
+
+	ownedAddresses = &addressRecord{Interfaces: map[string][]string{
+		"eth_a": {"10.210.8.121/30", "fd00:10::1/127"},
+		"eth_b": {"10.210.8.125/30"},
+	}}
+
+	if err := removeExistingIPs(map[string]*networkConfiguration{"eth_a": nwconfig}, false); err != nil {
+		t.Fatalf("removeExistingIPs failed: %v", err)
+	}
+
+	if !slices.Equal(deletedAddrs, []string{"10.210.8.121/30", "fd00:10::1/127"}) {
+		t.Errorf("unexpected addresses removed: %v", deletedAddrs)
+	}
+
+	if !slices.Equal(deletedRoutes, []string{"10.210.0.0/16"}) {
+		t.Errorf("unexpected routes removed: %v", deletedRoutes)
+	}
+
+	if _, ok := ownedAddresses.Interfaces["eth_a"]; ok || len(ownedAddresses.Interfaces) != 1 {
+		t.Errorf("unexpected address record after the removal: %v", ownedAddresses.Interfaces)
+	}
+
+	deletedAddrs = nil
+
+	if err := removeExistingIPs(map[string]*networkConfiguration{"eth_a": nwconfig}, true); err != nil {
+		t.Fatalf("removeExistingIPs failed: %v", err)
+	}
+
+	if len(deletedAddrs) != 4 {
+		t.Errorf("expected all but the link-local address to be flushed: %v", deletedAddrs)
+	}
+}
+
 func TestRemoveExistingIPsErrors(t *testing.T) {
 	netConfs := getFakeNetworkDataConfigs()
 
 	networkLink.AddrList = fakeLinkAddrListErr
+	networkLink.RouteList = fakeRouteList
 
-	err := removeExistingIPs(netConfs)
+	err := removeExistingIPs(netConfs, true)
 	if err == nil {
 		t.Error("removeExistingIPs should have failed")
 	}
@@ -629,7 +914,7 @@ func TestRemoveExistingIPsErrors(t *testing.T) {
 		return fmt.Errorf("cant remove addr")
 	}
 
-	err = removeExistingIPs(netConfs)
+	err = removeExistingIPs(netConfs, true)
 	if err == nil {
 		t.Error("removeExistingIPs should have failed")
 	}
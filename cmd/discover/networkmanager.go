@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"k8s.io/klog/v2"
+
+	nm "github.com/intel/network-operator/internal/nm"
+)
+
+const (
+	backendNetlink        = "netlink"
+	backendNetworkManager = "networkmanager"
+)
+
+// profileBackend configures the interfaces with NetworkManager connection
+// profiles instead of netlink so that NetworkManager owns the addresses and
+// routes and keeps them across restarts.
+type profileBackend struct {
+	nm  nm.NetworkManagerIf
+	mtu int
+}
+
+// nmProfiles is set when the L3 configuration is applied with NetworkManager.
+var nmProfiles *profileBackend
+
+func setupProfileBackend(config *cmdConfig) error {
+	if config.backend != backendNetworkManager {
+		return nil
+	}
+
+	nmapi, err := nm.NewNetworkManager()
+	if err != nil {
+		return fmt.Errorf("Failed to create NetworkManager: %v", err)
+	}
+
+	nmProfiles = &profileBackend{nm: nmapi, mtu: config.mtu}
+
+	return nil
+}
+
+func (b *profileBackend) configure(nwconfig *networkConfiguration) error {
+	profile := nm.InterfaceProfile{
+		Interface: nwconfig.link.Attrs().Name,
+		Address: &net.IPNet{
+			IP:   *nwconfig.localAddr,
+			Mask: pointToPointMask(nwconfig),
+		},
+		MTU: b.mtu,
+	}
+
+	if nwconfig.lldpPeer != nil {
+		profile.Gateway = *nwconfig.lldpPeer
+		profile.Routes = routedNetworkDestinations(nwconfig)
+	}
+
+	return nm.ApplyProfile(b.nm, profile)
+}
+
+func (b *profileBackend) deconfigure(ifname string) {
+	if err := nm.DeleteProfile(b.nm, ifname); err != nil {
+		klog.Warningf("Could not remove connection profile of interface '%s': %v", ifname, err)
+	}
+}
+
+// writeNetworkManagerConfig keeps the interfaces unmanaged across
+// NetworkManager restarts and reboots with a configuration snippet.
+func writeNetworkManagerConfig(nmapi nm.NetworkManagerIf, config *cmdConfig, networkConfigs map[string]*networkConfiguration) error {
+	interfaces := make([]nm.UnmanagedInterface, 0, len(networkConfigs))
+
+	for name, nwconfig := range networkConfigs {
+		interfaces = append(interfaces, nm.UnmanagedInterface{
+			Name: name,
+			MAC:  nwconfig.link.Attrs().HardwareAddr.String(),
+		})
+	}
+
+	changed, err := nm.WriteUnmanagedConfig(config.nmConfDir, config.nmConfFile, interfaces, config.nmConfig)
+	if err != nil || !changed {
+		return err
+	}
+
+	return nm.ReloadConfiguration(nmapi)
+}
+
+// restoreNetworkManager hands the interfaces disabled in NetworkManager back
+// to it. The persistent configuration is removed and the interfaces are taken
+// from the record kept on the host so that only the interfaces NetworkManager
+// managed originally are restored.
+func restoreNetworkManager(config *cmdConfig, networkConfigs map[string]*networkConfiguration) {
+	hasRecord := false
+	if config.nmRecord != "" {
+		_, err := os.Stat(config.nmRecord)
+		hasRecord = err == nil
+	}
+
+	hasConfig := config.nmConfDir != "" && nm.HasUnmanagedConfig(config.nmConfDir, config.nmConfFile)
+
+	if !hasRecord && !hasConfig {
+		return
+	}
+
+	nmapi, err := nm.NewNetworkManager()
+	if err != nil {
+		klog.Warningf("Failed to create NetworkManager: %v", err)
+
+		return
+	}
+
+	if hasConfig {
+		if _, err := nm.RemoveUnmanagedConfig(config.nmConfDir, config.nmConfFile); err != nil {
+			klog.Warningf("Failed to remove NetworkManager configuration: %v", err)
+		} else if err := nm.ReloadConfiguration(nmapi); err != nil {
+			klog.Warningf("Failed to reload NetworkManager configuration: %v", err)
+		}
+	}
+
+	names := make([]string, 0, len(networkConfigs))
+	for name := range networkConfigs {
+		names = append(names, name)
+	}
+
+	// only the interfaces of this daemon, other policies keep theirs
+	if err := nm.RestoreNetworkManager(nmapi, config.nmRecord, names); err != nil {
+		nodeEvents.warningf(reasonNetworkManagerRestoreFailed, "Failed to restore interfaces in NetworkManager: %v", err)
+		klog.Warningf("Failed to restore interfaces in NetworkManager: %v", err)
+
+		return
+	}
+
+	for _, nwconfig := range networkConfigs {
+		nwconfig.nmUnmanaged = false
+	}
+}
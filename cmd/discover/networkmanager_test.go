@@ -0,0 +1,275 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink"
+
+	nm "github.com/intel/network-operator/internal/nm"
+)
+
+type fakeNetworkManager struct {
+	reloads     int
+	devices     []string
+	connections []*fakeConnection
+}
+
+type fakeDevice struct {
+	name string
+}
+
+type fakeConnection struct {
+	settings nm.ConnectionSettings
+	deleted  bool
+}
+
+func (f *fakeNetworkManager) GetPropertyVersion() (string, error) {
+	return "1.46.0", nil
+}
+
+func (f *fakeNetworkManager) GetAllDevices() ([]nm.DeviceWrapperIf, error) {
+	devices := []nm.DeviceWrapperIf{}
+	for _, name := range f.devices {
+		devices = append(devices, &fakeDevice{name: name})
+	}
+	return devices, nil
+}
+
+func (f *fakeNetworkManager) Reload(flags uint32) error {
+	f.reloads++
+	return nil
+}
+
+func (f *fakeNetworkManager) ListConnections() ([]nm.ConnectionWrapperIf, error) {
+	connections := []nm.ConnectionWrapperIf{}
+	for _, c := range f.connections {
+		if !c.deleted {
+			connections = append(connections, c)
+		}
+	}
+	return connections, nil
+}
+
+func (f *fakeNetworkManager) AddConnection(settings nm.ConnectionSettings) (nm.ConnectionWrapperIf, error) {
+	c := &fakeConnection{settings: settings}
+	f.connections = append(f.connections, c)
+	return c, nil
+}
+
+func (f *fakeNetworkManager) ActivateConnection(connection nm.ConnectionWrapperIf, device nm.DeviceWrapperIf) error {
+	return nil
+}
+
+func (d *fakeDevice) GetPropertyInterface() (string, error) {
+	return d.name, nil
+}
+
+func (d *fakeDevice) GetPropertyManaged() (bool, error) {
+	return true, nil
+}
+
+func (d *fakeDevice) SetPropertyManaged(managed bool) error {
+	return nil
+}
+
+func (c *fakeConnection) GetSettings() (nm.ConnectionSettings, error) {
+	return c.settings, nil
+}
+
+func (c *fakeConnection) Update(settings nm.ConnectionSettings) error {
+	c.settings = settings
+	return nil
+}
+
+func (c *fakeConnection) Delete() error {
+	c.deleted = true
+	return nil
+}
+
+func TestWriteNetworkManagerConfig(t *testing.T) {
+	config := &cmdConfig{
+		nmConfig:   nm.MatchMAC,
+		nmConfDir:  path.Join(t.TempDir(), "conf.d"),
+		nmConfFile: "99-intel-network-operator-policy-a.conf",
+	}
+
+	nmapi := &fakeNetworkManager{}
+	nwconfigs := getFakeNetworkDataConfigs()
+
+	if err := writeNetworkManagerConfig(nmapi, config, nwconfigs); err != nil {
+		t.Fatalf("cannot write NetworkManager configuration: %v", err)
+	}
+
+	if err := writeNetworkManagerConfig(nmapi, config, nwconfigs); err != nil {
+		t.Fatalf("cannot write NetworkManager configuration: %v", err)
+	}
+
+	if nmapi.reloads != 1 {
+		t.Errorf("expected one reload for an unchanged configuration, got %d", nmapi.reloads)
+	}
+
+	if !nm.HasUnmanagedConfig(config.nmConfDir, config.nmConfFile) {
+		t.Fatal("NetworkManager configuration was not written")
+	}
+
+	content, _ := os.ReadFile(path.Join(config.nmConfDir, config.nmConfFile))
+
+	for _, nwconfig := range nwconfigs {
+		if !strings.Contains(string(content), "mac:"+nwconfig.link.Attrs().HardwareAddr.String()) {
+			t.Errorf("MAC of '%s' not in the configuration:\n%s", nwconfig.link.Attrs().Name, content)
+		}
+	}
+
+	// a second policy on the node writes its own configuration
+	other := *config
+	other.nmConfig = nm.MatchInterfaceName
+	other.nmConfFile = "99-intel-network-operator-policy-b.conf"
+
+	if err := writeNetworkManagerConfig(nmapi, &other, map[string]*networkConfiguration{"eth_d": nwconfigs["eth_a"]}); err != nil {
+		t.Fatalf("cannot write NetworkManager configuration: %v", err)
+	}
+
+	if entries, _ := os.ReadDir(config.nmConfDir); len(entries) != 2 {
+		t.Errorf("expected a configuration for each policy, got %d", len(entries))
+	}
+
+	if updated, _ := os.ReadFile(path.Join(config.nmConfDir, config.nmConfFile)); !bytes.Equal(updated, content) {
+		t.Errorf("configuration of the first policy changed:\n%s", updated)
+	}
+}
+
+func TestSanitizeNetworkManagerConfig(t *testing.T) {
+	config := &cmdConfig{
+		mode:           L3,
+		mtu:            1500,
+		addressParser:  addressParserPortDescription,
+		driver:         defaultDriver,
+		lldpTxInterval: 10 * time.Second,
+		nmConfig:       nm.MatchInterfaceName,
+	}
+
+	if err := sanitizeInput(config); err == nil {
+		t.Error("NetworkManager configuration accepted without disabling NetworkManager")
+	}
+
+	config.disableNM = true
+	if err := sanitizeInput(config); err != nil {
+		t.Errorf("sanitizing input failed: %v", err)
+	}
+
+	config.nmConfig = "pci"
+	if err := sanitizeInput(config); err == nil {
+		t.Error("unknown NetworkManager configuration match accepted")
+	}
+}
+
+func TestProfileBackend(t *testing.T) {
+	nmapi := &fakeNetworkManager{devices: []string{"eth_a", "eth_b", "eth_c"}}
+	nmProfiles = &profileBackend{nm: nmapi, mtu: 8000}
+	defer func() { nmProfiles = nil }()
+
+	// netlink must not be used for the addresses and routes
+	networkLink.AddrAdd = func(link netlink.Link, addr *netlink.Addr) error {
+		t.Errorf("address %s added with netlink", addr)
+		return nil
+	}
+	networkLink.RouteAppend = func(route *netlink.Route) error {
+		t.Errorf("route %s added with netlink", route)
+		return nil
+	}
+	defer func() {
+		networkLink.AddrAdd = netlink.AddrAdd
+		networkLink.RouteAppend = netlink.RouteAppend
+	}()
+
+	nwconfigs := getFakeNetworkDataConfigs()
+
+	lldpResults(nwconfigs)
+
+	configured, total := configureInterfaces(nwconfigs)
+	if configured != 2 || total != 3 {
+		t.Fatalf("expected 2/3 interfaces to be configured, got %d/%d", configured, total)
+	}
+
+	if len(nmapi.connections) != 2 {
+		t.Fatalf("expected two connection profiles, got %d", len(nmapi.connections))
+	}
+
+	connection := nmapi.connections[0]
+	if connection.settings["connection"]["interface-name"] != "eth_a" {
+		connection = nmapi.connections[1]
+	}
+
+	settings := connection.settings
+	if settings["connection"]["interface-name"] != "eth_a" || settings["802-3-ethernet"]["mtu"] != uint32(8000) {
+		t.Errorf("unexpected profile settings %v", settings)
+	}
+
+	ipv4 := settings["ipv4"]
+	addrs := ipv4["address-data"].([]map[string]interface{})
+	if addrs[0]["address"] != nwconfigs["eth_a"].localAddr.String() || addrs[0]["prefix"] != uint32(30) {
+		t.Errorf("unexpected profile addresses %v", addrs)
+	}
+
+	routes := ipv4["route-data"].([]map[string]interface{})
+	if len(routes) != 1 || routes[0]["next-hop"] != nwconfigs["eth_a"].lldpPeer.String() || routes[0]["prefix"] != uint32(16) {
+		t.Errorf("unexpected profile routes %v", routes)
+	}
+
+	deconfigureInterface(nwconfigs["eth_a"])
+
+	if !connection.deleted || nwconfigs["eth_a"].localAddr != nil {
+		t.Error("connection profile was not removed")
+	}
+}
+
+func TestSanitizeBackend(t *testing.T) {
+	config := &cmdConfig{
+		mode:           L3,
+		mtu:            1500,
+		addressParser:  addressParserPortDescription,
+		driver:         defaultDriver,
+		lldpTxInterval: 10 * time.Second,
+	}
+
+	if err := sanitizeInput(config); err != nil || config.backend != backendNetlink {
+		t.Errorf("expected the netlink backend by default: %v", err)
+	}
+
+	config.backend = backendNetworkManager
+	if err := sanitizeInput(config); err != nil {
+		t.Errorf("sanitizing input failed: %v", err)
+	}
+
+	config.disableNM = true
+	if err := sanitizeInput(config); err == nil {
+		t.Error("NetworkManager backend accepted with NetworkManager disabled")
+	}
+
+	config.backend = "nmstate"
+	config.disableNM = false
+	if err := sanitizeInput(config); err == nil {
+		t.Error("unknown backend accepted")
+	}
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+const (
+	nmStateUnmanaged = "unmanaged"
+)
+
+type nodeStateReporter struct {
+	client    client.Client
+	policy    string
+	node      string
+	namespace string
+	// set while waiting for the operator to allocate the addresses
+	pending bool
+	// set once the configuration has been removed from the node
+	cleanedUp bool
+}
+
+func newNodeStateReporter(policy string) (*nodeStateReporter, error) {
+	nodeName := os.Getenv("NODE_NAME")
+	namespace := os.Getenv("POD_NAMESPACE")
+
+	if nodeName == "" || namespace == "" {
+		return nil, fmt.Errorf("NODE_NAME and POD_NAMESPACE need to be set for node state reporting")
+	}
+
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get in-cluster config: %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := networkv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create client: %v", err)
+	}
+
+	return &nodeStateReporter{
+		client:    c,
+		policy:    policy,
+		node:      nodeName,
+		namespace: namespace,
+	}, nil
+}
+
+func interfaceAddress(nwconfig *networkConfiguration) string {
+	if nwconfig.localAddr == nil {
+		return ""
+	}
+
+	addrs, err := networkLink.AddrList(nwconfig.link, addrFamily(nwconfig))
+	if err != nil {
+		return ""
+	}
+
+	for _, addr := range addrs {
+		if addr.IPNet != nil && addr.IPNet.IP.Equal(*nwconfig.localAddr) {
+			return addr.IPNet.String()
+		}
+	}
+
+	return ""
+}
+
+func interfaceRoutes(nwconfig *networkConfiguration) []string {
+	routes, err := networkLink.RouteList(nwconfig.link, addrFamily(nwconfig))
+	if err != nil {
+		return nil
+	}
+
+	routeStrs := []string{}
+	for _, route := range routes {
+		routeStrs = append(routeStrs, routeString(route))
+	}
+
+	return routeStrs
+}
+
+func interfaceStates(networkConfigs map[string]*networkConfiguration) []networkv1alpha1.InterfaceState {
+	names := make([]string, 0, len(networkConfigs))
+	for name := range networkConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	states := make([]networkv1alpha1.InterfaceState, 0, len(names))
+
+	for _, name := range names {
+		nwconfig := networkConfigs[name]
+
+		// refresh link attributes as MTU and flags may have changed
+		attrs := nwconfig.link.Attrs()
+		if link, err := networkLink.LinkByName(name); err == nil {
+			attrs = link.Attrs()
+		}
+
+		state := networkv1alpha1.InterfaceState{
+			Name:    name,
+			MAC:     attrs.HardwareAddr.String(),
+			Flags:   attrs.Flags.String(),
+			MTU:     attrs.MTU,
+			Address: interfaceAddress(nwconfig),
+			Routes:  interfaceRoutes(nwconfig),
+		}
+
+		if nwconfig.peerHWAddr != nil || nwconfig.portDescription != "" {
+			state.LLDPPeer = &networkv1alpha1.LLDPPeerState{
+				SysName:         nwconfig.sysName,
+				PortDescription: nwconfig.portDescription,
+			}
+			if nwconfig.peerHWAddr != nil {
+				state.LLDPPeer.MAC = nwconfig.peerHWAddr.String()
+			}
+			if nwconfig.lldpPeer != nil {
+				state.LLDPPeer.Address = nwconfig.lldpPeer.String()
+			}
+		}
+
+		if probe := nwconfig.peerProbe; probe != nil {
+			state.Reachability = &networkv1alpha1.PeerReachability{
+				Reachable:        probe.reachable(),
+				NeighborResolved: probe.neighborResolved,
+				EchoReply:        probe.echoReply,
+				LastProbeTime:    metav1.NewTime(probe.time),
+			}
+			if probe.err != nil {
+				state.Reachability.Message = probe.err.Error()
+			}
+		}
+
+		if nwconfig.nmUnmanaged {
+			state.NetworkManager = nmStateUnmanaged
+		}
+
+		if nwconfig.configErr != nil {
+			state.Error = nwconfig.configErr.Error()
+		}
+
+		states = append(states, state)
+	}
+
+	return states
+}
+
+// get returns the state of the node for the policy. The state is looked up
+// by its labels, the spec tells apart the states of policy and node names
+// that were shortened in the labels.
+func (r *nodeStateReporter) get(ctx context.Context) (*networkv1alpha1.NetworkNodeState, error) {
+	var states networkv1alpha1.NetworkNodeStateList
+
+	if err := r.client.List(ctx, &states, client.InNamespace(r.namespace),
+		client.MatchingLabels(networkv1alpha1.NetworkNodeStateLabels(r.policy, r.node))); err != nil {
+		return nil, fmt.Errorf("cannot list the node states of policy '%s': %v", r.policy, err)
+	}
+
+	for i := range states.Items {
+		if states.Items[i].IsFor(r.policy, r.node) {
+			return &states.Items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no node state for policy '%s' on node '%s'", r.policy, r.node)
+}
+
+func (r *nodeStateReporter) report(ctx context.Context, networkConfigs map[string]*networkConfiguration, runErr error) error {
+	state, err := r.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	state.Status.Interfaces = interfaceStates(networkConfigs)
+	state.Status.LastUpdate = metav1.Now()
+
+	switch {
+	case r.cleanedUp:
+		state.Status.State = networkv1alpha1.NodeStateCleanedUp
+		state.Status.LastError = ""
+	case r.pending:
+		state.Status.State = networkv1alpha1.NodeStatePending
+		state.Status.LastError = ""
+	case runErr != nil:
+		state.Status.State = networkv1alpha1.NodeStateFailed
+		state.Status.LastError = runErr.Error()
+	default:
+		state.Status.State = networkv1alpha1.NodeStateConfigured
+		state.Status.LastError = ""
+	}
+
+	if err := r.client.Status().Update(ctx, state); err != nil {
+		return fmt.Errorf("cannot update node state '%s': %v", state.Name, err)
+	}
+
+	klog.V(3).Infof("Reported node state '%s': %s", state.Name, state.Status.State)
+
+	return nil
+}
+
+// teardownRequested returns true when the operator has asked the node to
+// remove the configuration because the policy is being deleted.
+func (r *nodeStateReporter) teardownRequested(ctx context.Context) (bool, error) {
+	state, err := r.get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return state.Spec.Teardown, nil
+}
+
+// addressAllocations returns the addresses the operator has allocated for
+// the interfaces from the address pool of the policy.
+func (r *nodeStateReporter) addressAllocations(ctx context.Context) ([]networkv1alpha1.AddressAllocation, error) {
+	state, err := r.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Spec.Addresses, nil
+}
+
+// reportNodeState reports the node state if reporting is enabled and
+// returns true when there's nothing left to report.
+func reportNodeState(config *cmdConfig, reporter *nodeStateReporter, networkConfigs map[string]*networkConfiguration, runErr error) bool {
+	if reporter == nil {
+		return true
+	}
+
+	if err := reporter.report(config.ctx, networkConfigs, runErr); err != nil {
+		klog.Warningf("Could not report node state: %v", err)
+
+		return false
+	}
+
+	return true
+}
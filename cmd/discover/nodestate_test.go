@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+func fakeRouteList(link netlink.Link, family int) ([]netlink.Route, error) {
+	_, dst, _ := net.ParseCIDR("10.210.0.0/16")
+	gw := net.IPv4(10, 210, 8, 122)
+
+	return []netlink.Route{{Dst: dst, Gw: gw}}, nil
+}
+
+func TestInterfaceStates(t *testing.T) {
+	networkLink.LinkByName = fakeLinkByName
+	networkLink.AddrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return []netlink.Addr{
+			{IPNet: &net.IPNet{IP: net.IPv4(10, 210, 8, 121), Mask: net.CIDRMask(30, 32)}},
+		}, nil
+	}
+	networkLink.RouteList = fakeRouteList
+
+	nwconfigs := getFakeNetworkDataConfigs()
+	_ = lldpResults(nwconfigs)
+
+	nwconfigs["eth_a"].nmUnmanaged = true
+
+	states := interfaceStates(nwconfigs)
+	if len(states) != len(nwconfigs) {
+		t.Fatalf("expected %d interface states, got %d", len(nwconfigs), len(states))
+	}
+
+	// states are sorted by interface name
+	if states[0].Name != "eth_a" || states[1].Name != "eth_b" || states[2].Name != "eth_c" {
+		t.Errorf("interface states are not sorted: %v", states)
+	}
+
+	if states[0].Address != "10.210.8.121/30" {
+		t.Errorf("expected address 10.210.8.121/30, got '%s'", states[0].Address)
+	}
+	if states[0].NetworkManager != nmStateUnmanaged {
+		t.Errorf("expected NetworkManager state '%s', got '%s'", nmStateUnmanaged, states[0].NetworkManager)
+	}
+	if states[0].LLDPPeer == nil || states[0].LLDPPeer.Address != "10.210.8.122" {
+		t.Errorf("expected LLDP peer 10.210.8.122, got %+v", states[0].LLDPPeer)
+	}
+	if len(states[0].Routes) != 1 || states[0].Routes[0] != "10.210.0.0/16 via 10.210.8.122" {
+		t.Errorf("unexpected routes %v", states[0].Routes)
+	}
+	if states[0].Error != "" {
+		t.Errorf("expected no error for eth_a, got '%s'", states[0].Error)
+	}
+
+	if states[1].Address != "" {
+		t.Errorf("expected no address for eth_b, got '%s'", states[1].Address)
+	}
+	if states[1].Error == "" {
+		t.Error("expected an LLDP error for eth_b")
+	}
+}
+
+func TestNodeStateReport(t *testing.T) {
+	networkLink.LinkByName = fakeLinkByName
+	networkLink.AddrList = fakeLinkAddrList
+	networkLink.RouteList = fakeRouteList
+
+	scheme := runtime.NewScheme()
+	if err := networkv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot add scheme: %v", err)
+	}
+
+	state := &networkv1alpha1.NetworkNodeState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      networkv1alpha1.NetworkNodeStateName("policy", "node"),
+			Namespace: "ns",
+			Labels:    networkv1alpha1.NetworkNodeStateLabels("policy", "node"),
+		},
+		Spec: networkv1alpha1.NetworkNodeStateSpec{
+			NodeName: "node",
+			Policy:   "policy",
+		},
+	}
+
+	reporter := &nodeStateReporter{
+		client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(state).
+			WithStatusSubresource(state).
+			Build(),
+		policy:    "policy",
+		node:      "node",
+		namespace: state.Namespace,
+	}
+
+	ctx := context.Background()
+	nwconfigs := getFakeNetworkDataConfigs()
+
+	if err := reporter.report(ctx, nwconfigs, fmt.Errorf("no peers")); err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+
+	reported := &networkv1alpha1.NetworkNodeState{}
+	if err := reporter.client.Get(ctx, types.NamespacedName{Name: state.Name, Namespace: state.Namespace}, reported); err != nil {
+		t.Fatalf("cannot get node state: %v", err)
+	}
+
+	if reported.Status.State != networkv1alpha1.NodeStateFailed || reported.Status.LastError != "no peers" {
+		t.Errorf("unexpected node state '%s' with error '%s'", reported.Status.State, reported.Status.LastError)
+	}
+	if len(reported.Status.Interfaces) != len(nwconfigs) {
+		t.Errorf("expected %d interfaces, got %d", len(nwconfigs), len(reported.Status.Interfaces))
+	}
+
+	if err := reporter.report(ctx, nwconfigs, nil); err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+
+	if err := reporter.client.Get(ctx, types.NamespacedName{Name: state.Name, Namespace: state.Namespace}, reported); err != nil {
+		t.Fatalf("cannot get node state: %v", err)
+	}
+
+	if reported.Status.State != networkv1alpha1.NodeStateConfigured || reported.Status.LastError != "" {
+		t.Errorf("unexpected node state '%s' with error '%s'", reported.Status.State, reported.Status.LastError)
+	}
+
+	reporter.node = "missing"
+	if err := reporter.report(ctx, nwconfigs, nil); err == nil {
+		t.Error("report succeeded for a missing node state")
+	}
+
+	if !reportNodeState(&cmdConfig{ctx: ctx}, nil, nwconfigs, nil) {
+		t.Error("reporting without a reporter should succeed")
+	}
+}
+
+func TestReportNodeStateCollision(t *testing.T) {
+	networkLink.LinkByName = fakeLinkByName
+	networkLink.AddrList = fakeLinkAddrList
+	networkLink.RouteList = fakeRouteList
+
+	scheme := runtime.NewScheme()
+	if err := networkv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot add scheme: %v", err)
+	}
+
+	// the names used to be the same for both
+	nodeState := func(policy, node string) *networkv1alpha1.NetworkNodeState {
+		return &networkv1alpha1.NetworkNodeState{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      networkv1alpha1.NetworkNodeStateName(policy, node),
+				Namespace: "ns",
+				Labels:    networkv1alpha1.NetworkNodeStateLabels(policy, node),
+			},
+			Spec: networkv1alpha1.NetworkNodeStateSpec{
+				NodeName: node,
+				Policy:   policy,
+			},
+		}
+	}
+
+	states := []*networkv1alpha1.NetworkNodeState{nodeState("a-b", "c"), nodeState("a", "b-c")}
+
+	reporter := &nodeStateReporter{
+		client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(states[0], states[1]).
+			WithStatusSubresource(states[0], states[1]).
+			Build(),
+		policy:    "a",
+		node:      "b-c",
+		namespace: "ns",
+	}
+
+	ctx := context.Background()
+
+	if err := reporter.report(ctx, getFakeNetworkDataConfigs(), nil); err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+
+	for i, expected := range []string{"", networkv1alpha1.NodeStateConfigured} {
+		reported := &networkv1alpha1.NetworkNodeState{}
+		if err := reporter.client.Get(ctx, types.NamespacedName{Name: states[i].Name, Namespace: "ns"}, reported); err != nil {
+			t.Fatalf("cannot get node state: %v", err)
+		}
+
+		if reported.Status.State != expected {
+			t.Errorf("node state '%s': expected state '%s', got '%s'", reported.Name, expected, reported.Status.State)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+const (
+	probeTimeout = time.Second
+
+	nfdReachabilityFile       = nfdFeatureDir + "scale-out-reachability.txt"
+	nfdScaleOutReachableLabel = "intel.feature.node.kubernetes.io/gaudi-scale-out-reachable"
+	nfdReachablePortsLabel    = "intel.feature.node.kubernetes.io/gaudi-scale-out-reachable-ports"
+
+	icmpv4EchoRequest = 8
+	icmpv4EchoReply   = 0
+	icmpv6EchoRequest = 128
+	icmpv6EchoReply   = 129
+)
+
+// peerProbe holds the result of the last reachability probe of the LLDP peer.
+type peerProbe struct {
+	peer net.IP
+	// the peer address resolved to the MAC address received via LLDP
+	neighborResolved bool
+	// the peer answered to an ICMP echo request
+	echoReply bool
+	err       error
+	time      time.Time
+}
+
+func (p *peerProbe) reachable() bool {
+	return p.neighborResolved && p.echoReply
+}
+
+// icmpEcho sends an ICMP echo request to the peer from the given interface
+// and waits for the reply until the context is done. Replaced in tests.
+var icmpEcho = sendICMPEcho
+
+// icmpChecksum calculates the internet checksum (RFC 1071) of the message.
+func icmpChecksum(msg []byte) uint16 {
+	var sum uint32
+
+	for i := 0; i+1 < len(msg); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(msg[i:]))
+	}
+	if len(msg)%2 == 1 {
+		sum += uint32(msg[len(msg)-1]) << 8
+	}
+
+	for sum>>16 != 0 {
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+
+	return ^uint16(sum)
+}
+
+func icmpEchoMessage(msgType uint8, id, seq uint16, payload []byte) []byte {
+	msg := make([]byte, 8, 8+len(payload))
+	msg[0] = msgType
+	binary.BigEndian.PutUint16(msg[4:], id)
+	binary.BigEndian.PutUint16(msg[6:], seq)
+	msg = append(msg, payload...)
+
+	// the kernel calculates the checksum for ICMPv6 raw sockets
+	if msgType == icmpv4EchoRequest {
+		binary.BigEndian.PutUint16(msg[2:], icmpChecksum(msg))
+	}
+
+	return msg
+}
+
+// isEchoReply checks whether the received ICMP message is the reply for
+// the request with the given id and sequence number.
+func isEchoReply(msg []byte, replyType uint8, id, seq uint16) bool {
+	return len(msg) >= 8 && msg[0] == replyType &&
+		binary.BigEndian.Uint16(msg[4:]) == id &&
+		binary.BigEndian.Uint16(msg[6:]) == seq
+}
+
+func sendICMPEcho(ctx context.Context, ifname string, peer net.IP) error {
+	var (
+		fd        int
+		err       error
+		sa        unix.Sockaddr
+		reqType   uint8 = icmpv6EchoRequest
+		replyType uint8 = icmpv6EchoReply
+	)
+
+	if peer4 := peer.To4(); peer4 != nil {
+		fd, err = unix.Socket(unix.AF_INET, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.IPPROTO_ICMP)
+		sa4 := &unix.SockaddrInet4{}
+		copy(sa4.Addr[:], peer4)
+		sa = sa4
+		reqType, replyType = icmpv4EchoRequest, icmpv4EchoReply
+	} else {
+		fd, err = unix.Socket(unix.AF_INET6, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.IPPROTO_ICMPV6)
+		sa6 := &unix.SockaddrInet6{}
+		copy(sa6.Addr[:], peer.To16())
+		sa = sa6
+	}
+	if err != nil {
+		return fmt.Errorf("cannot open ICMP socket: %v", err)
+	}
+	defer unix.Close(fd)
+
+	if err := unix.SetsockoptString(fd, unix.SOL_SOCKET, unix.SO_BINDTODEVICE, ifname); err != nil {
+		return fmt.Errorf("cannot bind ICMP socket to '%s': %v", ifname, err)
+	}
+
+	id := uint16(os.Getpid())
+	seq := uint16(time.Now().UnixNano())
+
+	if err := unix.Sendto(fd, icmpEchoMessage(reqType, id, seq, []byte(ifname)), 0, sa); err != nil {
+		return fmt.Errorf("cannot send ICMP echo request to %s: %v", peer, err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(probeTimeout)
+	}
+
+	buf := make([]byte, 1500)
+
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 || ctx.Err() != nil {
+			return fmt.Errorf("no ICMP echo reply from %s", peer)
+		}
+
+		tv := unix.NsecToTimeval(remaining.Nanoseconds())
+		if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+			return fmt.Errorf("cannot set ICMP socket timeout: %v", err)
+		}
+
+		n, from, err := unix.Recvfrom(fd, buf, 0)
+		if err == unix.EAGAIN || err == unix.EINTR {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("cannot receive ICMP echo reply from %s: %v", peer, err)
+		}
+
+		msg := buf[:n]
+
+		switch from := from.(type) {
+		case *unix.SockaddrInet4:
+			if !net.IP(from.Addr[:]).Equal(peer) || n < 20 {
+				continue
+			}
+			// IPv4 raw sockets receive the IP header too
+			msg = msg[int(msg[0]&0x0f)*4:]
+		case *unix.SockaddrInet6:
+			if !net.IP(from.Addr[:]).Equal(peer) {
+				continue
+			}
+		}
+
+		if isEchoReply(msg, replyType, id, seq) {
+			return nil
+		}
+	}
+}
+
+// resolveNeighbor checks that the peer address has a valid neighbor entry
+// on the interface, and that it matches the MAC address received via LLDP.
+func resolveNeighbor(nwconfig *networkConfiguration) error {
+	neighs, err := networkLink.NeighList(nwconfig.link.Attrs().Index, addrFamily(nwconfig))
+	if err != nil {
+		return fmt.Errorf("cannot list neighbors: %v", err)
+	}
+
+	for _, neigh := range neighs {
+		if !neigh.IP.Equal(*nwconfig.lldpPeer) {
+			continue
+		}
+
+		if neigh.State&(netlink.NUD_INCOMPLETE|netlink.NUD_FAILED) != 0 || len(neigh.HardwareAddr) == 0 {
+			return fmt.Errorf("peer %s is not resolved", nwconfig.lldpPeer)
+		}
+
+		if nwconfig.peerHWAddr != nil && !bytes.Equal(neigh.HardwareAddr, *nwconfig.peerHWAddr) {
+			return fmt.Errorf("peer %s resolved to %s, LLDP peer is %s",
+				nwconfig.lldpPeer, neigh.HardwareAddr, nwconfig.peerHWAddr)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("no neighbor entry for peer %s", nwconfig.lldpPeer)
+}
+
+// probePeer checks that the LLDP peer of the interface is reachable. The
+// echo request also triggers the address resolution of the peer.
+func probePeer(ctx context.Context, nwconfig *networkConfiguration) *peerProbe {
+	probe := &peerProbe{peer: *nwconfig.lldpPeer, time: time.Now()}
+	ifname := nwconfig.link.Attrs().Name
+
+	echoErr := icmpEcho(ctx, ifname, *nwconfig.lldpPeer)
+	probe.echoReply = echoErr == nil
+
+	neighErr := resolveNeighbor(nwconfig)
+	probe.neighborResolved = neighErr == nil
+
+	if neighErr != nil {
+		probe.err = neighErr
+	} else if echoErr != nil {
+		probe.err = echoErr
+	}
+
+	return probe
+}
+
+// probeConfig returns a copy of the parts of the interface configuration
+// the probe needs, so that the probe doesn't race with the main loop.
+func probeConfig(nwconfig *networkConfiguration) *networkConfiguration {
+	lldpPeer := slices.Clone(*nwconfig.lldpPeer)
+	localAddr := slices.Clone(*nwconfig.localAddr)
+
+	target := &networkConfiguration{
+		link:      nwconfig.link,
+		lldpPeer:  &lldpPeer,
+		localAddr: &localAddr,
+	}
+
+	if nwconfig.peerHWAddr != nil {
+		peerHWAddr := slices.Clone(*nwconfig.peerHWAddr)
+		target.peerHWAddr = &peerHWAddr
+	}
+
+	return target
+}
+
+// startProbes probes the LLDP peers of all configured interfaces in
+// parallel in the background, within probeTimeout. The results are sent to
+// the returned channel when all probes are done.
+func startProbes(ctx context.Context, networkConfigs map[string]*networkConfiguration) <-chan map[string]*peerProbe {
+	targets := map[string]*networkConfiguration{}
+
+	for ifname, nwconfig := range networkConfigs {
+		if nwconfig.lldpPeer != nil && nwconfig.localAddr != nil {
+			targets[ifname] = probeConfig(nwconfig)
+		}
+	}
+
+	results := make(chan map[string]*peerProbe, 1)
+
+	go func() {
+		probectx, cancel := context.WithTimeout(ctx, probeTimeout)
+		defer cancel()
+
+		var (
+			wg     sync.WaitGroup
+			mutex  sync.Mutex
+			probes = map[string]*peerProbe{}
+		)
+
+		for ifname, target := range targets {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				probe := probePeer(probectx, target)
+
+				mutex.Lock()
+				probes[ifname] = probe
+				mutex.Unlock()
+			}()
+		}
+
+		wg.Wait()
+
+		results <- probes
+	}()
+
+	return results
+}
+
+// applyProbes stores the probe results in the interface configurations and
+// returns true when the reachability of any interface changed. Results for
+// a peer that has changed since the probe started are dropped.
+func applyProbes(networkConfigs map[string]*networkConfiguration, probes map[string]*peerProbe) bool {
+	changed := false
+
+	for ifname, nwconfig := range networkConfigs {
+		if nwconfig.lldpPeer == nil || nwconfig.localAddr == nil {
+			if nwconfig.peerProbe != nil {
+				nwconfig.peerProbe = nil
+				changed = true
+			}
+			continue
+		}
+
+		probe, ok := probes[ifname]
+		if !ok || !probe.peer.Equal(*nwconfig.lldpPeer) {
+			continue
+		}
+
+		if nwconfig.peerProbe == nil || nwconfig.peerProbe.reachable() != probe.reachable() {
+			if probe.reachable() {
+				klog.Infof("Peer %s of interface '%s' is reachable", nwconfig.lldpPeer, ifname)
+			} else {
+				klog.Warningf("Peer %s of interface '%s' is not reachable: %v", nwconfig.lldpPeer, ifname, probe.err)
+			}
+
+			changed = true
+		}
+
+		nwconfig.peerProbe = probe
+	}
+
+	return changed
+}
+
+// reachabilityLabels returns the NFD labels for the probed interfaces.
+func reachabilityLabels(networkConfigs map[string]*networkConfiguration) []string {
+	probed, reachable := 0, 0
+
+	for _, nwconfig := range networkConfigs {
+		if nwconfig.peerProbe == nil {
+			continue
+		}
+
+		probed++
+
+		if nwconfig.peerProbe.reachable() {
+			reachable++
+		}
+	}
+
+	if probed == 0 {
+		return nil
+	}
+
+	return []string{
+		fmt.Sprintf("%s=%t", nfdScaleOutReachableLabel, reachable == probed),
+		fmt.Sprintf("%s=%d", nfdReachablePortsLabel, reachable),
+	}
+}
+
+func writeReachabilityLabels(networkConfigs map[string]*networkConfiguration) error {
+	s, err := os.Stat(nfdFeatureDir)
+	if err != nil || !s.IsDir() {
+		return nil
+	}
+
+	labels := reachabilityLabels(networkConfigs)
+	if len(labels) == 0 {
+		if err := os.Remove(nfdReachabilityFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Failed to remove NFD reachability labels: %+v", err)
+		}
+
+		return nil
+	}
+
+	content := strings.Join(labels, "\n") + "\n"
+
+	if err := os.WriteFile(nfdReachabilityFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("Failed to write NFD reachability labels: %+v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+)
+
+func TestICMPEchoMessage(t *testing.T) {
+	msg := icmpEchoMessage(icmpv4EchoRequest, 0x1234, 1, []byte("eth_a"))
+
+	// a message with a valid checksum sums up to zero
+	if icmpChecksum(msg) != 0 {
+		t.Errorf("invalid checksum in %x", msg)
+	}
+
+	reply := append([]byte{}, msg...)
+	reply[0] = icmpv4EchoReply
+
+	if !isEchoReply(reply, icmpv4EchoReply, 0x1234, 1) {
+		t.Error("echo reply not matched")
+	}
+	if isEchoReply(reply, icmpv4EchoReply, 0x1234, 2) {
+		t.Error("echo reply with another sequence number matched")
+	}
+	if isEchoReply(msg, icmpv4EchoReply, 0x1234, 1) {
+		t.Error("echo request matched as a reply")
+	}
+	if isEchoReply(reply[:4], icmpv4EchoReply, 0x1234, 1) {
+		t.Error("truncated reply matched")
+	}
+
+	msg6 := icmpEchoMessage(icmpv6EchoRequest, 0x1234, 1, nil)
+	if len(msg6) != 8 || msg6[2] != 0 || msg6[3] != 0 {
+		t.Errorf("ICMPv6 checksum should be left to the kernel: %x", msg6)
+	}
+}
+
+func probedNetworkConfig(peerMAC net.HardwareAddr) *networkConfiguration {
+	localAddr := net.IPv4(10, 210, 8, 121)
+	peerAddr := net.IPv4(10, 210, 8, 122)
+
+	return &networkConfiguration{
+		link: &fakeLink{
+			fakeAttrs: netlink.LinkAttrs{Name: "eth_a", Index: 1},
+		},
+		localAddr:  &localAddr,
+		lldpPeer:   &peerAddr,
+		peerHWAddr: &peerMAC,
+	}
+}
+
+func TestResolveNeighbor(t *testing.T) {
+	peerMAC := net.HardwareAddr{0x01, 0x01, 0x02, 0x02, 0x03, 0x03}
+
+	tcases := []struct {
+		name      string
+		neighs    []netlink.Neigh
+		listErr   error
+		expectErr bool
+	}{
+		{
+			name: "resolved",
+			neighs: []netlink.Neigh{
+				{IP: net.IPv4(10, 210, 8, 1), HardwareAddr: net.HardwareAddr{0, 0, 0, 0, 0, 1}, State: netlink.NUD_REACHABLE},
+				{IP: net.IPv4(10, 210, 8, 122), HardwareAddr: peerMAC, State: netlink.NUD_STALE},
+			},
+		},
+		{
+			name: "other MAC",
+			neighs: []netlink.Neigh{
+				{IP: net.IPv4(10, 210, 8, 122), HardwareAddr: net.HardwareAddr{0, 0, 0, 0, 0, 1}, State: netlink.NUD_REACHABLE},
+			},
+			expectErr: true,
+		},
+		{
+			name: "failed",
+			neighs: []netlink.Neigh{
+				{IP: net.IPv4(10, 210, 8, 122), State: netlink.NUD_FAILED},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "missing",
+			expectErr: true,
+		},
+		{
+			name:      "list error",
+			listErr:   fmt.Errorf("no neighbors"),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcases {
+		networkLink.NeighList = func(linkIndex, family int) ([]netlink.Neigh, error) {
+			return tc.neighs, tc.listErr
+		}
+
+		err := resolveNeighbor(probedNetworkConfig(peerMAC))
+		if tc.expectErr != (err != nil) {
+			t.Errorf("%s: unexpected result: %v", tc.name, err)
+		}
+	}
+}
+
+// probePeers probes the LLDP peers and waits for the results.
+func probePeers(networkConfigs map[string]*networkConfiguration) bool {
+	return applyProbes(networkConfigs, <-startProbes(context.Background(), networkConfigs))
+}
+
+func TestProbePeers(t *testing.T) {
+	peerMAC := net.HardwareAddr{0x01, 0x01, 0x02, 0x02, 0x03, 0x03}
+
+	networkLink.NeighList = func(linkIndex, family int) ([]netlink.Neigh, error) {
+		return []netlink.Neigh{
+			{IP: net.IPv4(10, 210, 8, 122), HardwareAddr: peerMAC, State: netlink.NUD_REACHABLE},
+		}, nil
+	}
+
+	echoErr := error(nil)
+	icmpEcho = func(ctx context.Context, ifname string, peer net.IP) error {
+		return echoErr
+	}
+	defer func() { icmpEcho = sendICMPEcho }()
+
+	nwconfigs := map[string]*networkConfiguration{
+		"eth_a": probedNetworkConfig(peerMAC),
+		"eth_b": {
+			link: &fakeLink{fakeAttrs: netlink.LinkAttrs{Name: "eth_b", Index: 2}},
+		},
+	}
+
+	if labels := reachabilityLabels(nwconfigs); labels != nil {
+		t.Errorf("expected no labels before probing, got %v", labels)
+	}
+
+	if !probePeers(nwconfigs) {
+		t.Error("first probe should change the reachability")
+	}
+
+	if nwconfigs["eth_b"].peerProbe != nil {
+		t.Error("interface without a peer was probed")
+	}
+
+	probe := nwconfigs["eth_a"].peerProbe
+	if probe == nil || !probe.reachable() || probe.err != nil {
+		t.Fatalf("expected a reachable peer, got %+v", probe)
+	}
+
+	labels := reachabilityLabels(nwconfigs)
+	if len(labels) != 2 || labels[0] != nfdScaleOutReachableLabel+"=true" || labels[1] != nfdReachablePortsLabel+"=1" {
+		t.Errorf("unexpected labels %v", labels)
+	}
+
+	if probePeers(nwconfigs) {
+		t.Error("unchanged reachability reported as a change")
+	}
+
+	echoErr = fmt.Errorf("no reply")
+
+	if !probePeers(nwconfigs) {
+		t.Error("lost reachability not reported as a change")
+	}
+
+	probe = nwconfigs["eth_a"].peerProbe
+	if probe.reachable() || !probe.neighborResolved || probe.echoReply || probe.err == nil {
+		t.Errorf("expected an unreachable peer, got %+v", probe)
+	}
+
+	labels = reachabilityLabels(nwconfigs)
+	if len(labels) != 2 || labels[0] != nfdScaleOutReachableLabel+"=false" || labels[1] != nfdReachablePortsLabel+"=0" {
+		t.Errorf("unexpected labels %v", labels)
+	}
+
+	echoErr = nil
+	results := startProbes(context.Background(), nwconfigs)
+
+	otherPeer := net.IPv4(10, 210, 8, 126)
+	nwconfigs["eth_a"].lldpPeer = &otherPeer
+
+	if applyProbes(nwconfigs, <-results) || nwconfigs["eth_a"].peerProbe != probe {
+		t.Error("probe of the previous peer applied")
+	}
+
+	peerAddr := net.IPv4(10, 210, 8, 122)
+	nwconfigs["eth_a"].lldpPeer = &peerAddr
+
+	networkLink.LinkByName = fakeLinkByName
+	networkLink.AddrList = fakeLinkAddrList
+	networkLink.RouteList = fakeRouteList
+
+	states := interfaceStates(map[string]*networkConfiguration{"eth_a": nwconfigs["eth_a"]})
+	if states[0].Reachability == nil || states[0].Reachability.Reachable || states[0].Reachability.Message != "no reply" {
+		t.Errorf("unexpected reachability state %+v", states[0].Reachability)
+	}
+}
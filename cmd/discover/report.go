@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/vishvananda/netlink"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputJSON = "json"
+	outputYAML = "yaml"
+
+	// configuration steps reported with their errors
+	stepLinkUp    = "linkUp"
+	stepMTU       = "mtu"
+	stepLLDP      = "lldp"
+	stepAddress   = "address"
+	stepConfigure = "configure"
+)
+
+// lldpReport holds the LLDP information received on an interface.
+type lldpReport struct {
+	SysName         string `json:"sysName,omitempty"`
+	PortDescription string `json:"portDescription,omitempty"`
+	PeerMAC         string `json:"peerMAC,omitempty"`
+}
+
+// interfaceReport describes the discovery results of an interface.
+type interfaceReport struct {
+	Name         string            `json:"name"`
+	PCIAddress   string            `json:"pciAddress,omitempty"`
+	MAC          string            `json:"mac"`
+	Flags        string            `json:"flags"`
+	MTU          int               `json:"mtu"`
+	Addresses    []string          `json:"addresses"`
+	LLDP         *lldpReport       `json:"lldp,omitempty"`
+	LocalAddress string            `json:"localAddress,omitempty"`
+	PeerAddress  string            `json:"peerAddress,omitempty"`
+	Errors       map[string]string `json:"errors,omitempty"`
+}
+
+// discoveryReport is the machine-readable report of a discover run.
+type discoveryReport struct {
+	Mode       string            `json:"mode"`
+	Interfaces []interfaceReport `json:"interfaces"`
+}
+
+// setStepError records the result of a configuration step of the interface.
+func (nwconfig *networkConfiguration) setStepError(step string, err error) {
+	if err == nil {
+		delete(nwconfig.stepErrors, step)
+
+		return
+	}
+
+	if nwconfig.stepErrors == nil {
+		nwconfig.stepErrors = map[string]error{}
+	}
+
+	nwconfig.stepErrors[step] = err
+}
+
+// interfacePCIAddress returns the PCI address of the device the interface
+// belongs to.
+func interfacePCIAddress(ifname string) string {
+	matches, err := filepath.Glob(filepath.Join(getSysfsRoot(), pciDevicesPath, pciDevicePattern, "net", ifname))
+	if err != nil || len(matches) == 0 {
+		return ""
+	}
+
+	return filepath.Base(filepath.Dir(filepath.Dir(matches[0])))
+}
+
+func newDiscoveryReport(config *cmdConfig, networkConfigs map[string]*networkConfiguration) *discoveryReport {
+	names := make([]string, 0, len(networkConfigs))
+	for name := range networkConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report := &discoveryReport{
+		Mode:       config.mode,
+		Interfaces: make([]interfaceReport, 0, len(names)),
+	}
+
+	for _, name := range names {
+		nwconfig := networkConfigs[name]
+
+		// refresh link attributes as MTU and flags may have changed
+		attrs := nwconfig.link.Attrs()
+		if link, err := networkLink.LinkByName(name); err == nil {
+			attrs = link.Attrs()
+		}
+
+		iface := interfaceReport{
+			Name:       name,
+			PCIAddress: interfacePCIAddress(name),
+			MAC:        attrs.HardwareAddr.String(),
+			Flags:      attrs.Flags.String(),
+			MTU:        attrs.MTU,
+			Addresses:  []string{},
+		}
+
+		if addrs, err := networkLink.AddrList(nwconfig.link, netlink.FAMILY_ALL); err == nil {
+			for _, addr := range addrs {
+				if addr.IPNet != nil {
+					iface.Addresses = append(iface.Addresses, addr.IPNet.String())
+				}
+			}
+		}
+
+		if nwconfig.peerHWAddr != nil || nwconfig.sysName != "" || nwconfig.portDescription != "" {
+			iface.LLDP = &lldpReport{
+				SysName:         nwconfig.sysName,
+				PortDescription: nwconfig.portDescription,
+			}
+			if nwconfig.peerHWAddr != nil {
+				iface.LLDP.PeerMAC = nwconfig.peerHWAddr.String()
+			}
+		}
+
+		if nwconfig.localAddr != nil {
+			iface.LocalAddress = nwconfig.localAddr.String()
+		}
+
+		if nwconfig.lldpPeer != nil {
+			iface.PeerAddress = nwconfig.lldpPeer.String()
+		}
+
+		for step, err := range nwconfig.stepErrors {
+			if iface.Errors == nil {
+				iface.Errors = map[string]string{}
+			}
+
+			iface.Errors[step] = err.Error()
+		}
+
+		report.Interfaces = append(report.Interfaces, iface)
+	}
+
+	return report
+}
+
+func encodeReport(w io.Writer, report *discoveryReport, format string) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	if format == outputYAML {
+		data, err = yaml.Marshal(report)
+	} else {
+		data, err = json.MarshalIndent(report, "", "  ")
+		data = append(data, '\n')
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot encode discovery report: %v", err)
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
+
+// writeReport writes the discovery report to the report file or to stdout
+// if a report output format is set.
+func writeReport(config *cmdConfig, networkConfigs map[string]*networkConfiguration) {
+	if config.output == "" {
+		return
+	}
+
+	report := newDiscoveryReport(config, networkConfigs)
+
+	if config.reportFile == "" {
+		if err := encodeReport(os.Stdout, report, config.output); err != nil {
+			klog.Errorf("Could not write discovery report: %v", err)
+		}
+
+		return
+	}
+
+	f, err := os.Create(config.reportFile)
+	if err != nil {
+		klog.Errorf("Could not create discovery report file: %v", err)
+
+		return
+	}
+	defer f.Close()
+
+	if err := encodeReport(f, report, config.output); err != nil {
+		klog.Errorf("Could not write discovery report file '%s': %v", config.reportFile, err)
+
+		return
+	}
+
+	klog.Infof("Wrote discovery report to '%s'", config.reportFile)
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestDiscoveryReport(t *testing.T) {
+	testSysfsRoot := t.TempDir()
+	t.Setenv("SYSFS_ROOT", testSysfsRoot)
+
+	writeFakeSysfsEntries(testSysfsRoot, getFakeNetworkData(), t)
+
+	networkLink.LinkByName = fakeLinkByName
+	networkLink.AddrList = fakeLinkAddrList
+
+	networkConfigs := getFakeNetworkDataConfigs()
+	networkConfigs["eth_a"].sysName = "switch-1"
+	lldpResults(networkConfigs)
+	networkConfigs["eth_c"].setStepError(stepConfigure, errors.New("file exists"))
+
+	config := &cmdConfig{
+		mode:       L3,
+		output:     outputYAML,
+		reportFile: filepath.Join(t.TempDir(), "report.yaml"),
+	}
+
+	writeReport(config, networkConfigs)
+
+	data, err := os.ReadFile(config.reportFile)
+	if err != nil {
+		t.Fatalf("cannot read report: %v", err)
+	}
+
+	report := discoveryReport{}
+	if err := yaml.Unmarshal(data, &report); err != nil {
+		t.Fatalf("cannot parse YAML report: %v", err)
+	}
+
+	if report.Mode != L3 || len(report.Interfaces) != 3 {
+		t.Fatalf("unexpected report:\n%s", data)
+	}
+
+	ethA := report.Interfaces[0]
+	if ethA.Name != "eth_a" || ethA.PCIAddress != "0000:aa:00.0" || ethA.MAC != "0a:0b:0c:0d:0e:0f" ||
+		!slices.Equal(ethA.Addresses, []string{"192.192.192.1/24"}) ||
+		ethA.LocalAddress != "10.210.8.121" || ethA.PeerAddress != "10.210.8.122" || len(ethA.Errors) != 0 {
+		t.Errorf("unexpected report for eth_a: %+v", ethA)
+	}
+
+	if ethA.LLDP == nil || ethA.LLDP.SysName != "switch-1" ||
+		ethA.LLDP.PortDescription != "no-alert 10.210.8.122/30" || ethA.LLDP.PeerMAC != "01:01:02:02:03:03" {
+		t.Errorf("unexpected LLDP report for eth_a: %+v", ethA.LLDP)
+	}
+
+	if ethB := report.Interfaces[1]; ethB.LocalAddress != "" || ethB.Errors[stepAddress] == "" {
+		t.Errorf("unexpected report for eth_b: %+v", ethB)
+	}
+
+	if ethC := report.Interfaces[2]; ethC.Errors[stepConfigure] != "file exists" {
+		t.Errorf("unexpected report for eth_c: %+v", ethC)
+	}
+
+	config.output = outputJSON
+	writeReport(config, networkConfigs)
+
+	data, err = os.ReadFile(config.reportFile)
+	if err != nil {
+		t.Fatalf("cannot read report: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &report); err != nil || len(report.Interfaces) != 3 {
+		t.Errorf("cannot parse JSON report: %v\n%s", err, data)
+	}
+}
+
+func TestSanitizeOutput(t *testing.T) {
+	config := &cmdConfig{
+		mode:           L2,
+		mtu:            1500,
+		addressParser:  addressParserPortDescription,
+		driver:         defaultDriver,
+		lldpTxInterval: 10 * time.Second,
+		output:         "xml",
+	}
+
+	if err := sanitizeInput(config); err == nil {
+		t.Error("unknown output format accepted")
+	}
+
+	config.output = ""
+	config.reportFile = "/tmp/report.json"
+	if err := sanitizeInput(config); err != nil || config.output != outputJSON {
+		t.Errorf("unexpected output format '%s' for a report file: %v", config.output, err)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	devPortFile = "dev_port"
+	driverLink  = "driver"
+)
+
+// interfaceInfo describes a network interface of a PCI device for the
+// interface selection.
+type interfaceInfo struct {
+	name       string
+	pciAddress string
+	driver     string
+	// port index of the interface on its PCI device, -1 if unknown
+	port int
+}
+
+type portRange struct {
+	first int
+	last  int
+}
+
+// interfaceFilterFlags holds the command line values of an interface filter.
+type interfaceFilterFlags struct {
+	drivers      []string
+	pciAddresses []string
+	names        []string
+	ports        []string
+}
+
+// interfaceFilter matches interfaces by their driver, PCI address, name and
+// port index. An interface matches when it matches all the given criteria
+// and any of the values given for each criterion.
+type interfaceFilter struct {
+	drivers      []string
+	pciAddresses []string
+	names        []*regexp.Regexp
+	ports        []portRange
+}
+
+func parsePortRanges(ports []string) ([]portRange, error) {
+	ranges := make([]portRange, 0, len(ports))
+
+	for _, port := range ports {
+		firstStr, lastStr, isRange := strings.Cut(strings.TrimSpace(port), "-")
+		if !isRange {
+			lastStr = firstStr
+		}
+
+		first, err := strconv.Atoi(firstStr)
+		if err != nil || first < 0 {
+			return nil, fmt.Errorf("Invalid port index '%s'", port)
+		}
+
+		last, err := strconv.Atoi(lastStr)
+		if err != nil || last < first {
+			return nil, fmt.Errorf("Invalid port range '%s'", port)
+		}
+
+		ranges = append(ranges, portRange{first: first, last: last})
+	}
+
+	return ranges, nil
+}
+
+func parseInterfaceFilter(flags interfaceFilterFlags) (interfaceFilter, error) {
+	filter := interfaceFilter{
+		drivers: flags.drivers,
+	}
+
+	for _, pattern := range flags.pciAddresses {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return interfaceFilter{}, fmt.Errorf("Invalid PCI address pattern '%s': %v", pattern, err)
+		}
+	}
+
+	filter.pciAddresses = flags.pciAddresses
+
+	for _, name := range flags.names {
+		re, err := regexp.Compile(name)
+		if err != nil {
+			return interfaceFilter{}, fmt.Errorf("Invalid interface name expression '%s': %v", name, err)
+		}
+
+		filter.names = append(filter.names, re)
+	}
+
+	ports, err := parsePortRanges(flags.ports)
+	if err != nil {
+		return interfaceFilter{}, err
+	}
+
+	filter.ports = ports
+
+	return filter, nil
+}
+
+func (f interfaceFilter) empty() bool {
+	return len(f.drivers) == 0 && len(f.pciAddresses) == 0 && len(f.names) == 0 && len(f.ports) == 0
+}
+
+func (f interfaceFilter) matches(iface interfaceInfo) bool {
+	if len(f.drivers) > 0 && !slices.Contains(f.drivers, iface.driver) {
+		return false
+	}
+
+	if len(f.pciAddresses) > 0 && !slices.ContainsFunc(f.pciAddresses, func(pattern string) bool {
+		matched, _ := filepath.Match(pattern, iface.pciAddress)
+		return matched
+	}) {
+		return false
+	}
+
+	if len(f.names) > 0 && !slices.ContainsFunc(f.names, func(re *regexp.Regexp) bool {
+		return re.MatchString(iface.name)
+	}) {
+		return false
+	}
+
+	if len(f.ports) > 0 && !slices.ContainsFunc(f.ports, func(r portRange) bool {
+		return iface.port >= r.first && iface.port <= r.last
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// listedInterfaceInfo describes an interface given by name. The PCI device
+// information is read from sysfs and left empty for other interfaces.
+func listedInterfaceInfo(name string) interfaceInfo {
+	iface := interfaceInfo{name: name, port: -1}
+
+	matches, err := filepath.Glob(filepath.Join(getSysfsRoot(), pciDevicesPath, pciDevicePattern, "net", name))
+	if err != nil || len(matches) == 0 {
+		return iface
+	}
+
+	devicePath := filepath.Dir(filepath.Dir(matches[0]))
+
+	iface.pciAddress = filepath.Base(devicePath)
+	iface.driver = pciDriver(devicePath)
+	iface.port = netDevPort(matches[0])
+
+	return iface
+}
+
+// pciDriver returns the name of the driver bound to the PCI device.
+func pciDriver(devicePath string) string {
+	target, err := os.Readlink(filepath.Join(devicePath, driverLink))
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Base(target)
+}
+
+// netDevPort returns the port index of the network interface on its PCI device.
+func netDevPort(netDevicePath string) int {
+	data, err := os.ReadFile(filepath.Join(netDevicePath, devPortFile))
+	if err != nil {
+		return -1
+	}
+
+	port, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return -1
+	}
+
+	return port
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"os"
+	"path"
+	"slices"
+	"testing"
+)
+
+func TestParseInterfaceFilter(t *testing.T) {
+	filter, err := parseInterfaceFilter(interfaceFilterFlags{
+		drivers:      []string{"habanalabs"},
+		pciAddresses: []string{"0000:b3:00.*"},
+		names:        []string{"^eth_[ab]$"},
+		ports:        []string{"0", "2-4"},
+	})
+	if err != nil {
+		t.Fatalf("cannot parse filter: %v", err)
+	}
+
+	if len(filter.ports) != 2 || filter.ports[1] != (portRange{first: 2, last: 4}) {
+		t.Errorf("unexpected port ranges %v", filter.ports)
+	}
+
+	badFlags := []interfaceFilterFlags{
+		{pciAddresses: []string{"0000:b3:00.["}},
+		{names: []string{"eth_(a"}},
+		{ports: []string{"a"}},
+		{ports: []string{"-1"}},
+		{ports: []string{"4-2"}},
+		{ports: []string{"1-"}},
+	}
+
+	for _, flags := range badFlags {
+		if _, err := parseInterfaceFilter(flags); err == nil {
+			t.Errorf("invalid filter %+v accepted", flags)
+		}
+	}
+}
+
+func TestInterfaceSelection(t *testing.T) {
+	ifaces := []interfaceInfo{
+		{name: "eth0", pciAddress: "0000:b3:00.0", driver: "habanalabs", port: 0},
+		{name: "eth1", pciAddress: "0000:b3:00.0", driver: "habanalabs", port: 1},
+		{name: "eth2", pciAddress: "0000:b3:00.0", driver: "habanalabs", port: 2},
+		{name: "eth24", pciAddress: "0000:b4:00.0", driver: "habanalabs", port: 0},
+		{name: "eth25", pciAddress: "0000:b4:00.0", driver: "habanalabs", port: 1},
+		{name: "ens1f0", pciAddress: "0000:17:00.0", driver: "ice", port: -1},
+	}
+
+	tcases := []struct {
+		name     string
+		include  interfaceFilterFlags
+		exclude  interfaceFilterFlags
+		expected []string
+	}{
+		{
+			name:     "no filters",
+			expected: []string{"eth0", "eth1", "eth2", "eth24", "eth25", "ens1f0"},
+		},
+		{
+			name:     "keep port 0 for management",
+			exclude:  interfaceFilterFlags{ports: []string{"0"}},
+			expected: []string{"eth1", "eth2", "eth25", "ens1f0"},
+		},
+		{
+			name:     "exclude a dead port on a card",
+			exclude:  interfaceFilterFlags{pciAddresses: []string{"0000:b4:*"}, ports: []string{"1"}},
+			expected: []string{"eth0", "eth1", "eth2", "eth24", "ens1f0"},
+		},
+		{
+			name:     "include driver and port range",
+			include:  interfaceFilterFlags{drivers: []string{"habanalabs"}, ports: []string{"1-2"}},
+			expected: []string{"eth1", "eth2", "eth25"},
+		},
+		{
+			name:     "include names",
+			include:  interfaceFilterFlags{names: []string{"^ens", "^eth2$"}},
+			expected: []string{"eth2", "ens1f0"},
+		},
+		{
+			name:     "include and exclude",
+			include:  interfaceFilterFlags{pciAddresses: []string{"0000:b3:00.0"}},
+			exclude:  interfaceFilterFlags{names: []string{"eth0"}},
+			expected: []string{"eth1", "eth2"},
+		},
+	}
+
+	for _, tc := range tcases {
+		include, err := parseInterfaceFilter(tc.include)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		exclude, err := parseInterfaceFilter(tc.exclude)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		selector := deviceSelector{include: include, exclude: exclude}
+
+		selected := []string{}
+		for _, iface := range ifaces {
+			if selector.selects(iface) {
+				selected = append(selected, iface.name)
+			}
+		}
+
+		if !slices.Equal(selected, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, selected)
+		}
+	}
+}
+
+func TestGetNetworksSelection(t *testing.T) {
+	testSysfsRoot, err := os.MkdirTemp("", "networkoperator.")
+	if err != nil {
+		t.Errorf("cannot create tmp dir: %v", err)
+	}
+	defer os.RemoveAll(testSysfsRoot)
+
+	os.Setenv("SYSFS_ROOT", testSysfsRoot)
+
+	devices := getFakeNetworkData()
+	writeFakeSysfsEntries(testSysfsRoot, devices, t)
+
+	for netdev, fakenwconfig := range devices {
+		devport := "1"
+		if netdev == "eth_a" {
+			devport = "0"
+		}
+
+		netdevice := path.Join(testSysfsRoot, sysfsDevicePath, fakenwconfig.pcidevice, netDevicePath, netdev)
+		if err := os.WriteFile(path.Join(netdevice, devPortFile), []byte(devport+"\n"), 0644); err != nil {
+			t.Fatalf("cannot write dev_port file: %v", err)
+		}
+	}
+
+	exclude, _ := parseInterfaceFilter(interfaceFilterFlags{ports: []string{"0"}})
+	devs := getNetworks(deviceSelector{driver: defaultDriver, exclude: exclude})
+	slices.Sort(devs)
+
+	if !slices.Equal(devs, []string{"eth_b", "eth_c"}) {
+		t.Errorf("expected port 0 to be excluded, got %v", devs)
+	}
+
+	include, _ := parseInterfaceFilter(interfaceFilterFlags{drivers: []string{"ice"}})
+	if devs := getNetworks(deviceSelector{driver: defaultDriver, include: include}); len(devs) != 0 {
+		t.Errorf("no devices should have been selected for driver 'ice': %v", devs)
+	}
+
+	include, _ = parseInterfaceFilter(interfaceFilterFlags{pciAddresses: []string{"0000:cc:*"}})
+	if devs := getNetworks(deviceSelector{driver: defaultDriver, include: include}); !slices.Equal(devs, []string{"eth_c"}) {
+		t.Errorf("expected only eth_c to be selected, got %v", devs)
+	}
+}
+
+func TestSelectedInterfacesListed(t *testing.T) {
+	testSysfsRoot := t.TempDir()
+	t.Setenv("SYSFS_ROOT", testSysfsRoot)
+
+	devices := getFakeNetworkData()
+	writeFakeSysfsEntries(testSysfsRoot, devices, t)
+
+	config := &cmdConfig{
+		driver: defaultDriver,
+		// eth_a is both discovered and listed, eth_x isn't a PCI device
+		ifaces: "eth_a,eth_x,eth_a",
+	}
+
+	selected, err := selectedInterfaces(config)
+	if err != nil {
+		t.Fatalf("interface selection failed: %v", err)
+	}
+
+	slices.Sort(selected)
+	if !slices.Equal(selected, []string{"eth_a", "eth_b", "eth_c", "eth_x"}) {
+		t.Errorf("expected each interface once, got %v", selected)
+	}
+
+	// the filters apply to the listed interfaces as well
+	config.exclude, _ = parseInterfaceFilter(interfaceFilterFlags{names: []string{"^eth_[ax]$"}})
+
+	selected, err = selectedInterfaces(config)
+	if err != nil {
+		t.Fatalf("interface selection failed: %v", err)
+	}
+
+	slices.Sort(selected)
+	if !slices.Equal(selected, []string{"eth_b", "eth_c"}) {
+		t.Errorf("expected the listed interfaces to be excluded, got %v", selected)
+	}
+
+	config.exclude = interfaceFilter{}
+	config.include, _ = parseInterfaceFilter(interfaceFilterFlags{pciAddresses: []string{"0000:aa:*"}})
+
+	selected, err = selectedInterfaces(config)
+	if err != nil {
+		t.Fatalf("interface selection failed: %v", err)
+	}
+
+	if !slices.Equal(selected, []string{"eth_a"}) {
+		t.Errorf("expected only eth_a to be included, got %v", selected)
+	}
+}
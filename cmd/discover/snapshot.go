@@ -0,0 +1,490 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+
+	nm "github.com/intel/network-operator/internal/nm"
+)
+
+const (
+	// the address flags that can be set from userspace, the others like
+	// IFA_F_PERMANENT and IFA_F_TENTATIVE are kept by the kernel
+	settableAddressFlags = unix.IFA_F_NODAD | unix.IFA_F_NOPREFIXROUTE | unix.IFA_F_MANAGETEMPADDR
+)
+
+// addressSnapshot is an address of an interface before discover changed it.
+type addressSnapshot struct {
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+	Flags   int    `json:"flags,omitempty"`
+}
+
+// routeSnapshot is a route via an interface before discover changed it.
+type routeSnapshot struct {
+	// empty for the default route
+	Destination string `json:"destination,omitempty"`
+	Gateway     string `json:"gateway,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Protocol    int    `json:"protocol,omitempty"`
+	Scope       int    `json:"scope,omitempty"`
+	Priority    int    `json:"priority,omitempty"`
+}
+
+// linkSnapshot is the state of an interface before discover changed it.
+type linkSnapshot struct {
+	MAC       string            `json:"mac"`
+	Up        bool              `json:"up"`
+	MTU       int               `json:"mtu"`
+	Addresses []addressSnapshot `json:"addresses,omitempty"`
+	Routes    []routeSnapshot   `json:"routes,omitempty"`
+	// set when NetworkManager is disabled for the interfaces
+	NetworkManagerManaged *bool `json:"networkManagerManaged,omitempty"`
+}
+
+// interfacesSnapshot holds the original state of the interfaces by name.
+type interfacesSnapshot struct {
+	Interfaces map[string]*linkSnapshot `json:"interfaces"`
+}
+
+func loadSnapshot(path string) (*interfacesSnapshot, error) {
+	snapshot := &interfacesSnapshot{Interfaces: map[string]*linkSnapshot{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return snapshot, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read interface snapshot: %v", err)
+	}
+
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("cannot parse interface snapshot %s: %v", path, err)
+	}
+
+	if snapshot.Interfaces == nil {
+		snapshot.Interfaces = map[string]*linkSnapshot{}
+	}
+
+	return snapshot, nil
+}
+
+func saveSnapshot(path string, snapshot *interfacesSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("cannot create interface snapshot directory: %v", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("cannot write interface snapshot: %v", err)
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// snapshotLink returns the current state of the interface. The link-local
+// addresses and the routes the kernel adds for the addresses are left out
+// as the kernel restores them.
+func snapshotLink(nwconfig *networkConfiguration) (*linkSnapshot, error) {
+	attrs := nwconfig.link.Attrs()
+
+	snapshot := &linkSnapshot{
+		MAC: attrs.HardwareAddr.String(),
+		Up:  attrs.Flags&net.FlagUp != 0,
+		MTU: attrs.MTU,
+	}
+
+	addrs, err := networkLink.AddrList(nwconfig.link, netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		if addr.IPNet == nil || addr.IPNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+
+		snapshot.Addresses = append(snapshot.Addresses, addressSnapshot{
+			Address: addr.IPNet.String(),
+			Label:   addr.Label,
+			Flags:   addr.Flags,
+		})
+	}
+
+	routes, err := networkLink.RouteList(nwconfig.link, netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, route := range routes {
+		if route.Protocol == unix.RTPROT_KERNEL {
+			continue
+		}
+
+		rs := routeSnapshot{
+			Protocol: int(route.Protocol),
+			Scope:    int(route.Scope),
+			Priority: route.Priority,
+		}
+		if route.Dst != nil {
+			rs.Destination = route.Dst.String()
+		}
+		if route.Gw != nil {
+			rs.Gateway = route.Gw.String()
+		}
+		if route.Src != nil {
+			rs.Source = route.Src.String()
+		}
+
+		snapshot.Routes = append(snapshot.Routes, rs)
+	}
+
+	return snapshot, nil
+}
+
+// updateSnapshot adds the interfaces missing from the snapshot at path to
+// it. Returns the snapshot and whether it changed. nmManaged lists the
+// interfaces NetworkManager manages, nil if NetworkManager is left alone.
+func updateSnapshot(path string, networkConfigs map[string]*networkConfiguration, nmManaged []string) (*interfacesSnapshot, bool, error) {
+	snapshot, err := loadSnapshot(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+
+	for name, nwconfig := range networkConfigs {
+		if _, ok := snapshot.Interfaces[name]; ok {
+			continue
+		}
+
+		ls, err := snapshotLink(nwconfig)
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot snapshot interface '%s': %v", name, err)
+		}
+
+		if nmManaged != nil {
+			managed := slices.Contains(nmManaged, name)
+			ls.NetworkManagerManaged = &managed
+		}
+
+		snapshot.Interfaces[name] = ls
+		changed = true
+	}
+
+	return snapshot, changed, nil
+}
+
+// snapshotInterfaces records the state of the interfaces before they are
+// changed. Interfaces already in the snapshot keep their recorded state, so
+// that the state from before a crashed run is not replaced with the state
+// that run left behind.
+func snapshotInterfaces(path string, networkConfigs map[string]*networkConfiguration, nmManaged []string) error {
+	snapshot, changed, err := updateSnapshot(path, networkConfigs, nmManaged)
+	if err != nil || !changed {
+		return err
+	}
+
+	klog.Infof("Recorded the state of the interfaces in '%s'", path)
+
+	return saveSnapshot(path, snapshot)
+}
+
+func restoreAddress(link netlink.Link, as addressSnapshot) error {
+	ip, ipnet, err := net.ParseCIDR(as.Address)
+	if err != nil {
+		return err
+	}
+
+	ipnet.IP = ip
+
+	// the kernel sets the scope from the address
+	addr := &netlink.Addr{IPNet: ipnet, Label: as.Label, Flags: as.Flags & settableAddressFlags}
+	if err := networkLink.AddrAdd(link, addr); err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+
+	return nil
+}
+
+func restoreRoute(link netlink.Link, rs routeSnapshot) error {
+	route := &netlink.Route{
+		LinkIndex: link.Attrs().Index,
+		Protocol:  netlink.RouteProtocol(rs.Protocol),
+		Scope:     netlink.Scope(rs.Scope),
+		Priority:  rs.Priority,
+		Gw:        net.ParseIP(rs.Gateway),
+		Src:       net.ParseIP(rs.Source),
+	}
+
+	if rs.Destination != "" {
+		_, dst, err := net.ParseCIDR(rs.Destination)
+		if err != nil {
+			return err
+		}
+
+		route.Dst = dst
+	}
+
+	if err := networkLink.RouteAppend(route); err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+
+	return nil
+}
+
+// restoreLink restores the MTU, addresses, routes and link state of the
+// interface from the snapshot.
+func restoreLink(name string, ls *linkSnapshot) error {
+	link, err := networkLink.LinkByName(name)
+	if err != nil {
+		return err
+	}
+
+	if mac := link.Attrs().HardwareAddr.String(); mac != ls.MAC {
+		return fmt.Errorf("MAC address %s doesn't match the recorded %s", mac, ls.MAC)
+	}
+
+	var errs []error
+
+	if link.Attrs().MTU != ls.MTU {
+		if err := networkLink.LinkSetMTU(link, ls.MTU); err != nil {
+			errs = append(errs, fmt.Errorf("cannot set MTU %d: %v", ls.MTU, err))
+		}
+	}
+
+	for _, as := range ls.Addresses {
+		if err := restoreAddress(link, as); err != nil {
+			errs = append(errs, fmt.Errorf("cannot add address %s: %v", as.Address, err))
+		}
+	}
+
+	// the routes need the link up
+	if ls.Up {
+		if err := networkLink.LinkSetUp(link); err != nil {
+			errs = append(errs, fmt.Errorf("cannot set link up: %v", err))
+		}
+
+		for _, rs := range ls.Routes {
+			if err := restoreRoute(link, rs); err != nil {
+				errs = append(errs, fmt.Errorf("cannot add route %s via %s: %v", rs.Destination, rs.Gateway, err))
+			}
+		}
+	} else if err := networkLink.LinkSetDown(link); err != nil {
+		errs = append(errs, fmt.Errorf("cannot set link down: %v", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// restoreSnapshot restores the interfaces to the state in the snapshot and
+// drops them from it, all the interfaces in it when interfaces is nil. The
+// snapshot is removed once it is empty. Interfaces that could not be restored
+// are kept for a later `discover restore`. Returns the names of the
+// NetworkManager managed interfaces.
+func restoreSnapshot(path string, interfaces []string) ([]string, error) {
+	snapshot, err := loadSnapshot(path)
+	if err != nil || len(snapshot.Interfaces) == 0 {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(snapshot.Interfaces))
+	for name := range snapshot.Interfaces {
+		if interfaces == nil || slices.Contains(interfaces, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var (
+		errs      []error
+		nmManaged []string
+	)
+
+	for _, name := range names {
+		ls := snapshot.Interfaces[name]
+
+		if err := restoreLink(name, ls); err != nil {
+			errs = append(errs, fmt.Errorf("interface '%s': %v", name, err))
+
+			continue
+		}
+
+		if ls.NetworkManagerManaged != nil && *ls.NetworkManagerManaged {
+			nmManaged = append(nmManaged, name)
+		}
+
+		delete(snapshot.Interfaces, name)
+
+		klog.Infof("Restored the recorded state of interface '%s'", name)
+	}
+
+	if len(snapshot.Interfaces) > 0 {
+		if len(names) > len(errs) {
+			errs = append(errs, saveSnapshot(path, snapshot))
+		}
+	} else if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		errs = append(errs, fmt.Errorf("cannot remove interface snapshot: %v", err))
+	}
+
+	return nmManaged, errors.Join(errs...)
+}
+
+// restoreRecordedState restores the interfaces of the daemon from its
+// snapshot. With manage, the interfaces NetworkManager managed before they
+// were configured are handed back to it.
+func restoreRecordedState(config *cmdConfig, networkConfigs map[string]*networkConfiguration, manage bool) error {
+	if config.snapshot == "" {
+		return nil
+	}
+
+	names := make([]string, 0, len(networkConfigs))
+	for name := range networkConfigs {
+		names = append(names, name)
+	}
+
+	nmManaged, err := restoreSnapshot(config.snapshot, names)
+
+	if manage && len(nmManaged) > 0 {
+		if nmapi, nmErr := nm.NewNetworkManager(); nmErr != nil {
+			klog.Warningf("Failed to create NetworkManager: %v", nmErr)
+		} else if nmErr := nm.ManageInterfaces(nmapi, nmManaged); nmErr != nil {
+			klog.Warningf("Failed to restore interfaces in NetworkManager: %v", nmErr)
+		}
+	}
+
+	return err
+}
+
+// nmManagedInterfaces returns the interfaces NetworkManager manages.
+func nmManagedInterfaces(interfaces []string) ([]string, error) {
+	nmapi, err := nm.NewNetworkManager()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create NetworkManager: %v", err)
+	}
+
+	managed, err := nm.ManagedInterfaces(nmapi, interfaces)
+	if managed == nil && err == nil {
+		managed = []string{}
+	}
+
+	return managed, err
+}
+
+// restoreInterfaces removes the configuration added by discover and restores
+// the interfaces to the state recorded before it changed them.
+func restoreInterfaces(config *cmdConfig) error {
+	snapshot, err := loadSnapshot(config.snapshot)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshot.Interfaces) == 0 {
+		return fmt.Errorf("No interface state recorded in '%s'", config.snapshot)
+	}
+
+	names := make([]string, 0, len(snapshot.Interfaces))
+	for name := range snapshot.Interfaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var nmapi nm.NetworkManagerIf
+
+	if api, nmErr := nm.NewNetworkManager(); nmErr != nil {
+		klog.Warningf("Failed to create NetworkManager: %v", nmErr)
+	} else if _, nmErr := api.GetPropertyVersion(); nmErr == nil {
+		nmapi = api
+	}
+
+	// connection profiles of the networkmanager backend
+	if nmapi != nil {
+		for _, name := range names {
+			if profileErr := nm.DeleteProfile(nmapi, name); profileErr != nil {
+				klog.Warningf("Could not remove connection profile of interface '%s': %v", name, profileErr)
+			}
+		}
+	}
+
+	networkConfigs := getNetworkConfigs(names)
+
+	if ownedAddresses, err = loadAddressRecord(config.addressRecord); err != nil {
+		return err
+	}
+
+	if err := removeExistingIPs(networkConfigs, false); err != nil {
+		klog.Warningf("Failed to remove the addresses added by discover: %v", err)
+	}
+
+	restoreNetworkManager(config, networkConfigs)
+
+	nmManaged, err := restoreSnapshot(config.snapshot, nil)
+
+	if nmapi != nil && len(nmManaged) > 0 {
+		if nmErr := nm.ManageInterfaces(nmapi, nmManaged); nmErr != nil {
+			klog.Warningf("Failed to restore interfaces in NetworkManager: %v", nmErr)
+		}
+	}
+
+	return err
+}
+
+func setupRestoreCmd() *cobra.Command {
+	config := &cmdConfig{}
+
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the network devices to the state recorded before discover changed them",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			return restoreInterfaces(config)
+		},
+	}
+
+	cmd.Flags().StringVarP(&config.snapshot, "snapshot", "", "",
+		"File on the host with the recorded state of the interfaces")
+	_ = cmd.MarkFlagRequired("snapshot")
+	cmd.Flags().StringVarP(&config.addressRecord, "address-record", "", "",
+		"File on the host recording the addresses added by discover")
+	cmd.Flags().StringVarP(&config.nmRecord, "networkmanager-record", "", nm.DefaultRecordPath,
+		"File on the host recording the interfaces disabled in NetworkManager")
+	cmd.Flags().StringVarP(&config.nmConfDir, "networkmanager-conf-dir", "", nm.DefaultConfDir,
+		"NetworkManager configuration snippet directory")
+	cmd.Flags().StringVarP(&config.nmConfFile, "networkmanager-conf-file", "", nm.DefaultConfFile,
+		"Name of the NetworkManager configuration snippet")
+
+	return cmd
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	var (
+		mtus          []int
+		addedAddrs    []string
+		appendedRoute []string
+		linkUps       int
+	)
+
+	mac := net.HardwareAddr{0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+
+	ipnet := func(cidr string) *net.IPNet {
+		ip, network, _ := net.ParseCIDR(cidr)
+		network.IP = ip
+		return network
+	}
+
+	original := &fakeLink{fakeAttrs: netlink.LinkAttrs{
+		Name: "eth_a", HardwareAddr: mac, MTU: 1500, Flags: net.FlagUp,
+	}}
+	configured := &fakeLink{fakeAttrs: netlink.LinkAttrs{
+		Name: "eth_a", HardwareAddr: mac, MTU: 8000, Flags: net.FlagUp,
+	}}
+
+	networkLink.AddrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return []netlink.Addr{
+			{IPNet: ipnet("192.168.0.10/24"), Label: "eth_a"},
+			{IPNet: ipnet("fd00::10/64"), Flags: unix.IFA_F_PERMANENT | unix.IFA_F_NODAD, Scope: unix.RT_SCOPE_UNIVERSE},
+			{IPNet: ipnet("fe80::1/64")},
+		}, nil
+	}
+	networkLink.RouteList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		return []netlink.Route{
+			{Dst: ipnet("192.168.0.0/24"), Protocol: unix.RTPROT_KERNEL},
+			{Dst: ipnet("10.0.0.0/8"), Gw: net.ParseIP("192.168.0.1"), Protocol: unix.RTPROT_BOOT},
+		}, nil
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	networkConfigs := map[string]*networkConfiguration{"eth_a": {link: original}}
+
+	if err := snapshotInterfaces(path, networkConfigs, []string{"eth_a"}); err != nil {
+		t.Fatalf("cannot snapshot interfaces: %v", err)
+	}
+
+	// a restarted run must not replace the original state
+	if err := snapshotInterfaces(path, map[string]*networkConfiguration{"eth_a": {link: configured}}, nil); err != nil {
+		t.Fatalf("cannot snapshot interfaces: %v", err)
+	}
+
+	snapshot, err := loadSnapshot(path)
+	if err != nil {
+		t.Fatalf("cannot load snapshot: %v", err)
+	}
+
+	ls := snapshot.Interfaces["eth_a"]
+	if ls == nil || ls.MTU != 1500 || !ls.Up || len(ls.Addresses) != 2 || len(ls.Routes) != 1 ||
+		ls.NetworkManagerManaged == nil || !*ls.NetworkManagerManaged {
+		t.Fatalf("unexpected snapshot %+v", ls)
+	}
+
+	networkLink.LinkByName = func(name string) (netlink.Link, error) {
+		return configured, nil
+	}
+	networkLink.LinkSetMTU = func(link netlink.Link, mtu int) error {
+		mtus = append(mtus, mtu)
+		return nil
+	}
+	networkLink.AddrAdd = func(link netlink.Link, addr *netlink.Addr) error {
+		addedAddrs = append(addedAddrs, fmt.Sprintf("%s %s %d", addr.IPNet, addr.Label, addr.Flags))
+		return nil
+	}
+	networkLink.RouteAppend = func(route *netlink.Route) error {
+		appendedRoute = append(appendedRoute, route.Dst.String()+" via "+route.Gw.String())
+		return nil
+	}
+	networkLink.LinkSetUp = func(link netlink.Link) error {
+		linkUps++
+		return nil
+	}
+
+	nmManaged, err := restoreSnapshot(path, []string{"eth_a"})
+	if err != nil {
+		t.Fatalf("cannot restore snapshot: %v", err)
+	}
+
+	if !slices.Equal(mtus, []int{1500}) || !slices.Equal(addedAddrs, []string{"192.168.0.10/24 eth_a 0", "fd00::10/64  2"}) ||
+		!slices.Equal(appendedRoute, []string{"10.0.0.0/8 via 192.168.0.1"}) || linkUps != 1 {
+		t.Errorf("unexpected restore: MTU %v, addresses %v, routes %v, link ups %d", mtus, addedAddrs, appendedRoute, linkUps)
+	}
+
+	if !slices.Equal(nmManaged, []string{"eth_a"}) {
+		t.Errorf("unexpected NetworkManager managed interfaces %v", nmManaged)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("snapshot was not removed: %v", err)
+	}
+}
+
+func TestRestoreSnapshotMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	snapshot := &interfacesSnapshot{Interfaces: map[string]*linkSnapshot{
+		"eth_a": {MAC: "02:00:00:00:00:01", MTU: 1500},
+	}}
+	if err := saveSnapshot(path, snapshot); err != nil {
+		t.Fatalf("cannot save snapshot: %v", err)
+	}
+
+	networkLink.LinkByName = fakeLinkByName
+
+	if _, err := restoreSnapshot(path, nil); err == nil {
+		t.Error("interface with another MAC address restored")
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("snapshot of an interface that wasn't restored was removed: %v", err)
+	}
+
+	if err := restoreInterfaces(&cmdConfig{snapshot: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("restore without a snapshot succeeded")
+	}
+}
+
+func TestRestoreSnapshotSelection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	snapshot := &interfacesSnapshot{Interfaces: map[string]*linkSnapshot{
+		"eth_a": {MAC: "02:00:00:00:00:01", MTU: 1500},
+		"eth_b": {MAC: "02:00:00:00:00:02", MTU: 1500},
+	}}
+	if err := saveSnapshot(path, snapshot); err != nil {
+		t.Fatalf("cannot save snapshot: %v", err)
+	}
+
+	var restored []string
+
+	networkLink.LinkByName = func(name string) (netlink.Link, error) {
+		restored = append(restored, name)
+		mac, _ := net.ParseMAC(snapshot.Interfaces[name].MAC)
+		return &fakeLink{fakeAttrs: netlink.LinkAttrs{Name: name, HardwareAddr: mac, MTU: 1500}}, nil
+	}
+	networkLink.LinkSetDown = func(link netlink.Link) error {
+		return nil
+	}
+
+	if _, err := restoreSnapshot(path, []string{"eth_a", "eth_c"}); err != nil {
+		t.Fatalf("cannot restore snapshot: %v", err)
+	}
+
+	if !slices.Equal(restored, []string{"eth_a"}) {
+		t.Errorf("unexpected restored interfaces %v", restored)
+	}
+
+	// the interfaces of other daemons stay recorded
+	left, err := loadSnapshot(path)
+	if err != nil {
+		t.Fatalf("cannot load snapshot: %v", err)
+	}
+
+	if _, ok := left.Interfaces["eth_b"]; !ok || len(left.Interfaces) != 1 {
+		t.Errorf("unexpected interfaces left in the snapshot %v", left.Interfaces)
+	}
+}
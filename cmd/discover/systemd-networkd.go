@@ -18,7 +18,6 @@ package main
 
 import (
 	"fmt"
-	"net"
 	"os"
 	"path/filepath"
 )
@@ -46,27 +45,35 @@ func checkNetworkConfig(ifname string, nwconfig *networkConfiguration) error {
 	return nil
 }
 
-func writeNetwork(networkdpath string, ifname string, nwconfig *networkConfiguration) error {
-	networkMask := net.CIDRMask(int(RouteMaskRoutedNetwork), 32)
-	networkAddr := nwconfig.localAddr.Mask(networkMask)
+// networkdConfig returns the systemd-networkd configuration of the interface.
+func networkdConfig(ifname string, nwconfig *networkConfiguration) string {
+	pointToPointLen, _ := pointToPointMask(nwconfig).Size()
 
 	network := fmt.Sprintf("[Match]\n"+
 		"MACAddress=%s\n"+
 		"\n"+
 		"[Network]\n"+
 		"Description=Networkd configuration for %s created by network-operator\n"+
-		"Address=%s/%d\n"+
-		"\n"+
-		"[Route]\n"+
-		"Destination=%s/%d\n",
+		"Address=%s/%d\n",
 		nwconfig.link.Attrs().HardwareAddr.String(),
 		ifname,
-		nwconfig.localAddr.String(), int(RouteMaskPointToPoint),
-		networkAddr, int(RouteMaskRoutedNetwork),
+		nwconfig.localAddr.String(), pointToPointLen,
 	)
 
+	for _, dst := range routedNetworkDestinations(nwconfig) {
+		network += fmt.Sprintf("\n"+
+			"[Route]\n"+
+			"Destination=%s\n",
+			dst.String(),
+		)
+	}
+
+	return network
+}
+
+func writeNetwork(networkdpath string, ifname string, nwconfig *networkConfiguration) error {
 	filename := networkdFilename(networkdpath, ifname)
-	if err := os.WriteFile(filename, []byte(network), 0644); err != nil {
+	if err := os.WriteFile(filename, []byte(networkdConfig(ifname, nwconfig)), 0644); err != nil {
 		return fmt.Errorf("could not write networkd config file '%s': %v", filename, err)
 	}
 
@@ -71,7 +71,7 @@ func fakesystemdnetworkdconfigs() (map[string]*networkConfiguration, map[string]
 		if nwconfig.localAddr == nil {
 			expectedoutput[iface] = ""
 		} else {
-			networkAddr := nwconfig.localAddr.Mask(net.CIDRMask(int(RouteMaskRoutedNetwork), 32))
+			networkAddr := nwconfig.localAddr.Mask(net.CIDRMask(int(RouteMaskRoutedNetwork), ipv4Bits))
 
 			expectedoutput[iface] = "[Match]\nMACAddress=" +
 				nwconfig.link.Attrs().HardwareAddr.String() +
@@ -132,6 +132,73 @@ func TestSystemdNetworkdConfig(t *testing.T) {
 	}
 }
 
+func TestSystemdNetworkdConfigIPv6(t *testing.T) {
+	testDir, err := os.MkdirTemp("", "networkoperator.")
+	if err != nil {
+		t.Errorf("cannot create tmp dir: %v", err)
+	}
+	defer os.RemoveAll(testDir)
+
+	addr := net.ParseIP("fd00:10:210:8::")
+	nwconfig := networkConfiguration{
+		link: &fakeLink{
+			fakeAttrs: netlink.LinkAttrs{
+				HardwareAddr: net.HardwareAddr{0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f},
+			},
+		},
+		localAddr: &addr,
+		prefixLen: 127,
+	}
+
+	expectedstr := "[Match]\nMACAddress=0a:0b:0c:0d:0e:0f\n\n" +
+		"[Network]\nDescription=Networkd configuration for eth_a created by network-operator\n" +
+		"Address=fd00:10:210:8::/127\n\n" +
+		"[Route]\nDestination=fd00:10:210:8::/64\n"
+
+	if _, err := WriteSystemdNetworkd(testDir, map[string]*networkConfiguration{"eth_a": &nwconfig}); err != nil {
+		t.Fatalf("could not create config file: %v", err)
+	}
+
+	configuredstr, err := os.ReadFile(networkdFilename(testDir, "eth_a"))
+	if string(configuredstr) != expectedstr {
+		t.Errorf("expected\n'%s', got \n'%s': %v", expectedstr, string(configuredstr), err)
+	}
+}
+
+func TestSystemdNetworkdConfigRoutedNetworks(t *testing.T) {
+	testDir, err := os.MkdirTemp("", "networkoperator.")
+	if err != nil {
+		t.Errorf("cannot create tmp dir: %v", err)
+	}
+	defer os.RemoveAll(testDir)
+
+	addr := net.IPv4(10, 210, 8, 121)
+	nwconfig := networkConfiguration{
+		link: &fakeLink{
+			fakeAttrs: netlink.LinkAttrs{
+				HardwareAddr: net.HardwareAddr{0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f},
+			},
+		},
+		localAddr: &addr,
+	}
+	nwconfig.routedNetworks, _ = parseRoutedNetworks([]string{"10.192.0.0/12", "/20"})
+
+	expectedstr := "[Match]\nMACAddress=0a:0b:0c:0d:0e:0f\n\n" +
+		"[Network]\nDescription=Networkd configuration for eth_a created by network-operator\n" +
+		"Address=10.210.8.121/30\n\n" +
+		"[Route]\nDestination=10.192.0.0/12\n\n" +
+		"[Route]\nDestination=10.210.0.0/20\n"
+
+	if _, err := WriteSystemdNetworkd(testDir, map[string]*networkConfiguration{"eth_a": &nwconfig}); err != nil {
+		t.Fatalf("could not create config file: %v", err)
+	}
+
+	configuredstr, err := os.ReadFile(networkdFilename(testDir, "eth_a"))
+	if string(configuredstr) != expectedstr {
+		t.Errorf("expected\n'%s', got \n'%s': %v", expectedstr, string(configuredstr), err)
+	}
+}
+
 func TestSystemdNetworkdConfigNoDir(t *testing.T) {
 	testDir, err := os.MkdirTemp("", "networkoperator.")
 	if err != nil {
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	teardownPollInterval = 10 * time.Second
+)
+
+// teardownPending returns true when the operator has asked the node to be
+// cleaned up.
+func teardownPending(config *cmdConfig, reporter *nodeStateReporter) bool {
+	if reporter == nil {
+		return false
+	}
+
+	requested, err := reporter.teardownRequested(config.ctx)
+	if err != nil {
+		klog.V(3).Infof("Could not check for node teardown: %v", err)
+
+		return false
+	}
+
+	return requested
+}
+
+// teardownNode removes the configuration done for the policy from the node:
+// addresses and routes, the gaudinet and systemd-networkd files and the NFD
+// labels. Interfaces disabled in NetworkManager are handed back to it.
+func teardownNode(config *cmdConfig, networkConfigs map[string]*networkConfiguration) {
+	klog.Info("Policy is being deleted, removing the node configuration...")
+
+	names := make([]string, 0, len(networkConfigs))
+	for name, nwconfig := range networkConfigs {
+		deconfigureInterface(nwconfig)
+
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if config.gaudinetfile != "" {
+		if err := os.Remove(config.gaudinetfile); err != nil && !os.IsNotExist(err) {
+			klog.Warningf("Failed to remove %s: %v", config.gaudinetfile, err)
+		}
+	}
+
+	if config.networkd != "" {
+		DeleteSystemdNetworkd(config.networkd, names)
+	}
+
+	postCleanups(config, networkConfigs, true)
+
+	klog.Info("Node configuration removed")
+}
+
+// idleAfterTeardown keeps the daemon running once the node has been cleaned
+// up so that the DaemonSet doesn't restart it to configure the node again.
+func idleAfterTeardown(config *cmdConfig, reporter *nodeStateReporter, networkConfigs map[string]*networkConfiguration) {
+	term := make(chan os.Signal, 1)
+
+	signal.Notify(term, os.Interrupt, syscall.SIGTERM)
+
+	retry := time.NewTicker(nodeStateRetryInterval)
+	defer retry.Stop()
+
+	if reportNodeState(config, reporter, networkConfigs, nil) {
+		retry.Stop()
+	}
+
+	klog.Info("Node cleaned up. Idling...")
+
+	for {
+		select {
+		case <-term:
+			return
+		case <-retry.C:
+			if reportNodeState(config, reporter, networkConfigs, nil) {
+				retry.Stop()
+			}
+		}
+	}
+}
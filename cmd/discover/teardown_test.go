@@ -0,0 +1,181 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+func TestTeardownNode(t *testing.T) {
+	var deletedAddrs, deletedRoutes []string
+
+	networkLink.AddrList = func(link netlink.Link, family int) ([]netlink.Addr, error) {
+		return []netlink.Addr{}, nil
+	}
+	networkLink.AddrDel = func(link netlink.Link, addr *netlink.Addr) error {
+		deletedAddrs = append(deletedAddrs, addr.IPNet.String())
+		return nil
+	}
+	networkLink.RouteList = func(link netlink.Link, family int) ([]netlink.Route, error) {
+		return []netlink.Route{}, nil
+	}
+	networkLink.RouteDel = func(route *netlink.Route) error {
+		deletedRoutes = append(deletedRoutes, route.Dst.String()+" via "+route.Gw.String())
+		return nil
+	}
+	networkLink.LinkSetDown = func(link netlink.Link) error {
+		return nil
+	}
+
+	tmpDir := t.TempDir()
+
+	config := &cmdConfig{
+		ctx:          context.Background(),
+		gaudinetfile: path.Join(tmpDir, "gaudinet.json"),
+		networkd:     path.Join(tmpDir, "networkd"),
+	}
+
+	nwconfigs := getFakeNetworkDataConfigs()
+	_ = lldpResults(nwconfigs)
+
+	routed, _ := parseRoutedNetworks([]string{"10.210.0.0/16"})
+	for _, nwconfig := range nwconfigs {
+		nwconfig.routedNetworks = routed
+	}
+
+	if err := os.MkdirAll(config.networkd, 0755); err != nil {
+		t.Fatalf("cannot create networkd dir: %v", err)
+	}
+	if err := os.WriteFile(config.gaudinetfile, []byte("{}"), 0644); err != nil {
+		t.Fatalf("cannot write gaudinet file: %v", err)
+	}
+	networkdFiles, err := WriteSystemdNetworkd(config.networkd,
+		map[string]*networkConfiguration{"eth_a": nwconfigs["eth_a"]})
+	if err != nil || len(networkdFiles) == 0 {
+		t.Fatalf("cannot write networkd files: %v", err)
+	}
+
+	teardownNode(config, nwconfigs)
+
+	if len(deletedAddrs) == 0 || len(deletedRoutes) == 0 {
+		t.Errorf("addresses %v and routes %v were not removed", deletedAddrs, deletedRoutes)
+	}
+
+	for _, nwconfig := range nwconfigs {
+		if nwconfig.localAddr != nil {
+			t.Errorf("interface '%s' still has an address configured", nwconfig.link.Attrs().Name)
+		}
+	}
+
+	if _, err := os.Stat(config.gaudinetfile); !os.IsNotExist(err) {
+		t.Errorf("gaudinet file was not removed: %v", err)
+	}
+
+	for _, filename := range networkdFiles {
+		if _, err := os.Stat(filename); !os.IsNotExist(err) {
+			t.Errorf("networkd file %s was not removed: %v", filename, err)
+		}
+	}
+}
+
+func TestTeardownRequested(t *testing.T) {
+	networkLink.LinkByName = fakeLinkByName
+	networkLink.AddrList = fakeLinkAddrList
+	networkLink.RouteList = fakeRouteList
+
+	scheme := runtime.NewScheme()
+	if err := networkv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot add scheme: %v", err)
+	}
+
+	state := &networkv1alpha1.NetworkNodeState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      networkv1alpha1.NetworkNodeStateName("policy", "node"),
+			Namespace: "ns",
+			Labels:    networkv1alpha1.NetworkNodeStateLabels("policy", "node"),
+		},
+		Spec: networkv1alpha1.NetworkNodeStateSpec{
+			NodeName: "node",
+			Policy:   "policy",
+		},
+	}
+
+	reporter := &nodeStateReporter{
+		client: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(state).
+			WithStatusSubresource(state).
+			Build(),
+		policy:    "policy",
+		node:      "node",
+		namespace: state.Namespace,
+	}
+
+	ctx := context.Background()
+	config := &cmdConfig{ctx: ctx}
+
+	if teardownPending(config, nil) {
+		t.Error("teardown pending without a reporter")
+	}
+
+	if teardownPending(config, reporter) {
+		t.Error("teardown pending before it was requested")
+	}
+
+	key := types.NamespacedName{Name: state.Name, Namespace: state.Namespace}
+	if err := reporter.client.Get(ctx, key, state); err != nil {
+		t.Fatalf("cannot get node state: %v", err)
+	}
+
+	state.Spec.Teardown = true
+	if err := reporter.client.Update(ctx, state); err != nil {
+		t.Fatalf("cannot update node state: %v", err)
+	}
+
+	if !teardownPending(config, reporter) {
+		t.Error("requested teardown not detected")
+	}
+
+	reporter.cleanedUp = true
+	if err := reporter.report(ctx, getFakeNetworkDataConfigs(), nil); err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+
+	if err := reporter.client.Get(ctx, key, state); err != nil {
+		t.Fatalf("cannot get node state: %v", err)
+	}
+
+	if state.Status.State != networkv1alpha1.NodeStateCleanedUp {
+		t.Errorf("expected node state '%s', got '%s'", networkv1alpha1.NodeStateCleanedUp, state.Status.State)
+	}
+
+	reporter.node = "missing"
+	if teardownPending(config, reporter) {
+		t.Error("teardown pending for a missing node state")
+	}
+}
@@ -203,6 +203,7 @@ func main() {
 	if err = (&controller.NetworkClusterPolicyReconciler{
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("network-operator"),
 		Namespace: ns,
 	}).SetupWithManager(mgr, isInOpenShift); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NetworkClusterPolicy")
@@ -29,6 +29,18 @@ var contentGaudiDiscoveryDs []byte
 //go:embed generic/linkdiscovery-serviceaccount.yaml
 var contentLinkDiscoveryServiceAccount []byte
 
+//go:embed generic/linkdiscovery-role.yaml
+var contentLinkDiscoveryRole []byte
+
+//go:embed generic/linkdiscovery-events-role.yaml
+var contentLinkDiscoveryEventsRole []byte
+
+//go:embed generic/linkdiscovery-rolebinding.yaml
+var contentLinkDiscoveryRoleBinding []byte
+
+//go:embed generic/linkdiscovery-metrics-auth-clusterrolebinding.yaml
+var contentLinkDiscoveryMetricsAuthBinding []byte
+
 //go:embed openshift/rolebinding.yaml
 var contentOpenshiftRoleBinding []byte
 
@@ -40,6 +52,22 @@ func GaudiLinkDiscoveryServiceAccount() *core.ServiceAccount {
 	return getServiceAccount(contentLinkDiscoveryServiceAccount).DeepCopy()
 }
 
+func GaudiLinkDiscoveryRole() *rbac.Role {
+	return getRole(contentLinkDiscoveryRole).DeepCopy()
+}
+
+func GaudiLinkDiscoveryEventsRole() *rbac.Role {
+	return getRole(contentLinkDiscoveryEventsRole).DeepCopy()
+}
+
+func GaudiLinkDiscoveryRoleBinding() *rbac.RoleBinding {
+	return getRoleBinding(contentLinkDiscoveryRoleBinding).DeepCopy()
+}
+
+func GaudiLinkDiscoveryMetricsAuthBinding() *rbac.ClusterRoleBinding {
+	return getClusterRoleBinding(contentLinkDiscoveryMetricsAuthBinding).DeepCopy()
+}
+
 func OpenShiftRoleBinding() *rbac.RoleBinding {
 	return getRoleBinding(contentOpenshiftRoleBinding).DeepCopy()
 }
@@ -68,6 +96,18 @@ func getServiceAccount(content []byte) *core.ServiceAccount {
 	return &result
 }
 
+// getRole unmarshalls yaml content into a Role object.
+func getRole(content []byte) *rbac.Role {
+	var result rbac.Role
+
+	err := yaml.Unmarshal(content, &result)
+	if err != nil {
+		panic(err)
+	}
+
+	return &result
+}
+
 // getRoleBinding unmarshalls yaml content into a RoleBinding object.
 func getRoleBinding(content []byte) *rbac.RoleBinding {
 	var result rbac.RoleBinding
@@ -79,3 +119,15 @@ func getRoleBinding(content []byte) *rbac.RoleBinding {
 
 	return &result
 }
+
+// getClusterRoleBinding unmarshalls yaml content into a ClusterRoleBinding object.
+func getClusterRoleBinding(content []byte) *rbac.ClusterRoleBinding {
+	var result rbac.ClusterRoleBinding
+
+	err := yaml.Unmarshal(content, &result)
+	if err != nil {
+		panic(err)
+	}
+
+	return &result
+}
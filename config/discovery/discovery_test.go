@@ -32,9 +32,58 @@ func TestGaudiServiceAccount(t *testing.T) {
 	}
 }
 
+func TestGaudiRole(t *testing.T) {
+	role := GaudiLinkDiscoveryRole()
+	if role == nil || len(role.Rules) == 0 {
+		t.Error("expected to receive a valid role")
+	}
+}
+
+func TestGaudiEventsRole(t *testing.T) {
+	role := GaudiLinkDiscoveryEventsRole()
+	if role == nil || len(role.Rules) == 0 || role.Rules[0].Resources[0] != "events" {
+		t.Error("expected to receive a valid events role")
+	}
+}
+
+func TestGaudiRoleBinding(t *testing.T) {
+	rb := GaudiLinkDiscoveryRoleBinding()
+	if rb == nil {
+		t.Error("expected to receive a valid role binding")
+	}
+}
+
+func TestGaudiMetricsAuthBinding(t *testing.T) {
+	crb := GaudiLinkDiscoveryMetricsAuthBinding()
+	if crb == nil || crb.RoleRef.Kind != "ClusterRole" || crb.RoleRef.Name != "system:auth-delegator" {
+		t.Error("expected to receive a valid cluster role binding")
+	}
+}
+
 func TestOpenShiftRoleBinding(t *testing.T) {
 	rb := OpenShiftRoleBinding()
 	if rb == nil {
 		t.Error("expected to receive a valid role binding")
 	}
 }
+
+func TestDaemonsetMetricsPort(t *testing.T) {
+	ds := GaudiDiscoveryDaemonSet()
+
+	// the controller adds the port when the metrics are enabled
+	container := ds.Spec.Template.Spec.Containers[0]
+	if len(container.Ports) != 0 {
+		t.Errorf("expected no metrics port, got %v", container.Ports)
+	}
+
+	hostIP := false
+	for _, env := range container.Env {
+		if env.Name == "HOST_IP" && env.ValueFrom != nil && env.ValueFrom.FieldRef.FieldPath == "status.hostIP" {
+			hostIP = true
+		}
+	}
+
+	if !hostIP {
+		t.Error("expected the host IP in the environment for the metrics address")
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	"github.com/go-logr/logr"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+const (
+	// configurationTypeLabel records the configuration type the DaemonSet was created for.
+	configurationTypeLabel = "intel.com/configuration-type"
+)
+
+// policyChildren holds the names of the objects a policy should own.
+type policyChildren struct {
+	daemonSet      types.NamespacedName
+	serviceAccount types.NamespacedName
+	roles          []types.NamespacedName
+	roleBindings   []types.NamespacedName
+	// cluster scoped, the namespaces are empty
+	clusterRoleBindings []types.NamespacedName
+}
+
+func serviceAccountName(cr *networkv1alpha1.NetworkClusterPolicy) string {
+	return cr.Name + "-sa"
+}
+
+func linkDiscoveryRoleName(serviceAccountName string) string {
+	return serviceAccountName + "-role"
+}
+
+func (r *NetworkClusterPolicyReconciler) eventsRoleName(serviceAccountName string) string {
+	return r.Namespace + "-" + serviceAccountName + "-events"
+}
+
+// metricsAuthBindingName is cluster wide, the name includes the namespace
+// like the events role in the default namespace.
+func (r *NetworkClusterPolicyReconciler) metricsAuthBindingName(serviceAccountName string) string {
+	return r.Namespace + "-" + serviceAccountName + "-metrics-auth"
+}
+
+func openShiftRoleBindingName(serviceAccountName string) string {
+	return serviceAccountName + "-rb"
+}
+
+// policyMetricsPort returns the metrics port of the configuration type
+// selected by the policy, zero when the metrics are disabled.
+func policyMetricsPort(cr *networkv1alpha1.NetworkClusterPolicy) int32 {
+	switch cr.Spec.ConfigurationType {
+	case gaudiScaleOutSelection:
+		return cr.Spec.GaudiScaleOut.MetricsPort
+	case hostNICSelection:
+		return cr.Spec.HostNIC.MetricsPort
+	default:
+		return 0
+	}
+}
+
+// desiredChildren returns the objects the policy should own. The names
+// match the ones used when the DaemonSet and its collateral are created.
+func (r *NetworkClusterPolicyReconciler) desiredChildren(cr *networkv1alpha1.NetworkClusterPolicy) policyChildren {
+	saName := serviceAccountName(cr)
+	eventsRole := r.eventsRoleName(saName)
+
+	children := policyChildren{
+		daemonSet:      types.NamespacedName{Name: cr.Name, Namespace: r.Namespace},
+		serviceAccount: types.NamespacedName{Name: saName, Namespace: r.Namespace},
+		roles: []types.NamespacedName{
+			{Name: linkDiscoveryRoleName(saName), Namespace: r.Namespace},
+			{Name: eventsRole, Namespace: metav1.NamespaceDefault},
+		},
+		roleBindings: []types.NamespacedName{
+			{Name: linkDiscoveryRoleName(saName) + "-rb", Namespace: r.Namespace},
+			{Name: eventsRole + "-rb", Namespace: metav1.NamespaceDefault},
+		},
+	}
+
+	if r.isOpenShift {
+		children.roleBindings = append(children.roleBindings,
+			types.NamespacedName{Name: openShiftRoleBindingName(saName), Namespace: r.Namespace})
+	}
+
+	if policyMetricsPort(cr) > 0 {
+		children.clusterRoleBindings = append(children.clusterRoleBindings,
+			types.NamespacedName{Name: r.metricsAuthBindingName(saName)})
+	}
+
+	return children
+}
+
+// daemonSetConfigurationType returns the configuration type the DaemonSet
+// was created for. DaemonSets created before the label was introduced are
+// for Gaudi scale-out.
+func daemonSetConfigurationType(ds *apps.DaemonSet) string {
+	if configType, ok := ds.Labels[configurationTypeLabel]; ok {
+		return configType
+	}
+
+	return gaudiScaleOutSelection
+}
+
+// partitionDaemonSets returns the DaemonSet to keep for the policy and the
+// stale ones. A DaemonSet is stale when its name or configuration type
+// doesn't match the policy.
+func partitionDaemonSets(daemonSets []apps.DaemonSet, cr *networkv1alpha1.NetworkClusterPolicy, desired types.NamespacedName) (*apps.DaemonSet, []*apps.DaemonSet) {
+	var current *apps.DaemonSet
+
+	stale := []*apps.DaemonSet{}
+
+	for i := range daemonSets {
+		ds := &daemonSets[i]
+
+		if current == nil && client.ObjectKeyFromObject(ds) == desired &&
+			daemonSetConfigurationType(ds) == cr.Spec.ConfigurationType {
+			current = ds
+
+			continue
+		}
+
+		stale = append(stale, ds)
+	}
+
+	return current, stale
+}
+
+func (r *NetworkClusterPolicyReconciler) deleteStaleObject(ctx context.Context, log logr.Logger, cr *networkv1alpha1.NetworkClusterPolicy, obj client.Object, kind string) error {
+	if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "unable to delete stale object", "kind", kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+
+		return err
+	}
+
+	log.Info("Stale object deleted", "kind", kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+	r.recordEvent(cr, v1.EventTypeNormal, eventReasonStaleObjectDeleted, "Deleted stale %s %s/%s", kind, obj.GetNamespace(), obj.GetName())
+
+	return nil
+}
+
+// deleteStaleCollateral deletes the service accounts, roles, role bindings and
+// cluster role bindings owned by the policy that are no longer desired.
+func (r *NetworkClusterPolicyReconciler) deleteStaleCollateral(ctx context.Context, log logr.Logger, cr *networkv1alpha1.NetworkClusterPolicy, desired policyChildren) error {
+	owned := client.MatchingFields{ownerKey: cr.Name}
+
+	var serviceAccounts v1.ServiceAccountList
+	if err := r.List(ctx, &serviceAccounts, owned); err != nil {
+		log.Error(err, "unable to list child ServiceAccounts")
+
+		return err
+	}
+
+	for i := range serviceAccounts.Items {
+		sa := &serviceAccounts.Items[i]
+
+		if client.ObjectKeyFromObject(sa) == desired.serviceAccount {
+			continue
+		}
+
+		if err := r.deleteStaleObject(ctx, log, cr, sa, "ServiceAccount"); err != nil {
+			return err
+		}
+	}
+
+	var roles rbac.RoleList
+	if err := r.List(ctx, &roles, owned); err != nil {
+		log.Error(err, "unable to list child Roles")
+
+		return err
+	}
+
+	for i := range roles.Items {
+		role := &roles.Items[i]
+
+		if slices.Contains(desired.roles, client.ObjectKeyFromObject(role)) {
+			continue
+		}
+
+		if err := r.deleteStaleObject(ctx, log, cr, role, "Role"); err != nil {
+			return err
+		}
+	}
+
+	var roleBindings rbac.RoleBindingList
+	if err := r.List(ctx, &roleBindings, owned); err != nil {
+		log.Error(err, "unable to list child RoleBindings")
+
+		return err
+	}
+
+	for i := range roleBindings.Items {
+		rb := &roleBindings.Items[i]
+
+		if slices.Contains(desired.roleBindings, client.ObjectKeyFromObject(rb)) {
+			continue
+		}
+
+		if err := r.deleteStaleObject(ctx, log, cr, rb, "RoleBinding"); err != nil {
+			return err
+		}
+	}
+
+	var clusterRoleBindings rbac.ClusterRoleBindingList
+	if err := r.List(ctx, &clusterRoleBindings, owned); err != nil {
+		log.Error(err, "unable to list child ClusterRoleBindings")
+
+		return err
+	}
+
+	for i := range clusterRoleBindings.Items {
+		crb := &clusterRoleBindings.Items[i]
+
+		if slices.Contains(desired.clusterRoleBindings, client.ObjectKeyFromObject(crb)) {
+			continue
+		}
+
+		if err := r.deleteStaleObject(ctx, log, cr, crb, "ClusterRoleBinding"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+var _ = Describe("NetworkClusterPolicy children", func() {
+	const ns = "intel-network-operator"
+
+	var (
+		cr *networkv1alpha1.NetworkClusterPolicy
+		r  *NetworkClusterPolicyReconciler
+	)
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(networkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		cr = &networkv1alpha1.NetworkClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "policy",
+				UID:  "policy-uid",
+			},
+			Spec: networkv1alpha1.NetworkClusterPolicySpec{
+				ConfigurationType: "gaudi-so",
+			},
+		}
+
+		indexFunc := ownerIndexFunc(networkv1alpha1.GroupVersion.String(), "NetworkClusterPolicy")
+
+		r = &NetworkClusterPolicyReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(s).
+				WithIndex(&v1.ServiceAccount{}, ownerKey, indexFunc).
+				WithIndex(&rbac.Role{}, ownerKey, indexFunc).
+				WithIndex(&rbac.RoleBinding{}, ownerKey, indexFunc).
+				WithIndex(&rbac.ClusterRoleBinding{}, ownerKey, indexFunc).
+				WithIndex(&networkv1alpha1.NetworkNodeState{}, ownerKey, indexFunc).
+				Build(),
+			Scheme:    s,
+			Namespace: ns,
+		}
+	})
+
+	owned := func(obj client.Object, name, namespace string) client.Object {
+		obj.SetName(name)
+		obj.SetNamespace(namespace)
+		Expect(ctrl.SetControllerReference(cr, obj, r.Scheme)).To(Succeed())
+
+		return obj
+	}
+
+	It("should keep the DaemonSet matching the policy", func() {
+		daemonSets := []apps.DaemonSet{
+			{ObjectMeta: metav1.ObjectMeta{Name: "policy-copy", Namespace: ns}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: ns}},
+		}
+
+		desired := r.desiredChildren(cr).daemonSet
+
+		current, stale := partitionDaemonSets(daemonSets, cr, desired)
+		Expect(current).NotTo(BeNil())
+		Expect(current.Name).To(Equal("policy"))
+		Expect(stale).To(HaveLen(1))
+		Expect(stale[0].Name).To(Equal("policy-copy"))
+
+		By("changing the configuration type")
+		cr.Spec.ConfigurationType = "host-nic"
+
+		current, stale = partitionDaemonSets(daemonSets, cr, desired)
+		Expect(current).To(BeNil())
+		Expect(stale).To(HaveLen(2))
+
+		daemonSets[1].Labels = map[string]string{configurationTypeLabel: "host-nic"}
+
+		current, stale = partitionDaemonSets(daemonSets, cr, desired)
+		Expect(current).NotTo(BeNil())
+		Expect(stale).To(HaveLen(1))
+	})
+
+	It("should delete the collateral that is no longer desired", func() {
+		ctx := context.Background()
+
+		objs := []client.Object{
+			owned(&v1.ServiceAccount{}, "policy-sa", ns),
+			owned(&v1.ServiceAccount{}, "policy-old-sa", ns),
+			owned(&rbac.Role{}, "policy-sa-role", ns),
+			owned(&rbac.Role{}, ns+"-policy-sa-events", metav1.NamespaceDefault),
+			owned(&rbac.Role{}, "policy-old-sa-role", ns),
+			owned(&rbac.RoleBinding{}, "policy-sa-role-rb", ns),
+			owned(&rbac.RoleBinding{}, ns+"-policy-sa-events-rb", metav1.NamespaceDefault),
+			// OpenShift role binding when not running in OpenShift
+			owned(&rbac.RoleBinding{}, "policy-sa-rb", ns),
+			// not owned by the policy
+			&v1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "other-sa", Namespace: ns}},
+		}
+
+		for _, obj := range objs {
+			Expect(r.Create(ctx, obj)).To(Succeed())
+		}
+
+		Expect(r.deleteStaleCollateral(ctx, ctrl.Log, cr, r.desiredChildren(cr))).To(Succeed())
+
+		for i, obj := range objs {
+			err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+
+			switch obj.GetName() {
+			case "policy-old-sa", "policy-old-sa-role", "policy-sa-rb":
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "object %d: %s", i, obj.GetName())
+			default:
+				Expect(err).NotTo(HaveOccurred(), "object %d: %s", i, obj.GetName())
+			}
+		}
+
+		By("running in OpenShift")
+		r.isOpenShift = true
+
+		rb := owned(&rbac.RoleBinding{}, "policy-sa-rb", ns)
+		Expect(r.Create(ctx, rb)).To(Succeed())
+		Expect(r.deleteStaleCollateral(ctx, ctrl.Log, cr, r.desiredChildren(cr))).To(Succeed())
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(rb), rb)).To(Succeed())
+	})
+
+	It("should keep the metrics auth binding only while the metrics are enabled", func() {
+		ctx := context.Background()
+
+		cr.Spec.GaudiScaleOut.MetricsPort = 9600
+
+		desired := r.desiredChildren(cr)
+		Expect(desired.clusterRoleBindings).To(HaveLen(1))
+
+		r.reconcileLinkDiscoveryMetricsRBAC(ctx, ctrl.Log, cr, desired)
+
+		crb := &rbac.ClusterRoleBinding{}
+		key := client.ObjectKey{Name: ns + "-policy-sa-metrics-auth"}
+
+		Expect(r.Get(ctx, key, crb)).To(Succeed())
+		Expect(crb.Subjects).To(HaveLen(1))
+		Expect(crb.Subjects[0].Name).To(Equal("policy-sa"))
+
+		Expect(r.deleteStaleCollateral(ctx, ctrl.Log, cr, desired)).To(Succeed())
+		Expect(r.Get(ctx, key, crb)).To(Succeed())
+
+		By("disabling the metrics")
+		cr.Spec.GaudiScaleOut.MetricsPort = 0
+
+		desired = r.desiredChildren(cr)
+		Expect(desired.clusterRoleBindings).To(BeEmpty())
+
+		Expect(r.deleteStaleCollateral(ctx, ctrl.Log, cr, desired)).To(Succeed())
+		Expect(apierrors.IsNotFound(r.Get(ctx, key, crb))).To(BeTrue())
+	})
+
+	It("should label the node states", func() {
+		ctx := context.Background()
+
+		for _, nodeName := range []string{"node-a", "node-b"} {
+			Expect(r.Create(ctx, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}})).To(Succeed())
+		}
+
+		// created before the states were labelled
+		old := newNetworkNodeState(cr, "node-a", ns)
+		old.Name = "policy-node-a"
+		old.Labels = nil
+		Expect(ctrl.SetControllerReference(cr, old, r.Scheme)).To(Succeed())
+		Expect(r.Create(ctx, old)).To(Succeed())
+
+		states, err := r.reconcileNodeStates(ctx, cr, ctrl.Log)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(states).To(HaveLen(2))
+
+		var labelled networkv1alpha1.NetworkNodeStateList
+		Expect(r.List(ctx, &labelled, client.InNamespace(ns),
+			client.MatchingLabels(networkv1alpha1.NetworkNodeStateLabels(cr.Name, "node-a")))).To(Succeed())
+		Expect(labelled.Items).To(HaveLen(1))
+		Expect(labelled.Items[0].Name).To(Equal("policy-node-a"))
+
+		Expect(r.List(ctx, &labelled, client.InNamespace(ns),
+			client.MatchingLabels(networkv1alpha1.NetworkNodeStateLabels(cr.Name, "node-b")))).To(Succeed())
+		Expect(labelled.Items).To(HaveLen(1))
+		Expect(labelled.Items[0].Name).To(Equal(networkv1alpha1.NetworkNodeStateName(cr.Name, "node-b")))
+	})
+})
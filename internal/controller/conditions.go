@@ -0,0 +1,153 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+
+	apps "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+const (
+	reasonNoTargets         = "NoTargets"
+	reasonNodesConfigured   = "NodesConfigured"
+	reasonNodesNotReady     = "NodesNotReady"
+	reasonNodesFailed       = "NodesFailed"
+	reasonNodesPending      = "NodesPending"
+	reasonRolloutInProgress = "RolloutInProgress"
+	reasonRolloutComplete   = "RolloutComplete"
+	reasonNodeErrors        = "NodeErrors"
+	reasonPeersUnreachable  = "PeersUnreachable"
+	reasonNoErrors          = "NoErrors"
+)
+
+// failedNodes returns the number of nodes that reported a failed configuration.
+func failedNodes(states []networkv1alpha1.NetworkNodeState) int {
+	failed := 0
+
+	for _, state := range states {
+		if state.Status.State == networkv1alpha1.NodeStateFailed {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// configuredNodes returns the number of nodes that reported a completed configuration.
+func configuredNodes(states []networkv1alpha1.NetworkNodeState) int {
+	configured := 0
+
+	for _, state := range states {
+		if state.Status.State == networkv1alpha1.NodeStateConfigured {
+			configured++
+		}
+	}
+
+	return configured
+}
+
+// rolloutComplete checks that the DaemonSet controller has processed the
+// current DaemonSet and all scheduled Pods run the current template.
+func rolloutComplete(ds *apps.DaemonSet) bool {
+	return ds.Status.ObservedGeneration >= ds.Generation &&
+		ds.Status.UpdatedNumberScheduled >= ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberAvailable >= ds.Status.DesiredNumberScheduled
+}
+
+func availableCondition(ds *apps.DaemonSet, states []networkv1alpha1.NetworkNodeState) metav1.Condition {
+	desired := ds.Status.DesiredNumberScheduled
+
+	switch failed := failedNodes(states); {
+	case desired == 0:
+		return metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonNoTargets,
+			Message: "No nodes match the node selector"}
+	case ds.Status.NumberReady < desired:
+		return metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonNodesNotReady,
+			Message: fmt.Sprintf("%d of %d nodes are ready", ds.Status.NumberReady, desired)}
+	case failed > 0:
+		return metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonNodesFailed,
+			Message: fmt.Sprintf("%d of %d nodes failed to configure", failed, desired)}
+	// nodes that are still waiting for their addresses or have not reported
+	// yet are not configured
+	case len(states) != int(desired) || configuredNodes(states) != int(desired):
+		return metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonNodesPending,
+			Message: fmt.Sprintf("%d of %d nodes are configured", configuredNodes(states), desired)}
+	default:
+		return metav1.Condition{Status: metav1.ConditionTrue, Reason: reasonNodesConfigured,
+			Message: fmt.Sprintf("%d of %d nodes are ready", ds.Status.NumberReady, desired)}
+	}
+}
+
+func progressingCondition(ds *apps.DaemonSet) metav1.Condition {
+	if !rolloutComplete(ds) {
+		return metav1.Condition{Status: metav1.ConditionTrue, Reason: reasonRolloutInProgress,
+			Message: fmt.Sprintf("%d of %d nodes run the current configuration",
+				ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled)}
+	}
+
+	return metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonRolloutComplete,
+		Message: "All nodes run the current configuration"}
+}
+
+func degradedCondition(states []networkv1alpha1.NetworkNodeState) metav1.Condition {
+	errorNodes, degradedNodes := nodeStateCounts(states)
+
+	switch {
+	case errorNodes > 0:
+		return metav1.Condition{Status: metav1.ConditionTrue, Reason: reasonNodeErrors,
+			Message: fmt.Sprintf("%d nodes report errors", errorNodes)}
+	case degradedNodes > 0:
+		return metav1.Condition{Status: metav1.ConditionTrue, Reason: reasonPeersUnreachable,
+			Message: fmt.Sprintf("%d nodes have unreachable LLDP peers", degradedNodes)}
+	default:
+		return metav1.Condition{Status: metav1.ConditionFalse, Reason: reasonNoErrors,
+			Message: "No errors reported"}
+	}
+}
+
+// setPolicyConditions updates the conditions and the observed generation of
+// the policy and returns true when any of them changed.
+func setPolicyConditions(nc *networkv1alpha1.NetworkClusterPolicy, ds *apps.DaemonSet, states []networkv1alpha1.NetworkNodeState) bool {
+	changed := false
+
+	conditions := map[string]metav1.Condition{
+		networkv1alpha1.ConditionAvailable:   availableCondition(ds, states),
+		networkv1alpha1.ConditionProgressing: progressingCondition(ds),
+		networkv1alpha1.ConditionDegraded:    degradedCondition(states),
+	}
+
+	for _, conditionType := range []string{networkv1alpha1.ConditionAvailable,
+		networkv1alpha1.ConditionProgressing, networkv1alpha1.ConditionDegraded} {
+		condition := conditions[conditionType]
+		condition.Type = conditionType
+		condition.ObservedGeneration = nc.Generation
+
+		if meta.SetStatusCondition(&nc.Status.Conditions, condition) {
+			changed = true
+		}
+	}
+
+	if nc.Status.ObservedGeneration != nc.Generation {
+		nc.Status.ObservedGeneration = nc.Generation
+		changed = true
+	}
+
+	return changed
+}
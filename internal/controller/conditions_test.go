@@ -0,0 +1,138 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apps "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+var _ = Describe("NetworkClusterPolicy conditions", func() {
+	var (
+		nc *networkv1alpha1.NetworkClusterPolicy
+		ds *apps.DaemonSet
+	)
+
+	BeforeEach(func() {
+		nc = &networkv1alpha1.NetworkClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy", Generation: 2},
+		}
+		ds = &apps.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Generation: 3},
+			Status: apps.DaemonSetStatus{
+				ObservedGeneration:     3,
+				DesiredNumberScheduled: 2,
+				UpdatedNumberScheduled: 2,
+				NumberAvailable:        2,
+				NumberReady:            2,
+			},
+		}
+	})
+
+	configuredStates := func(n int) []networkv1alpha1.NetworkNodeState {
+		states := make([]networkv1alpha1.NetworkNodeState, n)
+		for i := range states {
+			states[i].Status.State = networkv1alpha1.NodeStateConfigured
+		}
+		return states
+	}
+
+	expectCondition := func(conditionType string, status metav1.ConditionStatus, reason string) {
+		condition := meta.FindStatusCondition(nc.Status.Conditions, conditionType)
+		ExpectWithOffset(1, condition).NotTo(BeNil())
+		ExpectWithOffset(1, condition.Status).To(Equal(status))
+		ExpectWithOffset(1, condition.Reason).To(Equal(reason))
+		ExpectWithOffset(1, condition.ObservedGeneration).To(Equal(nc.Generation))
+	}
+
+	It("should be available when all nodes are configured", func() {
+		states := configuredStates(2)
+
+		Expect(setPolicyConditions(nc, ds, states)).To(BeTrue())
+		Expect(nc.Status.ObservedGeneration).To(BeEquivalentTo(2))
+
+		expectCondition(networkv1alpha1.ConditionAvailable, metav1.ConditionTrue, reasonNodesConfigured)
+		expectCondition(networkv1alpha1.ConditionProgressing, metav1.ConditionFalse, reasonRolloutComplete)
+		expectCondition(networkv1alpha1.ConditionDegraded, metav1.ConditionFalse, reasonNoErrors)
+
+		Expect(setPolicyConditions(nc, ds, states)).To(BeFalse())
+	})
+
+	It("should not be available while nodes are pending", func() {
+		states := configuredStates(2)
+		states[1].Status.State = networkv1alpha1.NodeStatePending
+
+		Expect(setPolicyConditions(nc, ds, states)).To(BeTrue())
+
+		expectCondition(networkv1alpha1.ConditionAvailable, metav1.ConditionFalse, reasonNodesPending)
+	})
+
+	It("should not be available before all nodes report", func() {
+		Expect(setPolicyConditions(nc, ds, configuredStates(1))).To(BeTrue())
+
+		expectCondition(networkv1alpha1.ConditionAvailable, metav1.ConditionFalse, reasonNodesPending)
+	})
+
+	It("should be progressing during a rollout", func() {
+		ds.Status.ObservedGeneration = 2
+		ds.Status.UpdatedNumberScheduled = 1
+		ds.Status.NumberReady = 1
+
+		Expect(setPolicyConditions(nc, ds, nil)).To(BeTrue())
+
+		expectCondition(networkv1alpha1.ConditionAvailable, metav1.ConditionFalse, reasonNodesNotReady)
+		expectCondition(networkv1alpha1.ConditionProgressing, metav1.ConditionTrue, reasonRolloutInProgress)
+	})
+
+	It("should be degraded when nodes fail", func() {
+		states := []networkv1alpha1.NetworkNodeState{
+			{Status: networkv1alpha1.NetworkNodeStateStatus{
+				State:     networkv1alpha1.NodeStateFailed,
+				LastError: "no interfaces found",
+			}},
+		}
+
+		Expect(setPolicyConditions(nc, ds, states)).To(BeTrue())
+
+		expectCondition(networkv1alpha1.ConditionAvailable, metav1.ConditionFalse, reasonNodesFailed)
+		expectCondition(networkv1alpha1.ConditionDegraded, metav1.ConditionTrue, reasonNodeErrors)
+	})
+
+	It("should be degraded with unreachable peers", func() {
+		states := configuredStates(2)
+		states[0].Status.Interfaces = []networkv1alpha1.InterfaceState{
+			{Name: "eth0", Reachability: &networkv1alpha1.PeerReachability{}},
+		}
+
+		Expect(setPolicyConditions(nc, ds, states)).To(BeTrue())
+
+		expectCondition(networkv1alpha1.ConditionAvailable, metav1.ConditionTrue, reasonNodesConfigured)
+		expectCondition(networkv1alpha1.ConditionDegraded, metav1.ConditionTrue, reasonPeersUnreachable)
+	})
+
+	It("should not be available without targets", func() {
+		ds.Status = apps.DaemonSetStatus{ObservedGeneration: 3}
+
+		Expect(setPolicyConditions(nc, ds, nil)).To(BeTrue())
+
+		expectCondition(networkv1alpha1.ConditionAvailable, metav1.ConditionFalse, reasonNoTargets)
+		expectCondition(networkv1alpha1.ConditionProgressing, metav1.ConditionFalse, reasonRolloutComplete)
+	})
+})
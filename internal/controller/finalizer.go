@@ -0,0 +1,143 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+const (
+	// nodeCleanupFinalizer keeps a deleted policy until its nodes have
+	// removed the configuration done for it.
+	nodeCleanupFinalizer = "intel.com/node-cleanup"
+
+	// nodeCleanupTimeout limits how long a deleted policy waits for nodes
+	// that never confirm the cleanup, e.g. because they are down.
+	nodeCleanupTimeout      = 10 * time.Minute
+	nodeCleanupPollInterval = 10 * time.Second
+)
+
+// addFinalizer adds the node cleanup finalizer to the policy if missing.
+func (r *NetworkClusterPolicyReconciler) addFinalizer(ctx context.Context, log logr.Logger, cr *networkv1alpha1.NetworkClusterPolicy) error {
+	if !controllerutil.AddFinalizer(cr, nodeCleanupFinalizer) {
+		return nil
+	}
+
+	if err := r.Update(ctx, cr); err != nil {
+		log.Error(err, "unable to add finalizer")
+
+		return err
+	}
+
+	return nil
+}
+
+// nodeCleanupExpired returns true when the nodes have had enough time to
+// clean up after the policy deletion.
+func nodeCleanupExpired(cr *networkv1alpha1.NetworkClusterPolicy, now time.Time) bool {
+	return now.Sub(cr.DeletionTimestamp.Time) >= nodeCleanupTimeout
+}
+
+// finalizePolicy asks the nodes to tear down the configuration of a deleted
+// policy and releases the policy once all of them have confirmed the cleanup.
+// The DaemonSet is kept running until then as it does the cleanup. Nodes
+// that no longer exist are not waited for.
+func (r *NetworkClusterPolicyReconciler) finalizePolicy(ctx context.Context, log logr.Logger, cr *networkv1alpha1.NetworkClusterPolicy) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(cr, nodeCleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var nodes v1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		log.Error(err, "unable to list nodes")
+
+		return ctrl.Result{}, err
+	}
+
+	nodeNames := make(map[string]bool, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeNames[node.Name] = true
+	}
+
+	var states networkv1alpha1.NetworkNodeStateList
+	if err := r.List(ctx, &states, client.InNamespace(r.Namespace), client.MatchingFields{ownerKey: cr.Name}); err != nil {
+		log.Error(err, "unable to list child NetworkNodeStates")
+
+		return ctrl.Result{}, err
+	}
+
+	pending := []string{}
+
+	for i := range states.Items {
+		state := &states.Items[i]
+
+		if !nodeNames[state.Spec.NodeName] {
+			continue
+		}
+
+		if !state.Spec.Teardown {
+			state.Spec.Teardown = true
+
+			if err := r.Update(ctx, state); err != nil {
+				log.Error(err, "unable to request node teardown", "name", state.Name)
+
+				return ctrl.Result{}, err
+			}
+
+			log.Info("Node teardown requested", "name", state.Name)
+		}
+
+		if state.Status.State != networkv1alpha1.NodeStateCleanedUp {
+			pending = append(pending, state.Spec.NodeName)
+		}
+	}
+
+	sort.Strings(pending)
+
+	if len(pending) > 0 {
+		if !nodeCleanupExpired(cr, time.Now()) {
+			log.Info("Waiting for nodes to clean up", "nodes", pending)
+
+			return ctrl.Result{RequeueAfter: nodeCleanupPollInterval}, nil
+		}
+
+		log.Info("Node cleanup timed out", "nodes", pending)
+		r.recordEvent(cr, v1.EventTypeWarning, eventReasonNodeCleanupTimeout,
+			"Nodes did not confirm the cleanup within %v: %s", nodeCleanupTimeout, strings.Join(pending, ", "))
+	} else {
+		r.recordEvent(cr, v1.EventTypeNormal, eventReasonNodesCleanedUp, "Configuration removed from the nodes")
+	}
+
+	controllerutil.RemoveFinalizer(cr, nodeCleanupFinalizer)
+
+	if err := r.Update(ctx, cr); err != nil {
+		log.Error(err, "unable to remove finalizer")
+
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
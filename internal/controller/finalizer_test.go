@@ -0,0 +1,137 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+var _ = Describe("NetworkClusterPolicy finalizer", func() {
+	const ns = "intel-network-operator"
+
+	var (
+		ctx context.Context
+		cr  *networkv1alpha1.NetworkClusterPolicy
+		r   *NetworkClusterPolicyReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(networkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		cr = &networkv1alpha1.NetworkClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "policy",
+				UID:  "policy-uid",
+			},
+			Spec: networkv1alpha1.NetworkClusterPolicySpec{
+				ConfigurationType: "gaudi-so",
+			},
+		}
+
+		r = &NetworkClusterPolicyReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(s).
+				WithStatusSubresource(&networkv1alpha1.NetworkNodeState{}).
+				WithIndex(&networkv1alpha1.NetworkNodeState{}, ownerKey,
+					ownerIndexFunc(networkv1alpha1.GroupVersion.String(), "NetworkClusterPolicy")).
+				Build(),
+			Scheme:    s,
+			Namespace: ns,
+		}
+
+		Expect(r.Create(ctx, cr)).To(Succeed())
+		Expect(r.addFinalizer(ctx, ctrl.Log, cr)).To(Succeed())
+		Expect(cr.Finalizers).To(ContainElement(nodeCleanupFinalizer))
+
+		for _, nodeName := range []string{"node-a", "node-b"} {
+			Expect(r.Create(ctx, &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}})).To(Succeed())
+		}
+
+		// node-c has been removed from the cluster
+		for _, nodeName := range []string{"node-a", "node-b", "node-c"} {
+			state := newNetworkNodeState(cr, nodeName, ns)
+			Expect(ctrl.SetControllerReference(cr, state, r.Scheme)).To(Succeed())
+			Expect(r.Create(ctx, state)).To(Succeed())
+		}
+
+		Expect(r.Delete(ctx, cr)).To(Succeed())
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+		Expect(cr.DeletionTimestamp).NotTo(BeNil())
+	})
+
+	getState := func(nodeName string) *networkv1alpha1.NetworkNodeState {
+		state := &networkv1alpha1.NetworkNodeState{}
+		key := client.ObjectKey{Name: networkv1alpha1.NetworkNodeStateName(cr.Name, nodeName), Namespace: ns}
+		Expect(r.Get(ctx, key, state)).To(Succeed())
+
+		return state
+	}
+
+	It("should release the policy once the nodes are cleaned up", func() {
+		result, err := r.finalizePolicy(ctx, ctrl.Log, cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(nodeCleanupPollInterval))
+
+		Expect(getState("node-a").Spec.Teardown).To(BeTrue())
+		Expect(getState("node-b").Spec.Teardown).To(BeTrue())
+		Expect(getState("node-c").Spec.Teardown).To(BeFalse())
+
+		By("cleaning up one of the nodes")
+		state := getState("node-a")
+		state.Status.State = networkv1alpha1.NodeStateCleanedUp
+		Expect(r.Status().Update(ctx, state)).To(Succeed())
+
+		result, err = r.finalizePolicy(ctx, ctrl.Log, cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(nodeCleanupPollInterval))
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+
+		By("cleaning up the last node")
+		state = getState("node-b")
+		state.Status.State = networkv1alpha1.NodeStateCleanedUp
+		Expect(r.Status().Update(ctx, state)).To(Succeed())
+
+		result, err = r.finalizePolicy(ctx, ctrl.Log, cr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		err = r.Get(ctx, client.ObjectKeyFromObject(cr), cr)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should stop waiting for the nodes after the timeout", func() {
+		deleted := cr.DeletionTimestamp.Time
+
+		Expect(nodeCleanupExpired(cr, deleted.Add(nodeCleanupPollInterval))).To(BeFalse())
+		Expect(nodeCleanupExpired(cr, deleted.Add(nodeCleanupTimeout))).To(BeTrue())
+	})
+})
@@ -0,0 +1,271 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+const (
+	defaultPoolPrefixLen     = 30
+	defaultPoolPrefixLenIPv6 = 127
+)
+
+// addressPool allocates the point-to-point networks of the interfaces from
+// the address pool of a policy.
+type addressPool struct {
+	network   *net.IPNet
+	prefixLen int
+	bits      int
+	// allocated networks in CIDR notation
+	used map[string]bool
+}
+
+func newAddressPool(pool *networkv1alpha1.AddressPool) (*addressPool, error) {
+	_, network, err := net.ParseCIDR(pool.CIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	poolLen, bits := network.Mask.Size()
+
+	prefixLen := pool.PrefixLength
+	if prefixLen == 0 {
+		prefixLen = defaultPoolPrefixLen
+		if bits == 128 {
+			prefixLen = defaultPoolPrefixLenIPv6
+		}
+	}
+
+	if prefixLen < poolLen || prefixLen > bits {
+		return nil, fmt.Errorf("prefix length %d doesn't fit in %s", prefixLen, network)
+	}
+
+	return &addressPool{
+		network:   network,
+		prefixLen: prefixLen,
+		bits:      bits,
+		used:      map[string]bool{},
+	}, nil
+}
+
+// nthNetwork returns the nth point-to-point network of the pool, or nil if
+// the pool has no more networks.
+func (p *addressPool) nthNetwork(n int) *net.IPNet {
+	poolLen, _ := p.network.Mask.Size()
+
+	offset := new(big.Int).Lsh(big.NewInt(int64(n)), uint(p.bits-p.prefixLen))
+	if offset.BitLen() > p.bits-poolLen {
+		return nil
+	}
+
+	base := new(big.Int).SetBytes(p.network.IP)
+	ip := base.Add(base, offset).FillBytes(make([]byte, len(p.network.IP)))
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(p.prefixLen, p.bits)}
+}
+
+// hostAddress returns the address at the offset in the network.
+func hostAddress(network *net.IPNet, offset int64) net.IP {
+	addr := new(big.Int).SetBytes(network.IP)
+
+	return addr.Add(addr, big.NewInt(offset)).FillBytes(make([]byte, len(network.IP)))
+}
+
+// addressAllocation returns the allocation of the interface in the network.
+// The switch port uses the first address of the network, the interface the
+// second one. A /127 has only two addresses and the network address is used
+// by the switch port.
+func (p *addressPool) addressAllocation(ifname string, network *net.IPNet) networkv1alpha1.AddressAllocation {
+	gateway := int64(1)
+	if p.bits-p.prefixLen == 1 {
+		gateway = 0
+	}
+
+	return networkv1alpha1.AddressAllocation{
+		Interface: ifname,
+		Address:   (&net.IPNet{IP: hostAddress(network, gateway+1), Mask: network.Mask}).String(),
+		Gateway:   hostAddress(network, gateway).String(),
+	}
+}
+
+// reserve marks the network of an existing allocation used and returns false
+// if the allocation doesn't belong to the pool or its network is used already.
+func (p *addressPool) reserve(allocation networkv1alpha1.AddressAllocation) bool {
+	ip, network, err := net.ParseCIDR(allocation.Address)
+	if err != nil || !p.network.Contains(ip) {
+		return false
+	}
+
+	if prefixLen, _ := network.Mask.Size(); prefixLen != p.prefixLen {
+		return false
+	}
+
+	if p.used[network.String()] {
+		return false
+	}
+
+	p.used[network.String()] = true
+
+	return true
+}
+
+// allocate returns the allocation of the interface in the first free
+// network of the pool.
+func (p *addressPool) allocate(ifname string) (networkv1alpha1.AddressAllocation, bool) {
+	for n := 0; ; n++ {
+		network := p.nthNetwork(n)
+		if network == nil {
+			return networkv1alpha1.AddressAllocation{}, false
+		}
+
+		if p.used[network.String()] {
+			continue
+		}
+
+		p.used[network.String()] = true
+
+		return p.addressAllocation(ifname, network), true
+	}
+}
+
+// staleAllocation returns true for an allocation of an interface the node
+// no longer reports. The allocations of nodes that haven't reported yet are
+// kept.
+func staleAllocation(state *networkv1alpha1.NetworkNodeState, allocation networkv1alpha1.AddressAllocation) bool {
+	if state.Status.LastUpdate.IsZero() {
+		return false
+	}
+
+	return !slices.ContainsFunc(state.Status.Interfaces, func(iface networkv1alpha1.InterfaceState) bool {
+		return iface.Name == allocation.Interface
+	})
+}
+
+// recordPoolWarning records a warning event about the address pool of the
+// policy, unless it's the same warning that was recorded last.
+func (r *NetworkClusterPolicyReconciler) recordPoolWarning(cr *networkv1alpha1.NetworkClusterPolicy, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	warning := reason + ": " + message
+
+	if last, ok := r.poolWarnings.Swap(cr.Name, warning); ok && last == warning {
+		return
+	}
+
+	r.recordEvent(cr, v1.EventTypeWarning, reason, "%s", message)
+}
+
+// allocateAddresses allocates addresses from the address pool of the policy
+// for the interfaces the nodes have reported. Existing allocations are kept
+// as long as they belong to the pool and the node reports the interface, so
+// that the interfaces keep their addresses across restarts.
+func (r *NetworkClusterPolicyReconciler) allocateAddresses(ctx context.Context, log logr.Logger, cr *networkv1alpha1.NetworkClusterPolicy, states []networkv1alpha1.NetworkNodeState) error {
+	var pool *addressPool
+
+	if cr.Spec.ConfigurationType == gaudiScaleOutSelection && cr.Spec.GaudiScaleOut.AddressPool != nil {
+		var err error
+
+		if pool, err = newAddressPool(cr.Spec.GaudiScaleOut.AddressPool); err != nil {
+			log.Error(err, "invalid address pool")
+			r.recordPoolWarning(cr, eventReasonInvalidAddressPool,
+				"Invalid address pool %s: %v", cr.Spec.GaudiScaleOut.AddressPool.CIDR, err)
+
+			return nil
+		}
+	}
+
+	kept := make([][]networkv1alpha1.AddressAllocation, len(states))
+
+	for i := range states {
+		kept[i] = []networkv1alpha1.AddressAllocation{}
+
+		if pool == nil {
+			continue
+		}
+
+		for _, allocation := range states[i].Spec.Addresses {
+			if staleAllocation(&states[i], allocation) {
+				continue
+			}
+
+			if pool.reserve(allocation) {
+				kept[i] = append(kept[i], allocation)
+			}
+		}
+	}
+
+	exhausted := []string{}
+
+	for i := range states {
+		state := &states[i]
+		allocations := kept[i]
+
+		if pool != nil {
+			for _, iface := range state.Status.Interfaces {
+				if slices.ContainsFunc(allocations, func(a networkv1alpha1.AddressAllocation) bool {
+					return a.Interface == iface.Name
+				}) {
+					continue
+				}
+
+				allocation, ok := pool.allocate(iface.Name)
+				if !ok {
+					exhausted = append(exhausted, state.Spec.NodeName+"/"+iface.Name)
+
+					continue
+				}
+
+				allocations = append(allocations, allocation)
+			}
+		}
+
+		if len(allocations) == 0 {
+			allocations = nil
+		}
+
+		if slices.Equal(allocations, state.Spec.Addresses) {
+			continue
+		}
+
+		state.Spec.Addresses = allocations
+
+		if err := r.Update(ctx, state); err != nil {
+			log.Error(err, "unable to update address allocations", "name", state.Name)
+
+			return err
+		}
+
+		log.Info("Addresses allocated", "name", state.Name, "addresses", len(allocations))
+	}
+
+	if len(exhausted) > 0 {
+		log.Info("Address pool exhausted", "interfaces", exhausted)
+		r.recordPoolWarning(cr, eventReasonAddressPoolExhausted,
+			"No addresses left in %s for %d interfaces", pool.network, len(exhausted))
+	} else {
+		r.poolWarnings.Delete(cr.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+var _ = Describe("NetworkClusterPolicy address pool", func() {
+	const ns = "intel-network-operator"
+
+	var (
+		ctx      context.Context
+		cr       *networkv1alpha1.NetworkClusterPolicy
+		r        *NetworkClusterPolicyReconciler
+		recorder *record.FakeRecorder
+		states   []networkv1alpha1.NetworkNodeState
+	)
+
+	interfaces := func(names ...string) []networkv1alpha1.InterfaceState {
+		ifaces := []networkv1alpha1.InterfaceState{}
+		for _, name := range names {
+			ifaces = append(ifaces, networkv1alpha1.InterfaceState{Name: name})
+		}
+
+		return ifaces
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(networkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		cr = &networkv1alpha1.NetworkClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "policy",
+				UID:  "policy-uid",
+			},
+			Spec: networkv1alpha1.NetworkClusterPolicySpec{
+				ConfigurationType: "gaudi-so",
+				GaudiScaleOut: networkv1alpha1.GaudiScaleOutSpec{
+					LinkDiscoverySpec: networkv1alpha1.LinkDiscoverySpec{
+						Layer: "L3",
+					},
+					AddressPool: &networkv1alpha1.AddressPool{
+						CIDR: "10.210.0.0/28",
+					},
+				},
+			},
+		}
+
+		recorder = record.NewFakeRecorder(10)
+
+		r = &NetworkClusterPolicyReconciler{
+			Client:    fake.NewClientBuilder().WithScheme(s).Build(),
+			Scheme:    s,
+			Recorder:  recorder,
+			Namespace: ns,
+		}
+
+		states = []networkv1alpha1.NetworkNodeState{}
+
+		for _, nodeName := range []string{"node-a", "node-b"} {
+			state := newNetworkNodeState(cr, nodeName, ns)
+			Expect(ctrl.SetControllerReference(cr, state, r.Scheme)).To(Succeed())
+			Expect(r.Create(ctx, state)).To(Succeed())
+
+			states = append(states, *state)
+		}
+	})
+
+	It("should allocate a point-to-point network for each reported interface", func() {
+		states[0].Status.Interfaces = interfaces("eth0", "eth1")
+		states[1].Status.Interfaces = interfaces("eth0")
+
+		Expect(r.allocateAddresses(ctx, ctrl.Log, cr, states)).To(Succeed())
+
+		Expect(states[0].Spec.Addresses).To(Equal([]networkv1alpha1.AddressAllocation{
+			{Interface: "eth0", Address: "10.210.0.2/30", Gateway: "10.210.0.1"},
+			{Interface: "eth1", Address: "10.210.0.6/30", Gateway: "10.210.0.5"},
+		}))
+		Expect(states[1].Spec.Addresses).To(Equal([]networkv1alpha1.AddressAllocation{
+			{Interface: "eth0", Address: "10.210.0.10/30", Gateway: "10.210.0.9"},
+		}))
+
+		By("keeping the allocations")
+		state := &networkv1alpha1.NetworkNodeState{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(&states[0]), state)).To(Succeed())
+		Expect(state.Spec.Addresses).To(HaveLen(2))
+
+		states[1].Status.Interfaces = interfaces("eth1", "eth0")
+
+		Expect(r.allocateAddresses(ctx, ctrl.Log, cr, states)).To(Succeed())
+		Expect(states[0].Spec.Addresses).To(HaveLen(2))
+		Expect(states[1].Spec.Addresses).To(Equal([]networkv1alpha1.AddressAllocation{
+			{Interface: "eth0", Address: "10.210.0.10/30", Gateway: "10.210.0.9"},
+			{Interface: "eth1", Address: "10.210.0.14/30", Gateway: "10.210.0.13"},
+		}))
+
+		By("running out of addresses")
+		states[1].Status.Interfaces = interfaces("eth0", "eth1", "eth2")
+
+		Expect(r.allocateAddresses(ctx, ctrl.Log, cr, states)).To(Succeed())
+		Expect(states[1].Spec.Addresses).To(HaveLen(2))
+		Expect(recorder.Events).To(Receive(Equal("Warning AddressPoolExhausted No addresses left in 10.210.0.0/28 for 1 interfaces")))
+
+		Expect(r.allocateAddresses(ctx, ctrl.Log, cr, states)).To(Succeed())
+		Expect(recorder.Events).NotTo(Receive())
+
+		By("rejecting an invalid pool")
+		cr.Spec.GaudiScaleOut.AddressPool = &networkv1alpha1.AddressPool{CIDR: "10.210.0.0/28", PrefixLength: 24}
+
+		Expect(r.allocateAddresses(ctx, ctrl.Log, cr, states)).To(Succeed())
+		Expect(states[1].Spec.Addresses).To(HaveLen(2))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning InvalidAddressPool Invalid address pool 10.210.0.0/28")))
+
+		Expect(r.allocateAddresses(ctx, ctrl.Log, cr, states)).To(Succeed())
+		Expect(recorder.Events).NotTo(Receive())
+
+		By("changing the pool")
+		cr.Spec.GaudiScaleOut.AddressPool = &networkv1alpha1.AddressPool{CIDR: "fd00:10::/64"}
+
+		Expect(r.allocateAddresses(ctx, ctrl.Log, cr, states)).To(Succeed())
+		Expect(states[0].Spec.Addresses).To(Equal([]networkv1alpha1.AddressAllocation{
+			{Interface: "eth0", Address: "fd00:10::1/127", Gateway: "fd00:10::"},
+			{Interface: "eth1", Address: "fd00:10::3/127", Gateway: "fd00:10::2"},
+		}))
+
+		By("releasing the allocations of removed interfaces")
+		states[0].Status.LastUpdate = metav1.Now()
+		states[0].Status.Interfaces = interfaces("eth1")
+		states[1].Status.Interfaces = interfaces("eth0", "eth1", "eth2", "eth3")
+
+		Expect(r.allocateAddresses(ctx, ctrl.Log, cr, states)).To(Succeed())
+		Expect(states[0].Spec.Addresses).To(Equal([]networkv1alpha1.AddressAllocation{
+			{Interface: "eth1", Address: "fd00:10::3/127", Gateway: "fd00:10::2"},
+		}))
+		Expect(states[1].Spec.Addresses).To(HaveLen(4))
+		Expect(states[1].Spec.Addresses[3]).To(Equal(
+			networkv1alpha1.AddressAllocation{Interface: "eth3", Address: "fd00:10::1/127", Gateway: "fd00:10::"}))
+
+		By("removing the pool")
+		cr.Spec.GaudiScaleOut.AddressPool = nil
+
+		Expect(r.allocateAddresses(ctx, ctrl.Log, cr, states)).To(Succeed())
+		Expect(states[0].Spec.Addresses).To(BeEmpty())
+		Expect(states[1].Spec.Addresses).To(BeEmpty())
+	})
+})
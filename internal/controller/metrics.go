@@ -0,0 +1,144 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	apps "k8s.io/api/apps/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+const (
+	metricsNamespace = "intel_network_operator"
+
+	reconcileResultSuccess = "success"
+	reconcileResultRequeue = "requeue"
+	reconcileResultError   = "error"
+)
+
+var (
+	policyTargets = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "policy_targets",
+		Help:      "Number of nodes the configuration DaemonSet of the NetworkClusterPolicy is scheduled on.",
+	}, []string{"policy"})
+
+	policyReadyNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "policy_ready_nodes",
+		Help:      "Number of nodes with a ready configuration Pod.",
+	}, []string{"policy"})
+
+	policyErrorNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "policy_error_nodes",
+		Help:      "Number of nodes reporting a node or interface error.",
+	}, []string{"policy"})
+
+	policyDegradedNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "policy_degraded_nodes",
+		Help:      "Number of nodes with at least one interface whose LLDP peer is not reachable.",
+	}, []string{"policy"})
+
+	policyRolloutProgress = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "policy_daemonset_rollout_progress",
+		Help:      "Ratio of targeted nodes running the current configuration Pod template, 1 when the rollout is complete.",
+	}, []string{"policy"})
+
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "policy_reconcile_total",
+		Help:      "Number of NetworkClusterPolicy reconciliations by result.",
+	}, []string{"policy", "result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(policyTargets, policyReadyNodes, policyErrorNodes,
+		policyDegradedNodes, policyRolloutProgress, reconcileTotal)
+}
+
+// nodeStateCounts returns the number of nodes with errors and the number of
+// nodes with unreachable LLDP peers.
+func nodeStateCounts(states []networkv1alpha1.NetworkNodeState) (int, int) {
+	errorNodes, degradedNodes := 0, 0
+
+	for _, state := range states {
+		hasError := state.Status.LastError != ""
+		degraded := false
+
+		for _, iface := range state.Status.Interfaces {
+			if iface.Error != "" {
+				hasError = true
+			}
+			if iface.Reachability != nil && !iface.Reachability.Reachable {
+				degraded = true
+			}
+		}
+
+		if hasError {
+			errorNodes++
+		}
+		if degraded {
+			degradedNodes++
+		}
+	}
+
+	return errorNodes, degradedNodes
+}
+
+// rolloutProgress returns the ratio of scheduled nodes running the current
+// Pod template of the DaemonSet.
+func rolloutProgress(ds *apps.DaemonSet) float64 {
+	if ds.Status.DesiredNumberScheduled == 0 {
+		return 1
+	}
+
+	return float64(ds.Status.UpdatedNumberScheduled) / float64(ds.Status.DesiredNumberScheduled)
+}
+
+func updatePolicyMetrics(policy string, ds *apps.DaemonSet, states []networkv1alpha1.NetworkNodeState) {
+	errorNodes, degradedNodes := nodeStateCounts(states)
+
+	policyTargets.WithLabelValues(policy).Set(float64(ds.Status.DesiredNumberScheduled))
+	policyReadyNodes.WithLabelValues(policy).Set(float64(ds.Status.NumberReady))
+	policyErrorNodes.WithLabelValues(policy).Set(float64(errorNodes))
+	policyDegradedNodes.WithLabelValues(policy).Set(float64(degradedNodes))
+	policyRolloutProgress.WithLabelValues(policy).Set(rolloutProgress(ds))
+}
+
+func deletePolicyMetrics(policy string) {
+	policyTargets.DeleteLabelValues(policy)
+	policyReadyNodes.DeleteLabelValues(policy)
+	policyErrorNodes.DeleteLabelValues(policy)
+	policyDegradedNodes.DeleteLabelValues(policy)
+	policyRolloutProgress.DeleteLabelValues(policy)
+	reconcileTotal.DeletePartialMatch(prometheus.Labels{"policy": policy})
+}
+
+func recordReconcileResult(policy string, result ctrl.Result, err error) {
+	switch {
+	case err != nil:
+		reconcileTotal.WithLabelValues(policy, reconcileResultError).Inc()
+	case result.Requeue || result.RequeueAfter > 0:
+		reconcileTotal.WithLabelValues(policy, reconcileResultRequeue).Inc()
+	default:
+		reconcileTotal.WithLabelValues(policy, reconcileResultSuccess).Inc()
+	}
+}
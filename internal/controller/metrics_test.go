@@ -0,0 +1,87 @@
+// Copyright 2025 Intel Corporation. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apps "k8s.io/api/apps/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+)
+
+var _ = Describe("NetworkClusterPolicy metrics", func() {
+	const policy = "metrics-policy"
+
+	AfterEach(func() {
+		deletePolicyMetrics(policy)
+	})
+
+	It("should count nodes with errors and unreachable peers", func() {
+		states := []networkv1alpha1.NetworkNodeState{
+			{Status: networkv1alpha1.NetworkNodeStateStatus{LastError: "no interfaces found"}},
+			{Status: networkv1alpha1.NetworkNodeStateStatus{
+				Interfaces: []networkv1alpha1.InterfaceState{
+					{Name: "eth0", Error: "link is down"},
+					{Name: "eth1", Reachability: &networkv1alpha1.PeerReachability{Reachable: false}},
+				},
+			}},
+			{Status: networkv1alpha1.NetworkNodeStateStatus{
+				Interfaces: []networkv1alpha1.InterfaceState{
+					{Name: "eth0", Reachability: &networkv1alpha1.PeerReachability{Reachable: true}},
+				},
+			}},
+		}
+
+		ds := &apps.DaemonSet{
+			Status: apps.DaemonSetStatus{
+				DesiredNumberScheduled: 4,
+				NumberReady:            3,
+				UpdatedNumberScheduled: 2,
+			},
+		}
+
+		updatePolicyMetrics(policy, ds, states)
+
+		Expect(testutil.ToFloat64(policyTargets.WithLabelValues(policy))).To(BeEquivalentTo(4))
+		Expect(testutil.ToFloat64(policyReadyNodes.WithLabelValues(policy))).To(BeEquivalentTo(3))
+		Expect(testutil.ToFloat64(policyErrorNodes.WithLabelValues(policy))).To(BeEquivalentTo(2))
+		Expect(testutil.ToFloat64(policyDegradedNodes.WithLabelValues(policy))).To(BeEquivalentTo(1))
+		Expect(testutil.ToFloat64(policyRolloutProgress.WithLabelValues(policy))).To(BeEquivalentTo(0.5))
+	})
+
+	It("should report a complete rollout without targets", func() {
+		Expect(rolloutProgress(&apps.DaemonSet{})).To(BeEquivalentTo(1))
+	})
+
+	It("should count reconcile results", func() {
+		recordReconcileResult(policy, ctrl.Result{}, nil)
+		recordReconcileResult(policy, ctrl.Result{Requeue: true}, nil)
+		recordReconcileResult(policy, ctrl.Result{}, fmt.Errorf("failed"))
+		recordReconcileResult(policy, ctrl.Result{}, nil)
+
+		Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(policy, reconcileResultSuccess))).To(BeEquivalentTo(2))
+		Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(policy, reconcileResultRequeue))).To(BeEquivalentTo(1))
+		Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(policy, reconcileResultError))).To(BeEquivalentTo(1))
+
+		deletePolicyMetrics(policy)
+
+		Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(policy, reconcileResultSuccess))).To(BeZero())
+	})
+})
@@ -19,14 +19,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -40,32 +46,77 @@ import (
 //+kubebuilder:rbac:groups=intel.com,resources=networkclusterpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=intel.com,resources=networkclusterpolicies/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=intel.com,resources=networkclusterpolicies/finalizers,verbs=update
+//+kubebuilder:rbac:groups=intel.com,resources=networknodestates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=intel.com,resources=networknodestates/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;create;delete
-//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;create;delete
+//+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;patch
 
 // NetworkClusterPolicyReconciler reconciles a NetworkClusterPolicy object
 type NetworkClusterPolicyReconciler struct {
 	client.Client
 	Scheme      *runtime.Scheme
+	Recorder    record.EventRecorder
 	Namespace   string
 	isOpenShift bool
+	// last address pool warning recorded for each policy
+	poolWarnings sync.Map
 }
 
 const (
 	ownerKey = ".metadata.controller"
 
+	eventReasonDaemonSetCreated          = "DaemonSetCreated"
+	eventReasonDaemonSetUpdated          = "DaemonSetUpdated"
+	eventReasonDaemonSetFailed           = "DaemonSetFailed"
+	eventReasonUnknownConfigurationType  = "UnknownConfigurationType"
+	eventReasonOpenShiftCollateralFailed = "OpenShiftCollateralFailed"
+	eventReasonStaleObjectDeleted        = "StaleObjectDeleted"
+	eventReasonNodesCleanedUp            = "NodesCleanedUp"
+	eventReasonNodeCleanupTimeout        = "NodeCleanupTimeout"
+	eventReasonAddressPoolExhausted      = "AddressPoolExhausted"
+	eventReasonInvalidAddressPool        = "InvalidAddressPool"
+
 	gaudiScaleOutSelection = "gaudi-so"
+	hostNICSelection       = "host-nic"
 
 	layerSelectionL2 = "L2"
 	layerSelectionL3 = "L3"
 
+	backendNetworkManager = "networkmanager"
+
+	metricsPortName = "metrics"
+	// the node's address from the downward API, the metrics are only served on it
+	hostIPEnv = "HOST_IP"
+
 	gaudinetPathHost      = "/etc/habanalabs/gaudinet.json"
 	gaudinetPathContainer = "/host" + gaudinetPathHost
+
+	// state kept on the host: the NetworkManager record and the snapshot of
+	// the interfaces before they were configured
+	hostStateDir = "/var/lib/intel-network-operator"
+
+	nmConfFilePrefix = "99-intel-network-operator-"
 )
 
+// recordEvent records an event for the policy, if a recorder is set.
+func (r *NetworkClusterPolicyReconciler) recordEvent(obj metav1.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+
+	if robj, ok := obj.(runtime.Object); ok {
+		r.Recorder.Eventf(robj, eventType, reason, messageFmt, args...)
+	}
+}
+
 func addHostVolume(ds *apps.DaemonSet, volumeType v1.HostPathType, volumeName, hostPath, containerPath string) {
 	for _, vol := range ds.Spec.Template.Spec.Volumes {
 		if vol.Name == volumeName {
@@ -106,13 +157,7 @@ func addHostVolume(ds *apps.DaemonSet, volumeType v1.HostPathType, volumeName, h
 	}
 }
 
-func (r *NetworkClusterPolicyReconciler) createOpenShiftCollateral(ctx context.Context, log logr.Logger, parent metav1.Object, serviceAccountName string) {
-	if serviceAccountName == "" {
-		return
-	}
-
-	log.Info("Creating OpenShift collateral")
-
+func (r *NetworkClusterPolicyReconciler) createServiceAccount(ctx context.Context, log logr.Logger, parent metav1.Object, serviceAccountName string) {
 	sa := discovery.GaudiLinkDiscoveryServiceAccount()
 	sa.Name = serviceAccountName
 	sa.ObjectMeta.Namespace = r.Namespace
@@ -132,9 +177,159 @@ func (r *NetworkClusterPolicyReconciler) createOpenShiftCollateral(ctx context.C
 	}
 
 	log.Info("Service account created", "name", sa.Name)
+}
+
+func (r *NetworkClusterPolicyReconciler) createLinkDiscoveryRBAC(ctx context.Context, log logr.Logger, parent metav1.Object, serviceAccountName string) {
+	role := discovery.GaudiLinkDiscoveryRole()
+	role.Name = linkDiscoveryRoleName(serviceAccountName)
+	role.ObjectMeta.Namespace = r.Namespace
+
+	if err := ctrl.SetControllerReference(parent, role, r.Scheme); err != nil {
+		log.Error(err, "unable to set controller reference (role)")
+
+		return
+	}
+
+	if err := r.Create(ctx, role); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "unable to create role")
+
+			return
+		}
+	}
+
+	log.Info("Role created", "name", role.Name)
+
+	rb := discovery.GaudiLinkDiscoveryRoleBinding()
+	rb.Name = linkDiscoveryRoleName(serviceAccountName) + "-rb"
+	rb.ObjectMeta.Namespace = r.Namespace
+	rb.RoleRef.Name = role.Name
+	rb.Subjects = []rbac.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      serviceAccountName,
+			Namespace: r.Namespace,
+		},
+	}
+
+	if err := ctrl.SetControllerReference(parent, rb, r.Scheme); err != nil {
+		log.Error(err, "unable to set controller reference (rolebinding)")
+
+		return
+	}
+
+	if err := r.Create(ctx, rb); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "unable to create role binding")
+
+			return
+		}
+	}
+
+	log.Info("Role binding created", "name", rb.Name)
+}
+
+// createLinkDiscoveryEventsRBAC allows the service account to record events
+// for the nodes, node events are recorded in the default namespace.
+func (r *NetworkClusterPolicyReconciler) createLinkDiscoveryEventsRBAC(ctx context.Context, log logr.Logger, parent metav1.Object, serviceAccountName string) {
+	role := discovery.GaudiLinkDiscoveryEventsRole()
+	role.Name = r.eventsRoleName(serviceAccountName)
+	role.ObjectMeta.Namespace = metav1.NamespaceDefault
+
+	if err := ctrl.SetControllerReference(parent, role, r.Scheme); err != nil {
+		log.Error(err, "unable to set controller reference (events role)")
+
+		return
+	}
+
+	if err := r.Create(ctx, role); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "unable to create events role")
+
+			return
+		}
+	}
+
+	log.Info("Role created", "name", role.Name, "namespace", role.Namespace)
+
+	rb := discovery.GaudiLinkDiscoveryRoleBinding()
+	rb.Name = role.Name + "-rb"
+	rb.ObjectMeta.Namespace = metav1.NamespaceDefault
+	rb.RoleRef.Name = role.Name
+	rb.Subjects = []rbac.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      serviceAccountName,
+			Namespace: r.Namespace,
+		},
+	}
+
+	if err := ctrl.SetControllerReference(parent, rb, r.Scheme); err != nil {
+		log.Error(err, "unable to set controller reference (events rolebinding)")
+
+		return
+	}
+
+	if err := r.Create(ctx, rb); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "unable to create events role binding")
+
+			return
+		}
+	}
+
+	log.Info("Role binding created", "name", rb.Name, "namespace", rb.Namespace)
+}
+
+// createLinkDiscoveryMetricsRBAC allows the service account to authenticate
+// and authorize the clients of the metrics endpoint.
+func (r *NetworkClusterPolicyReconciler) createLinkDiscoveryMetricsRBAC(ctx context.Context, log logr.Logger, parent metav1.Object, serviceAccountName string) {
+	crb := discovery.GaudiLinkDiscoveryMetricsAuthBinding()
+	crb.Name = r.metricsAuthBindingName(serviceAccountName)
+	crb.Subjects = []rbac.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      serviceAccountName,
+			Namespace: r.Namespace,
+		},
+	}
+
+	if err := ctrl.SetControllerReference(parent, crb, r.Scheme); err != nil {
+		log.Error(err, "unable to set controller reference (metrics auth clusterrolebinding)")
+
+		return
+	}
+
+	if err := r.Create(ctx, crb); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "unable to create metrics auth cluster role binding")
+
+			return
+		}
+	}
+
+	log.Info("Cluster role binding created", "name", crb.Name)
+}
+
+// reconcileLinkDiscoveryMetricsRBAC creates the metrics auth binding when the
+// metrics were enabled after the DaemonSet was created. The binding is deleted
+// with the stale collateral when the metrics are disabled.
+func (r *NetworkClusterPolicyReconciler) reconcileLinkDiscoveryMetricsRBAC(ctx context.Context, log logr.Logger, cr *networkv1alpha1.NetworkClusterPolicy, desired policyChildren) {
+	for _, name := range desired.clusterRoleBindings {
+		var crb rbac.ClusterRoleBinding
+		if err := r.Get(ctx, name, &crb); !apierrors.IsNotFound(err) {
+			continue
+		}
+
+		r.createLinkDiscoveryMetricsRBAC(ctx, log, cr, desired.serviceAccount.Name)
+	}
+}
+
+func (r *NetworkClusterPolicyReconciler) createOpenShiftCollateral(ctx context.Context, log logr.Logger, parent metav1.Object, serviceAccountName string) {
+	log.Info("Creating OpenShift collateral")
 
 	rb := discovery.OpenShiftRoleBinding()
-	rb.Name = serviceAccountName + "-rb"
+	rb.Name = openShiftRoleBindingName(serviceAccountName)
 	rb.ObjectMeta.Namespace = r.Namespace
 	rb.Subjects = []rbac.Subject{
 		{
@@ -146,6 +341,8 @@ func (r *NetworkClusterPolicyReconciler) createOpenShiftCollateral(ctx context.C
 
 	if err := ctrl.SetControllerReference(parent, rb, r.Scheme); err != nil {
 		log.Error(err, "unable to set controller reference (rolebinding)")
+		r.recordEvent(parent, v1.EventTypeWarning, eventReasonOpenShiftCollateralFailed,
+			"Unable to set controller reference for role binding %s: %v", rb.Name, err)
 
 		return
 	}
@@ -153,6 +350,8 @@ func (r *NetworkClusterPolicyReconciler) createOpenShiftCollateral(ctx context.C
 	if err := r.Create(ctx, rb); err != nil {
 		if !apierrors.IsAlreadyExists(err) {
 			log.Error(err, "unable to create role binding")
+			r.recordEvent(parent, v1.EventTypeWarning, eventReasonOpenShiftCollateralFailed,
+				"Unable to create role binding %s: %v", rb.Name, err)
 
 			return
 		}
@@ -161,7 +360,102 @@ func (r *NetworkClusterPolicyReconciler) createOpenShiftCollateral(ctx context.C
 	log.Info("Role binding created", "name", rb.Name)
 }
 
+// linkDiscoverySettings holds the settings shared by the configuration
+// types that configure interfaces with the link discovery DaemonSet.
+type linkDiscoverySettings struct {
+	image                 string
+	pullPolicy            string
+	layer                 string
+	mtu                   int
+	disableNetworkManager bool
+	networkManagerConfig  string
+	backend               string
+	addressPool           bool
+	routedNetworks        []string
+	lldpAddressParser     string
+	mgmtAddressFallback   bool
+	metricsPort           int32
+	// arguments to select the devices and interfaces, the link discovery defaults to Gaudi NICs
+	deviceArgs []string
+	// write the gaudinet file in L3 mode
+	gaudinet bool
+}
+
+func interfaceFilterArgs(prefix string, filter *networkv1alpha1.InterfaceFilter) []string {
+	args := []string{}
+
+	if len(filter.Drivers) > 0 {
+		args = append(args, fmt.Sprintf("--%s-drivers=%s", prefix, strings.Join(filter.Drivers, ",")))
+	}
+
+	if len(filter.PCIAddresses) > 0 {
+		args = append(args, fmt.Sprintf("--%s-pci-addresses=%s", prefix, strings.Join(filter.PCIAddresses, ",")))
+	}
+
+	// regular expressions may contain commas, pass them one by one
+	for _, name := range filter.Names {
+		args = append(args, fmt.Sprintf("--%s-names=%s", prefix, name))
+	}
+
+	if len(filter.Ports) > 0 {
+		args = append(args, fmt.Sprintf("--%s-ports=%s", prefix, strings.Join(filter.Ports, ",")))
+	}
+
+	return args
+}
+
+func interfaceSelectionArgs(selection *networkv1alpha1.InterfaceSelection) []string {
+	return append(interfaceFilterArgs("include", &selection.Include), interfaceFilterArgs("exclude", &selection.Exclude)...)
+}
+
+// sharedSettings returns the settings of the fields shared by the
+// configuration types.
+func sharedSettings(spec *networkv1alpha1.LinkDiscoverySpec) linkDiscoverySettings {
+	return linkDiscoverySettings{
+		image:                 spec.Image,
+		pullPolicy:            spec.PullPolicy,
+		layer:                 spec.Layer,
+		mtu:                   spec.MTU,
+		disableNetworkManager: spec.DisableNetworkManager,
+		networkManagerConfig:  spec.NetworkManagerConfig,
+		backend:               spec.Backend,
+		routedNetworks:        spec.RoutedNetworks,
+		lldpAddressParser:     spec.LLDPAddressParser,
+		mgmtAddressFallback:   spec.LLDPMgmtAddressFallback,
+		metricsPort:           spec.MetricsPort,
+		deviceArgs:            interfaceSelectionArgs(&spec.InterfaceSelection),
+	}
+}
+
+func gaudiScaleOutSettings(spec *networkv1alpha1.GaudiScaleOutSpec) linkDiscoverySettings {
+	settings := sharedSettings(&spec.LinkDiscoverySpec)
+	settings.addressPool = spec.AddressPool != nil
+	settings.gaudinet = true
+
+	return settings
+}
+
+func hostNICSettings(spec *networkv1alpha1.HostNICSpec) linkDiscoverySettings {
+	deviceArgs := []string{fmt.Sprintf("--driver=%s", spec.Driver)}
+	if len(spec.PCIIDs) > 0 {
+		deviceArgs = append(deviceArgs, fmt.Sprintf("--pci-ids=%s", strings.Join(spec.PCIIDs, ",")))
+	}
+
+	settings := sharedSettings(&spec.LinkDiscoverySpec)
+	settings.deviceArgs = append(deviceArgs, settings.deviceArgs...)
+
+	return settings
+}
+
 func updateGaudiScaleOutDaemonSet(ds *apps.DaemonSet, netconf *networkv1alpha1.NetworkClusterPolicy, namespace string) {
+	updateLinkDiscoveryDaemonSet(ds, netconf, gaudiScaleOutSettings(&netconf.Spec.GaudiScaleOut), namespace)
+}
+
+func updateHostNICDaemonSet(ds *apps.DaemonSet, netconf *networkv1alpha1.NetworkClusterPolicy, namespace string) {
+	updateLinkDiscoveryDaemonSet(ds, netconf, hostNICSettings(&netconf.Spec.HostNIC), namespace)
+}
+
+func updateLinkDiscoveryDaemonSet(ds *apps.DaemonSet, netconf *networkv1alpha1.NetworkClusterPolicy, settings linkDiscoverySettings, namespace string) {
 	ds.Name = netconf.Name
 	ds.ObjectMeta.Namespace = namespace
 
@@ -169,57 +463,147 @@ func updateGaudiScaleOutDaemonSet(ds *apps.DaemonSet, netconf *networkv1alpha1.N
 		ds.Spec.Template.Spec.NodeSelector = netconf.Spec.NodeSelector
 	}
 
-	if len(netconf.Spec.GaudiScaleOut.Image) > 0 {
-		ds.Spec.Template.Spec.Containers[0].Image = netconf.Spec.GaudiScaleOut.Image
+	if len(settings.image) > 0 {
+		ds.Spec.Template.Spec.Containers[0].Image = settings.image
+	}
+
+	if len(settings.pullPolicy) > 0 {
+		ds.Spec.Template.Spec.Containers[0].ImagePullPolicy = v1.PullPolicy(settings.pullPolicy)
 	}
 
 	args := []string{
 		"--configure=true", "--keep-running",
-		fmt.Sprintf("--mode=%s", netconf.Spec.GaudiScaleOut.Layer),
+		fmt.Sprintf("--mode=%s", settings.layer),
 	}
 
+	args = append(args, settings.deviceArgs...)
+
 	// Add log level to the args
 	if netconf.Spec.LogLevel > 0 {
 		args = append(args, fmt.Sprintf("--v=%d", netconf.Spec.LogLevel))
 	}
 
-	if netconf.Spec.GaudiScaleOut.MTU > 0 {
-		args = append(args, fmt.Sprintf("--mtu=%d", netconf.Spec.GaudiScaleOut.MTU))
+	if settings.mtu > 0 {
+		args = append(args, fmt.Sprintf("--mtu=%d", settings.mtu))
 	}
 
-	if netconf.Spec.GaudiScaleOut.DisableNetworkManager {
-		args = append(args, "--disable-networkmanager")
+	args = append(args, fmt.Sprintf("--policy=%s", netconf.Name))
+
+	if settings.disableNetworkManager {
+		// the policies targeting the same node keep their own configuration snippet
+		args = append(args, "--disable-networkmanager",
+			"--networkmanager-conf-file="+nmConfFilePrefix+netconf.Name+".conf")
+		if settings.networkManagerConfig != "" {
+			args = append(args, "--networkmanager-config="+settings.networkManagerConfig)
+		}
 		addHostVolume(ds, v1.HostPathDirectoryOrCreate, "var-run-dbus", "/var/run/dbus", "/var/run/dbus")
 		addHostVolume(ds, v1.HostPathDirectoryOrCreate, "networkmanager", "/etc/NetworkManager", "/etc/NetworkManager")
 	}
 
-	switch netconf.Spec.GaudiScaleOut.Layer {
+	switch settings.layer {
 	case layerSelectionL3:
-		args = append(args, "--wait=90s", fmt.Sprintf("--gaudinet=%s", gaudinetPathContainer))
+		args = append(args, "--wait=90s")
+
+		if settings.gaudinet {
+			args = append(args, fmt.Sprintf("--gaudinet=%s", gaudinetPathContainer))
+		}
+
+		if len(settings.routedNetworks) > 0 {
+			args = append(args, fmt.Sprintf("--routed-networks=%s", strings.Join(settings.routedNetworks, ",")))
+		}
 
-		addHostVolume(ds, v1.HostPathDirectoryOrCreate, "gaudinetpath", filepath.Dir(gaudinetPathHost), filepath.Dir(gaudinetPathContainer))
+		if len(settings.lldpAddressParser) > 0 {
+			args = append(args, fmt.Sprintf("--lldp-address-parser=%s", settings.lldpAddressParser))
+		}
+
+		if settings.mgmtAddressFallback {
+			args = append(args, "--lldp-mgmt-address-fallback")
+		}
+
+		// the operator allocates the addresses, the node reads them from its node state
+		if settings.addressPool {
+			args = append(args, "--address-source=pool")
+		}
+
+		// NetworkManager applies the configuration with connection profiles over D-Bus
+		if settings.backend == backendNetworkManager {
+			args = append(args, "--backend="+settings.backend)
+			addHostVolume(ds, v1.HostPathDirectoryOrCreate, "var-run-dbus", "/var/run/dbus", "/var/run/dbus")
+		}
+
+		if settings.gaudinet {
+			addHostVolume(ds, v1.HostPathDirectoryOrCreate, "gaudinetpath", filepath.Dir(gaudinetPathHost), filepath.Dir(gaudinetPathContainer))
+		}
+	}
+
+	// record of the interfaces to hand back to NetworkManager and of the
+	// original interface state, restored on exit and teardown
+	addHostVolume(ds, v1.HostPathDirectoryOrCreate, "host-state", hostStateDir, hostStateDir)
+
+	// the policies targeting the same node keep their own snapshot and records
+	args = append(args, "--snapshot="+filepath.Join(hostStateDir, "snapshots", netconf.Name+".json"),
+		"--address-record="+filepath.Join(hostStateDir, "addresses", netconf.Name+".json"),
+		"--networkmanager-record="+filepath.Join(hostStateDir, "networkmanager", netconf.Name+".json"))
+
+	// the Pods use the host network, the port is a host port on the node
+	setMetricsPort(ds, settings.metricsPort)
+
+	if settings.metricsPort > 0 {
+		args = append(args, fmt.Sprintf("--metrics-bind-address=$(%s):%d", hostIPEnv, settings.metricsPort), "--metrics-secure")
 	}
 
 	ds.Spec.Template.Spec.Containers[0].Args = args
 }
 
-func (r *NetworkClusterPolicyReconciler) createGaudiScaleOutDaemonset(netconf client.Object, ctx context.Context, log logr.Logger) (ctrl.Result, error) {
-	ds := discovery.GaudiDiscoveryDaemonSet()
+// setMetricsPort sets the metrics port of the container, the port is removed
+// when the metrics are disabled.
+func setMetricsPort(ds *apps.DaemonSet, port int32) {
+	c := &ds.Spec.Template.Spec.Containers[0]
+
+	c.Ports = slices.DeleteFunc(c.Ports, func(p v1.ContainerPort) bool {
+		return p.Name == metricsPortName
+	})
+
+	if port > 0 {
+		c.Ports = append(c.Ports, v1.ContainerPort{
+			Name:          metricsPortName,
+			ContainerPort: port,
+			Protocol:      v1.ProtocolTCP,
+		})
+	}
 
+	if len(c.Ports) == 0 {
+		c.Ports = nil
+	}
+}
+
+func (r *NetworkClusterPolicyReconciler) createGaudiScaleOutDaemonset(netconf client.Object, ctx context.Context, log logr.Logger) (ctrl.Result, error) {
 	cr := netconf.(*networkv1alpha1.NetworkClusterPolicy)
 
 	log.Info("Creating Gaudi Scale-Out DaemonSet", "name", cr.Name)
 
-	saName := ""
-	if r.isOpenShift {
-		saName = cr.Name + "-sa"
-	}
+	return r.createLinkDiscoveryDaemonSet(cr, gaudiScaleOutSettings(&cr.Spec.GaudiScaleOut), ctx, log)
+}
+
+func (r *NetworkClusterPolicyReconciler) createHostNICDaemonSet(netconf client.Object, ctx context.Context, log logr.Logger) (ctrl.Result, error) {
+	cr := netconf.(*networkv1alpha1.NetworkClusterPolicy)
+
+	log.Info("Creating host NIC DaemonSet", "name", cr.Name)
+
+	return r.createLinkDiscoveryDaemonSet(cr, hostNICSettings(&cr.Spec.HostNIC), ctx, log)
+}
+
+func (r *NetworkClusterPolicyReconciler) createLinkDiscoveryDaemonSet(cr *networkv1alpha1.NetworkClusterPolicy, settings linkDiscoverySettings, ctx context.Context, log logr.Logger) (ctrl.Result, error) {
+	ds := discovery.GaudiDiscoveryDaemonSet()
+
+	saName := serviceAccountName(cr)
 
 	ds.Spec.Template.Spec.ServiceAccountName = saName
+	ds.Labels[configurationTypeLabel] = cr.Spec.ConfigurationType
 
-	updateGaudiScaleOutDaemonSet(ds, cr, r.Namespace)
+	updateLinkDiscoveryDaemonSet(ds, cr, settings, r.Namespace)
 
-	if err := ctrl.SetControllerReference(netconf.(metav1.Object), ds, r.Scheme); err != nil {
+	if err := ctrl.SetControllerReference(cr, ds, r.Scheme); err != nil {
 		log.Error(err, "unable to set controller reference")
 
 		return ctrl.Result{}, err
@@ -227,14 +611,24 @@ func (r *NetworkClusterPolicyReconciler) createGaudiScaleOutDaemonset(netconf cl
 
 	if err := r.Create(ctx, ds); err != nil {
 		log.Error(err, "unable to create DaemonSet")
+		r.recordEvent(cr, v1.EventTypeWarning, eventReasonDaemonSetFailed, "Unable to create DaemonSet %s/%s: %v", ds.Namespace, ds.Name, err)
 
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Gaudi scale-out daemonset created")
+	log.Info("Link discovery daemonset created")
+	r.recordEvent(cr, v1.EventTypeNormal, eventReasonDaemonSetCreated, "Created DaemonSet %s/%s", ds.Namespace, ds.Name)
 
-	if saName != "" {
-		r.createOpenShiftCollateral(ctx, log, netconf.(metav1.Object), saName)
+	r.createServiceAccount(ctx, log, cr, saName)
+	r.createLinkDiscoveryRBAC(ctx, log, cr, saName)
+	r.createLinkDiscoveryEventsRBAC(ctx, log, cr, saName)
+
+	if settings.metricsPort > 0 {
+		r.createLinkDiscoveryMetricsRBAC(ctx, log, cr, saName)
+	}
+
+	if r.isOpenShift {
+		r.createOpenShiftCollateral(ctx, log, cr, saName)
 	}
 
 	return ctrl.Result{}, nil
@@ -246,8 +640,12 @@ func (r *NetworkClusterPolicyReconciler) createDaemonSet(ctx context.Context, ne
 	switch cr.Spec.ConfigurationType {
 	case gaudiScaleOutSelection:
 		return r.createGaudiScaleOutDaemonset(netconf, ctx, log)
+	case hostNICSelection:
+		return r.createHostNICDaemonSet(netconf, ctx, log)
 	default:
 		log.Info("Unknown configuration type, this shouldn't happen!", "type", cr.Spec.ConfigurationType)
+		r.recordEvent(cr, v1.EventTypeWarning, eventReasonUnknownConfigurationType,
+			"Unknown configuration type '%s'", cr.Spec.ConfigurationType)
 
 		return ctrl.Result{}, os.ErrInvalid
 	}
@@ -259,12 +657,162 @@ func (r *NetworkClusterPolicyReconciler) updateDaemonSet(ds *apps.DaemonSet, net
 	switch cr.Spec.ConfigurationType {
 	case gaudiScaleOutSelection:
 		updateGaudiScaleOutDaemonSet(ds, cr, r.Namespace)
+	case hostNICSelection:
+		updateHostNICDaemonSet(ds, cr, r.Namespace)
 	default:
 		panic("Unknown configuration type, this shouldn't happen!")
 	}
 }
 
-func (r *NetworkClusterPolicyReconciler) updateStatus(rawObj client.Object, ds *apps.DaemonSet, ctx context.Context, log logr.Logger) (ctrl.Result, error) {
+func newNetworkNodeState(cr *networkv1alpha1.NetworkClusterPolicy, nodeName, namespace string) *networkv1alpha1.NetworkNodeState {
+	return &networkv1alpha1.NetworkNodeState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      networkv1alpha1.NetworkNodeStateName(cr.Name, nodeName),
+			Namespace: namespace,
+			Labels:    networkv1alpha1.NetworkNodeStateLabels(cr.Name, nodeName),
+		},
+		Spec: networkv1alpha1.NetworkNodeStateSpec{
+			NodeName: nodeName,
+			Policy:   cr.Name,
+		},
+	}
+}
+
+// labelNodeState adds the labels the node looks up its state with to a state
+// created before the labels were introduced.
+func (r *NetworkClusterPolicyReconciler) labelNodeState(ctx context.Context, cr *networkv1alpha1.NetworkClusterPolicy, state *networkv1alpha1.NetworkNodeState) error {
+	desired := networkv1alpha1.NetworkNodeStateLabels(cr.Name, state.Spec.NodeName)
+
+	if labels.SelectorFromSet(desired).Matches(labels.Set(state.Labels)) {
+		return nil
+	}
+
+	patch := client.MergeFrom(state.DeepCopy())
+
+	if state.Labels == nil {
+		state.Labels = map[string]string{}
+	}
+
+	for key, value := range desired {
+		state.Labels[key] = value
+	}
+
+	return r.Patch(ctx, state, patch)
+}
+
+// reconcileNodeStates makes sure there's a NetworkNodeState for each node
+// targeted by the policy and returns the current states.
+func (r *NetworkClusterPolicyReconciler) reconcileNodeStates(ctx context.Context, netconf client.Object, log logr.Logger) ([]networkv1alpha1.NetworkNodeState, error) {
+	cr := netconf.(*networkv1alpha1.NetworkClusterPolicy)
+
+	var nodes v1.NodeList
+	if err := r.List(ctx, &nodes, client.MatchingLabels(cr.Spec.NodeSelector)); err != nil {
+		log.Error(err, "unable to list nodes")
+
+		return nil, err
+	}
+
+	var states networkv1alpha1.NetworkNodeStateList
+	if err := r.List(ctx, &states, client.InNamespace(r.Namespace), client.MatchingFields{ownerKey: cr.Name}); err != nil {
+		log.Error(err, "unable to list child NetworkNodeStates")
+
+		return nil, err
+	}
+
+	existing := make(map[string]networkv1alpha1.NetworkNodeState, len(states.Items))
+	for _, state := range states.Items {
+		existing[state.Spec.NodeName] = state
+	}
+
+	current := make([]networkv1alpha1.NetworkNodeState, 0, len(nodes.Items))
+
+	for _, node := range nodes.Items {
+		if state, ok := existing[node.Name]; ok {
+			if err := r.labelNodeState(ctx, cr, &state); err != nil {
+				log.Error(err, "unable to label node state", "name", state.Name)
+
+				return nil, err
+			}
+
+			current = append(current, state)
+			delete(existing, node.Name)
+
+			continue
+		}
+
+		state := newNetworkNodeState(cr, node.Name, r.Namespace)
+
+		if err := ctrl.SetControllerReference(cr, state, r.Scheme); err != nil {
+			log.Error(err, "unable to set controller reference (node state)")
+
+			return nil, err
+		}
+
+		if err := r.Create(ctx, state); err != nil && !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "unable to create node state", "node", node.Name)
+
+			return nil, err
+		}
+
+		log.Info("Node state created", "name", state.Name)
+
+		current = append(current, *state)
+	}
+
+	// Remove states of the nodes no longer targeted by the policy
+	for _, state := range existing {
+		if err := r.Delete(ctx, &state); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to delete node state", "name", state.Name)
+
+			return nil, err
+		}
+
+		log.Info("Node state deleted", "name", state.Name)
+	}
+
+	return current, nil
+}
+
+// nodeStateErrors collects node and interface errors from the node states.
+func nodeStateErrors(states []networkv1alpha1.NetworkNodeState) []string {
+	errors := []string{}
+
+	for _, state := range states {
+		if state.Status.LastError != "" {
+			errors = append(errors, fmt.Sprintf("%s: %s", state.Spec.NodeName, state.Status.LastError))
+		}
+
+		for _, iface := range state.Status.Interfaces {
+			if iface.Error != "" {
+				errors = append(errors, fmt.Sprintf("%s/%s: %s", state.Spec.NodeName, iface.Name, iface.Error))
+			}
+		}
+	}
+
+	sort.Strings(errors)
+
+	return errors
+}
+
+// unreachableInterfaces collects the interfaces whose LLDP peer was not
+// reachable in the last probe.
+func unreachableInterfaces(states []networkv1alpha1.NetworkNodeState) []string {
+	var unreachable []string
+
+	for _, state := range states {
+		for _, iface := range state.Status.Interfaces {
+			if iface.Reachability != nil && !iface.Reachability.Reachable {
+				unreachable = append(unreachable, state.Spec.NodeName+"/"+iface.Name)
+			}
+		}
+	}
+
+	sort.Strings(unreachable)
+
+	return unreachable
+}
+
+func (r *NetworkClusterPolicyReconciler) updateStatus(rawObj client.Object, ds *apps.DaemonSet, states []networkv1alpha1.NetworkNodeState, ctx context.Context, log logr.Logger) (ctrl.Result, error) {
 	nc := rawObj.(*networkv1alpha1.NetworkClusterPolicy)
 
 	updated := false
@@ -279,7 +827,15 @@ func (r *NetworkClusterPolicyReconciler) updateStatus(rawObj client.Object, ds *
 		updated = true
 	}
 
-	nc.Status.Errors = []string{}
+	if errors := nodeStateErrors(states); !slices.Equal(nc.Status.Errors, errors) || nc.Status.Errors == nil {
+		nc.Status.Errors = errors
+		updated = true
+	}
+
+	if unreachable := unreachableInterfaces(states); !slices.Equal(nc.Status.UnreachableInterfaces, unreachable) {
+		nc.Status.UnreachableInterfaces = unreachable
+		updated = true
+	}
 
 	// Update status if there's no State yet.
 	if len(nc.Status.State) == 0 {
@@ -294,6 +850,12 @@ func (r *NetworkClusterPolicyReconciler) updateStatus(rawObj client.Object, ds *
 		nc.Status.State = "All good"
 	}
 
+	if setPolicyConditions(nc, ds, states) {
+		updated = true
+	}
+
+	updatePolicyMetrics(nc.Name, ds, states)
+
 	if updated {
 		if err := r.Status().Update(ctx, nc); apierrors.IsConflict(err) {
 			return ctrl.Result{Requeue: true}, nil
@@ -320,11 +882,33 @@ func (r *NetworkClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl
 	if err := r.Get(ctx, req.NamespacedName, netConfObj); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			log.Error(err, "unable to fetch NetworkClusterPolicies")
+		} else {
+			deletePolicyMetrics(req.Name)
+			r.poolWarnings.Delete(req.Name)
 		}
 
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	cr := netConfObj.(*networkv1alpha1.NetworkClusterPolicy)
+
+	if !cr.DeletionTimestamp.IsZero() {
+		return r.finalizePolicy(ctx, log, cr)
+	}
+
+	if err := r.addFinalizer(ctx, log, cr); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	result, err := r.reconcilePolicy(ctx, req, netConfObj, log)
+
+	recordReconcileResult(req.Name, result, err)
+
+	return result, err
+}
+
+func (r *NetworkClusterPolicyReconciler) reconcilePolicy(ctx context.Context, req ctrl.Request, netConfObj client.Object, log logr.Logger) (ctrl.Result, error) {
+
 	// fetch possible existing daemonset
 
 	var olderDs apps.DaemonSetList
@@ -334,13 +918,34 @@ func (r *NetworkClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl
 		return ctrl.Result{}, err
 	}
 
-	if len(olderDs.Items) == 0 {
+	cr := netConfObj.(*networkv1alpha1.NetworkClusterPolicy)
+	desired := r.desiredChildren(cr)
+
+	ds, staleDs := partitionDaemonSets(olderDs.Items, cr, desired.daemonSet)
+
+	// Remove DaemonSets left behind by a configuration type change or copies
+
+	for _, stale := range staleDs {
+		if err := r.deleteStaleObject(ctx, log, cr, stale, "DaemonSet"); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.deleteStaleCollateral(ctx, log, cr, desired); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if ds == nil {
+		if len(staleDs) > 0 {
+			// wait for the stale DaemonSet to go away before creating the new one
+			return ctrl.Result{Requeue: true}, nil
+		}
+
 		return r.createDaemonSet(ctx, netConfObj, log)
 	}
 
 	// Update DaemonSet
 
-	ds := &olderDs.Items[0]
 	originalDs := ds.DeepCopy()
 
 	r.updateDaemonSet(ds, netConfObj)
@@ -351,35 +956,56 @@ func (r *NetworkClusterPolicyReconciler) Reconcile(ctx context.Context, req ctrl
 
 		if err := r.Update(ctx, ds); err != nil {
 			log.Error(err, "unable to update daemonset", "DaemonSet", ds)
+			r.recordEvent(netConfObj, v1.EventTypeWarning, eventReasonDaemonSetFailed,
+				"Unable to update DaemonSet %s/%s: %v", ds.Namespace, ds.Name, err)
 
 			return ctrl.Result{}, err
 		}
+
+		r.recordEvent(netConfObj, v1.EventTypeNormal, eventReasonDaemonSetUpdated, "Updated DaemonSet %s/%s", ds.Namespace, ds.Name)
+	}
+
+	r.reconcileLinkDiscoveryMetricsRBAC(ctx, log, cr, desired)
+
+	// Update Node States
+
+	states, err := r.reconcileNodeStates(ctx, netConfObj, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.allocateAddresses(ctx, log, cr, states); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// Update Pods Statuses
 
-	return r.updateStatus(netConfObj, ds, ctx, log)
+	return r.updateStatus(netConfObj, ds, states, ctx, log)
 }
 
-func indexDaemonSets(ctx context.Context, mgr ctrl.Manager, apiGVString, pluginKind string) error {
-	return mgr.GetFieldIndexer().IndexField(ctx, &apps.DaemonSet{}, ownerKey,
-		func(rawObj client.Object) []string {
-			// grab the DaemonSet object, extract the owner...
-			ds := rawObj.(*apps.DaemonSet)
-			owner := metav1.GetControllerOf(ds)
+// ownerIndexFunc indexes objects with the name of their controlling policy.
+func ownerIndexFunc(apiGVString, pluginKind string) client.IndexerFunc {
+	return func(rawObj client.Object) []string {
+		// extract the owner...
+		owner := metav1.GetControllerOf(rawObj)
 
-			if owner == nil {
-				return nil
-			}
+		if owner == nil {
+			return nil
+		}
 
-			// make sure it's a network configuration
-			if owner.APIVersion != apiGVString || owner.Kind != pluginKind {
-				return nil
-			}
+		// make sure it's a network configuration
+		if owner.APIVersion != apiGVString || owner.Kind != pluginKind {
+			return nil
+		}
 
-			// and if so, return it.
-			return []string{owner.Name}
-		})
+		// and if so, return it.
+		return []string{owner.Name}
+	}
+}
+
+// indexOwnedObjects indexes the objects of the given type created for the policies.
+func indexOwnedObjects(ctx context.Context, mgr ctrl.Manager, obj client.Object, apiGVString, pluginKind string) error {
+	return mgr.GetFieldIndexer().IndexField(ctx, obj, ownerKey, ownerIndexFunc(apiGVString, pluginKind))
 }
 
 func indexPods(ctx context.Context, mgr ctrl.Manager) error {
@@ -412,9 +1038,18 @@ func (r *NetworkClusterPolicyReconciler) SetupWithManager(mgr ctrl.Manager, isOp
 	apiGVString := networkv1alpha1.GroupVersion.String()
 	kind := "NetworkClusterPolicy"
 
-	// Index DaemonSets (CR).
-	if err := indexDaemonSets(ctx, mgr, apiGVString, kind); err != nil {
-		return err
+	// Index the objects owned by the policies (CR).
+	for _, obj := range []client.Object{
+		&apps.DaemonSet{},
+		&networkv1alpha1.NetworkNodeState{},
+		&v1.ServiceAccount{},
+		&rbac.Role{},
+		&rbac.RoleBinding{},
+		&rbac.ClusterRoleBinding{},
+	} {
+		if err := indexOwnedObjects(ctx, mgr, obj, apiGVString, kind); err != nil {
+			return err
+		}
 	}
 
 	// Index Pods with their owner (DaemonSet).
@@ -425,5 +1060,6 @@ func (r *NetworkClusterPolicyReconciler) SetupWithManager(mgr ctrl.Manager, isOp
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&networkv1alpha1.NetworkClusterPolicy{}).
 		Owns(&apps.DaemonSet{}).
+		Owns(&networkv1alpha1.NetworkNodeState{}).
 		Complete(r)
 }
@@ -23,11 +23,14 @@ import (
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	networkv1alpha1 "github.com/intel/network-operator/api/v1alpha1"
+	discovery "github.com/intel/network-operator/config/discovery"
 )
 
 var _ = Describe("NetworkClusterPolicy Controller", func() {
@@ -56,6 +59,29 @@ var _ = Describe("NetworkClusterPolicy Controller", func() {
 			Name:      resourceName + "-sa-rb",
 			Namespace: defaultNs,
 		}
+		roleTypeNamespacedName := types.NamespacedName{
+			Name:      resourceName + "-sa-role",
+			Namespace: defaultNs,
+		}
+		roleRoleBindingTypeNamespacedName := types.NamespacedName{
+			Name:      resourceName + "-sa-role-rb",
+			Namespace: defaultNs,
+		}
+		eventsRoleTypeNamespacedName := types.NamespacedName{
+			Name:      defaultNs + "-" + resourceName + "-sa-events",
+			Namespace: metav1.NamespaceDefault,
+		}
+		eventsRoleBindingTypeNamespacedName := types.NamespacedName{
+			Name:      defaultNs + "-" + resourceName + "-sa-events-rb",
+			Namespace: metav1.NamespaceDefault,
+		}
+
+		const nodeName = "test-node"
+
+		nodeStateTypeNamespacedName := types.NamespacedName{
+			Name:      resourceName + "-" + nodeName,
+			Namespace: defaultNs,
+		}
 
 		nicpolicy := &networkv1alpha1.NetworkClusterPolicy{}
 
@@ -73,9 +99,11 @@ var _ = Describe("NetworkClusterPolicy Controller", func() {
 				Spec: networkv1alpha1.NetworkClusterPolicySpec{
 					ConfigurationType: "gaudi-so",
 					GaudiScaleOut: networkv1alpha1.GaudiScaleOutSpec{
-						Layer: "L3",
-						Image: "intel/my-linkdiscovery:latest",
-						MTU:   8000,
+						LinkDiscoverySpec: networkv1alpha1.LinkDiscoverySpec{
+							Layer: "L3",
+							Image: "intel/my-linkdiscovery:latest",
+							MTU:   8000,
+						},
 					},
 					NodeSelector: map[string]string{
 						"foo": "bar",
@@ -95,34 +123,50 @@ var _ = Describe("NetworkClusterPolicy Controller", func() {
 			Eventually(func(g Gomega) {
 				g.Expect(k8sClient.Get(ctx, typeNamespacedName, nicpolicy)).To(Succeed())
 				g.Expect(nicpolicy.Spec.ConfigurationType).To(BeEquivalentTo("gaudi-so"))
+				g.Expect(nicpolicy.Finalizers).To(ContainElement(nodeCleanupFinalizer))
 				g.Expect(nicpolicy.Status.Targets).To(BeIdenticalTo(int32(0)))
 				g.Expect(nicpolicy.Status.State).To(BeIdenticalTo("No targets"))
+				g.Expect(nicpolicy.Status.ObservedGeneration).To(Equal(nicpolicy.Generation))
+
+				available := meta.FindStatusCondition(nicpolicy.Status.Conditions, networkv1alpha1.ConditionAvailable)
+				g.Expect(available).NotTo(BeNil())
+				g.Expect(available.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(available.Reason).To(Equal("NoTargets"))
 			}, timeout, interval).Should(Succeed())
 
 			var ds apps.DaemonSet
 			var sa core.ServiceAccount
 			var rb rbac.RoleBinding
+			var role rbac.Role
 
 			Eventually(func(g Gomega) {
 				g.Expect(k8sClient.Get(ctx, typeNamespacedName, &ds)).To(Succeed())
 				g.Expect(ds.ObjectMeta.Name).To(BeEquivalentTo(typeNamespacedName.Name))
 				g.Expect(ds.Spec.Template.Spec.ServiceAccountName).To(BeEquivalentTo(resourceName + "-sa"))
+				g.Expect(ds.Labels).To(HaveKeyWithValue(configurationTypeLabel, "gaudi-so"))
 				g.Expect(ds.Spec.Template.Spec.Containers).To(HaveLen(1))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Image).To(BeEquivalentTo("intel/my-linkdiscovery:latest"))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].Args).To(HaveLen(6))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args).To(HaveLen(10))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[0]).To(BeEquivalentTo("--configure=true"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[1]).To(BeEquivalentTo("--keep-running"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[2]).To(BeEquivalentTo("--mode=L3"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[3]).To(BeEquivalentTo("--mtu=8000"))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[4]).To(BeEquivalentTo("--wait=90s"))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[5]).To(BeEquivalentTo("--gaudinet=/host/etc/habanalabs/gaudinet.json"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[4]).To(BeEquivalentTo("--policy=" + resourceName))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[5]).To(BeEquivalentTo("--wait=90s"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[6]).To(BeEquivalentTo("--gaudinet=/host/etc/habanalabs/gaudinet.json"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[7]).To(BeEquivalentTo("--snapshot=/var/lib/intel-network-operator/snapshots/" + resourceName + ".json"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[8]).To(BeEquivalentTo("--address-record=/var/lib/intel-network-operator/addresses/" + resourceName + ".json"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[9]).To(BeEquivalentTo("--networkmanager-record=/var/lib/intel-network-operator/networkmanager/" + resourceName + ".json"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Ports).To(BeEmpty())
 
-				g.Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(2))
+				g.Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(3))
 				g.Expect(ds.Spec.Template.Spec.Volumes[0].Name).To(BeEquivalentTo("nfd-features"))
 				g.Expect(ds.Spec.Template.Spec.Volumes[1].Name).To(BeEquivalentTo("gaudinetpath"))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts).To(HaveLen(2))
+				g.Expect(ds.Spec.Template.Spec.Volumes[2].Name).To(BeEquivalentTo("host-state"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts).To(HaveLen(3))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[0].Name).To(BeEquivalentTo("nfd-features"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[1].Name).To(BeEquivalentTo("gaudinetpath"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[2].Name).To(BeEquivalentTo("host-state"))
 
 				// Check for service account and role binding
 				g.Expect(k8sClient.Get(ctx, serviceAccountTypeNamespacedName, &sa)).To(Succeed())
@@ -131,11 +175,73 @@ var _ = Describe("NetworkClusterPolicy Controller", func() {
 				g.Expect(rb.Subjects[0].Name).To(BeEquivalentTo(resourceName + "-sa"))
 				g.Expect(rb.Subjects[0].Namespace).To(BeEquivalentTo(defaultNs))
 
+				// Check for node state role and role binding
+				g.Expect(k8sClient.Get(ctx, roleTypeNamespacedName, &role)).To(Succeed())
+				g.Expect(k8sClient.Get(ctx, roleRoleBindingTypeNamespacedName, &rb)).To(Succeed())
+				g.Expect(rb.RoleRef.Name).To(BeEquivalentTo(roleTypeNamespacedName.Name))
+				g.Expect(rb.Subjects).To(HaveLen(1))
+				g.Expect(rb.Subjects[0].Name).To(BeEquivalentTo(resourceName + "-sa"))
+
+				// Check for node events role and role binding
+				g.Expect(k8sClient.Get(ctx, eventsRoleTypeNamespacedName, &role)).To(Succeed())
+				g.Expect(k8sClient.Get(ctx, eventsRoleBindingTypeNamespacedName, &rb)).To(Succeed())
+				g.Expect(rb.RoleRef.Name).To(BeEquivalentTo(eventsRoleTypeNamespacedName.Name))
+				g.Expect(rb.Subjects).To(HaveLen(1))
+				g.Expect(rb.Subjects[0].Namespace).To(BeEquivalentTo(defaultNs))
+
+				// Check for the DaemonSet event
+				var events core.EventList
+				g.Expect(k8sClient.List(ctx, &events, client.InNamespace(metav1.NamespaceDefault))).To(Succeed())
+				g.Expect(events.Items).To(ContainElement(HaveField("Reason", "DaemonSetCreated")))
+
 			}, timeout, interval).Should(Succeed())
 
+			By("creating a node matching the node selector")
+			node := &core.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: nodeName,
+					Labels: map[string]string{
+						"foo": "bar",
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, node)).To(Succeed())
+
+			// Trigger a reconcile
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.LogLevel = 2
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			var state networkv1alpha1.NetworkNodeState
+
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, nodeStateTypeNamespacedName, &state)).To(Succeed())
+				g.Expect(state.Spec.NodeName).To(BeEquivalentTo(nodeName))
+				g.Expect(state.Spec.Policy).To(BeEquivalentTo(resourceName))
+			}, timeout, interval).Should(Succeed())
+
+			By("reporting a node error")
+			state.Status.State = networkv1alpha1.NodeStateFailed
+			state.Status.LastError = "no interfaces found"
+			Expect(k8sClient.Status().Update(ctx, &state)).To(Succeed())
+
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, typeNamespacedName, nicpolicy)).To(Succeed())
+				g.Expect(nicpolicy.Status.Errors).To(ConsistOf(nodeName + ": no interfaces found"))
+
+				degraded := meta.FindStatusCondition(nicpolicy.Status.Conditions, networkv1alpha1.ConditionDegraded)
+				g.Expect(degraded).NotTo(BeNil())
+				g.Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+				g.Expect(degraded.Reason).To(Equal("NodeErrors"))
+			}, timeout, interval).Should(Succeed())
+
+			Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+
 			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
 
 			resource.Spec.GaudiScaleOut.Layer = "L2"
+			resource.Spec.LogLevel = 0
 
 			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
 
@@ -143,17 +249,21 @@ var _ = Describe("NetworkClusterPolicy Controller", func() {
 				g.Expect(k8sClient.Get(ctx, typeNamespacedName, &ds)).To(Succeed())
 				g.Expect(ds.ObjectMeta.Name).To(BeEquivalentTo(typeNamespacedName.Name))
 				g.Expect(ds.Spec.Template.Spec.Containers).To(HaveLen(1))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].Args).To(HaveLen(4))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args).To(HaveLen(8))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[0]).To(BeEquivalentTo("--configure=true"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[1]).To(BeEquivalentTo("--keep-running"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[2]).To(BeEquivalentTo("--mode=L2"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[3]).To(BeEquivalentTo("--mtu=8000"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[4]).To(BeEquivalentTo("--policy=" + resourceName))
 			}, timeout, interval).Should(Succeed())
 
 			// Test NetworkManager disabling
 			resource.Spec.GaudiScaleOut.Layer = "L3"
 			resource.Spec.GaudiScaleOut.DisableNetworkManager = true
 			resource.Spec.GaudiScaleOut.MTU = 0
+			resource.Spec.GaudiScaleOut.RoutedNetworks = []string{"10.192.0.0/12", "/20"}
+			resource.Spec.GaudiScaleOut.LLDPAddressParser = "key-value"
+			resource.Spec.GaudiScaleOut.MetricsPort = 9600
 
 			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
 
@@ -161,22 +271,35 @@ var _ = Describe("NetworkClusterPolicy Controller", func() {
 				g.Expect(k8sClient.Get(ctx, typeNamespacedName, &ds)).To(Succeed())
 				g.Expect(ds.ObjectMeta.Name).To(BeEquivalentTo(typeNamespacedName.Name))
 				g.Expect(ds.Spec.Template.Spec.Containers).To(HaveLen(1))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].Args).To(HaveLen(6))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args).To(HaveLen(15))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[0]).To(BeEquivalentTo("--configure=true"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[1]).To(BeEquivalentTo("--keep-running"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[2]).To(BeEquivalentTo("--mode=L3"))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[3]).To(BeEquivalentTo("--disable-networkmanager"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[3]).To(BeEquivalentTo("--policy=" + resourceName))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[4]).To(BeEquivalentTo("--disable-networkmanager"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[5]).To(BeEquivalentTo("--networkmanager-conf-file=99-intel-network-operator-" + resourceName + ".conf"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[8]).To(BeEquivalentTo("--routed-networks=10.192.0.0/12,/20"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[9]).To(BeEquivalentTo("--lldp-address-parser=key-value"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[10]).To(BeEquivalentTo("--snapshot=/var/lib/intel-network-operator/snapshots/" + resourceName + ".json"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[11]).To(BeEquivalentTo("--address-record=/var/lib/intel-network-operator/addresses/" + resourceName + ".json"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[12]).To(BeEquivalentTo("--networkmanager-record=/var/lib/intel-network-operator/networkmanager/" + resourceName + ".json"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[13]).To(BeEquivalentTo("--metrics-bind-address=$(HOST_IP):9600"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Args[14]).To(BeEquivalentTo("--metrics-secure"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Ports).To(HaveLen(1))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort).To(BeEquivalentTo(9600))
 
-				g.Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(4))
+				g.Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(5))
 				g.Expect(ds.Spec.Template.Spec.Volumes[0].Name).To(BeEquivalentTo("nfd-features"))
 				g.Expect(ds.Spec.Template.Spec.Volumes[1].Name).To(BeEquivalentTo("gaudinetpath"))
-				g.Expect(ds.Spec.Template.Spec.Volumes[2].Name).To(BeEquivalentTo("var-run-dbus"))
-				g.Expect(ds.Spec.Template.Spec.Volumes[3].Name).To(BeEquivalentTo("networkmanager"))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts).To(HaveLen(4))
+				g.Expect(ds.Spec.Template.Spec.Volumes[2].Name).To(BeEquivalentTo("host-state"))
+				g.Expect(ds.Spec.Template.Spec.Volumes[3].Name).To(BeEquivalentTo("var-run-dbus"))
+				g.Expect(ds.Spec.Template.Spec.Volumes[4].Name).To(BeEquivalentTo("networkmanager"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts).To(HaveLen(5))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[0].Name).To(BeEquivalentTo("nfd-features"))
 				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[1].Name).To(BeEquivalentTo("gaudinetpath"))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[2].Name).To(BeEquivalentTo("var-run-dbus"))
-				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[3].Name).To(BeEquivalentTo("networkmanager"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[2].Name).To(BeEquivalentTo("host-state"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[3].Name).To(BeEquivalentTo("var-run-dbus"))
+				g.Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts[4].Name).To(BeEquivalentTo("networkmanager"))
 			}, timeout, interval).Should(Succeed())
 
 			Expect(k8sClient.Delete(ctx, nicpolicy)).To(Succeed())
@@ -191,3 +314,167 @@ var _ = Describe("NetworkClusterPolicy Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("Link discovery DaemonSet", func() {
+	It("should pass the interface selection of Gaudi NICs", func() {
+		spec := networkv1alpha1.GaudiScaleOutSpec{
+			LinkDiscoverySpec: networkv1alpha1.LinkDiscoverySpec{
+				Layer: "L2",
+				InterfaceSelection: networkv1alpha1.InterfaceSelection{
+					Include: networkv1alpha1.InterfaceFilter{
+						Drivers:      []string{"habanalabs"},
+						PCIAddresses: []string{"0000:b3:00.*", "0000:b4:00.*"},
+					},
+					Exclude: networkv1alpha1.InterfaceFilter{
+						Ports: []string{"0"},
+					},
+				},
+			},
+		}
+
+		Expect(gaudiScaleOutSettings(&spec).deviceArgs).To(Equal([]string{
+			"--include-drivers=habanalabs", "--include-pci-addresses=0000:b3:00.*,0000:b4:00.*",
+			"--exclude-ports=0",
+		}))
+	})
+
+	It("should take the addresses of Gaudi NICs from the address pool", func() {
+		nc := &networkv1alpha1.NetworkClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "gaudi-pool",
+			},
+			Spec: networkv1alpha1.NetworkClusterPolicySpec{
+				ConfigurationType: "gaudi-so",
+				GaudiScaleOut: networkv1alpha1.GaudiScaleOutSpec{
+					LinkDiscoverySpec: networkv1alpha1.LinkDiscoverySpec{
+						Layer: "L3",
+					},
+					AddressPool: &networkv1alpha1.AddressPool{
+						CIDR: "10.210.0.0/16",
+					},
+				},
+			},
+		}
+
+		ds := discovery.GaudiDiscoveryDaemonSet()
+		updateGaudiScaleOutDaemonSet(ds, nc, "intel-network-operator")
+
+		Expect(ds.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--address-source=pool"))
+
+		nc.Spec.GaudiScaleOut.Layer = "L2"
+		updateGaudiScaleOutDaemonSet(ds, nc, "intel-network-operator")
+
+		Expect(ds.Spec.Template.Spec.Containers[0].Args).NotTo(ContainElement("--address-source=pool"))
+	})
+
+	It("should select the NICs by driver and PCI IDs without gaudinet", func() {
+		nc := &networkv1alpha1.NetworkClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "host-nic",
+			},
+			Spec: networkv1alpha1.NetworkClusterPolicySpec{
+				ConfigurationType: "host-nic",
+				HostNIC: networkv1alpha1.HostNICSpec{
+					Driver: "ice",
+					PCIIDs: []string{"8086:1593", "8086:159b"},
+					LinkDiscoverySpec: networkv1alpha1.LinkDiscoverySpec{
+						Layer:          "L3",
+						Image:          "intel/my-linkdiscovery:latest",
+						MTU:            9000,
+						RoutedNetworks: []string{"/20"},
+						InterfaceSelection: networkv1alpha1.InterfaceSelection{
+							Exclude: networkv1alpha1.InterfaceFilter{
+								Names: []string{"^ens1f[0-1]$", "^eth{1,2}$"},
+								Ports: []string{"0", "2-3"},
+							},
+						},
+					},
+				},
+				NodeSelector: map[string]string{
+					"foo": "bar",
+				},
+			},
+		}
+
+		ds := discovery.GaudiDiscoveryDaemonSet()
+		updateHostNICDaemonSet(ds, nc, "intel-network-operator")
+
+		Expect(ds.Name).To(Equal("host-nic"))
+		Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(nc.Spec.NodeSelector))
+		Expect(ds.Spec.Template.Spec.Containers[0].Image).To(Equal("intel/my-linkdiscovery:latest"))
+		Expect(ds.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(core.PullIfNotPresent))
+		Expect(ds.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{
+			"--configure=true", "--keep-running", "--mode=L3",
+			"--driver=ice", "--pci-ids=8086:1593,8086:159b",
+			"--exclude-names=^ens1f[0-1]$", "--exclude-names=^eth{1,2}$", "--exclude-ports=0,2-3",
+			"--mtu=9000", "--policy=host-nic", "--wait=90s",
+			"--routed-networks=/20", "--snapshot=/var/lib/intel-network-operator/snapshots/host-nic.json",
+			"--address-record=/var/lib/intel-network-operator/addresses/host-nic.json",
+			"--networkmanager-record=/var/lib/intel-network-operator/networkmanager/host-nic.json",
+		}))
+		Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(2))
+		Expect(ds.Spec.Template.Spec.Volumes[1].Name).To(Equal("host-state"))
+
+		By("changing the pull policy")
+		nc.Spec.HostNIC.PullPolicy = "Always"
+
+		updateHostNICDaemonSet(ds, nc, "intel-network-operator")
+
+		Expect(ds.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(core.PullAlways))
+
+		By("configuring with NetworkManager connection profiles")
+		nc.Spec.HostNIC.Backend = "networkmanager"
+
+		updateHostNICDaemonSet(ds, nc, "intel-network-operator")
+
+		Expect(ds.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--backend=networkmanager"))
+		Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(3))
+		Expect(ds.Spec.Template.Spec.Volumes[2].Name).To(Equal("var-run-dbus"))
+
+		By("falling back to the LLDP management address")
+		nc.Spec.HostNIC.LLDPMgmtAddressFallback = true
+
+		updateHostNICDaemonSet(ds, nc, "intel-network-operator")
+
+		Expect(ds.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--lldp-mgmt-address-fallback"))
+
+		By("selecting the NICs by PCI IDs only")
+		nc.Spec.HostNIC.Backend = ""
+		nc.Spec.HostNIC.Driver = ""
+		nc.Spec.HostNIC.Layer = "L2"
+		nc.Spec.HostNIC.DisableNetworkManager = true
+		nc.Spec.HostNIC.NetworkManagerConfig = "mac"
+
+		updateHostNICDaemonSet(ds, nc, "intel-network-operator")
+
+		Expect(ds.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{
+			"--configure=true", "--keep-running", "--mode=L2",
+			"--driver=", "--pci-ids=8086:1593,8086:159b",
+			"--exclude-names=^ens1f[0-1]$", "--exclude-names=^eth{1,2}$", "--exclude-ports=0,2-3",
+			"--mtu=9000", "--policy=host-nic", "--disable-networkmanager",
+			"--networkmanager-conf-file=99-intel-network-operator-host-nic.conf", "--networkmanager-config=mac", "--snapshot=/var/lib/intel-network-operator/snapshots/host-nic.json",
+			"--address-record=/var/lib/intel-network-operator/addresses/host-nic.json",
+			"--networkmanager-record=/var/lib/intel-network-operator/networkmanager/host-nic.json",
+		}))
+		Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(4))
+		Expect(ds.Spec.Template.Spec.Containers[0].Ports).To(BeEmpty())
+
+		By("enabling the metrics")
+		nc.Spec.HostNIC.MetricsPort = 9601
+
+		updateHostNICDaemonSet(ds, nc, "intel-network-operator")
+
+		Expect(ds.Spec.Template.Spec.Containers[0].Args).To(ContainElements(
+			"--metrics-bind-address=$(HOST_IP):9601", "--metrics-secure"))
+		Expect(ds.Spec.Template.Spec.Containers[0].Ports).To(HaveLen(1))
+		Expect(ds.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort).To(BeEquivalentTo(9601))
+
+		By("disabling the metrics")
+		nc.Spec.HostNIC.MetricsPort = 0
+
+		updateHostNICDaemonSet(ds, nc, "intel-network-operator")
+
+		Expect(ds.Spec.Template.Spec.Containers[0].Args).NotTo(ContainElement("--metrics-secure"))
+		Expect(ds.Spec.Template.Spec.Containers[0].Ports).To(BeEmpty())
+	})
+})
@@ -91,6 +91,7 @@ var _ = BeforeSuite(func() {
 	err = (&NetworkClusterPolicyReconciler{
 		Client:    k8sManager.GetClient(),
 		Scheme:    k8sManager.GetScheme(),
+		Recorder:  k8sManager.GetEventRecorderFor("network-operator"),
 		Namespace: "intel-network-operator",
 	}).SetupWithManager(k8sManager, true)
 	Expect(err).ToNot(HaveOccurred())
@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package networkmanager
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultConfDir is the NetworkManager configuration snippet directory.
+	DefaultConfDir = "/etc/NetworkManager/conf.d"
+	// DefaultConfFile is the name of the configuration snippet. The
+	// policies targeting the same node need their own snippets.
+	DefaultConfFile = "99-intel-network-operator.conf"
+
+	// MatchInterfaceName and MatchMAC select how the unmanaged interfaces
+	// are matched in the NetworkManager configuration.
+	MatchInterfaceName = "interface-name"
+	MatchMAC           = "mac"
+
+	// reload NetworkManager.conf from disk
+	reloadConfFlag uint32 = 0x01
+)
+
+// UnmanagedInterface identifies an interface in the unmanaged-devices setting.
+type UnmanagedInterface struct {
+	Name string
+	MAC  string
+}
+
+func unmanagedConfPath(confDir, confFile string) string {
+	return filepath.Join(confDir, confFile)
+}
+
+func unmanagedDevicesSpec(interfaces []UnmanagedInterface, match string) (string, error) {
+	specs := make([]string, 0, len(interfaces))
+
+	for _, iface := range interfaces {
+		switch match {
+		case MatchInterfaceName:
+			specs = append(specs, MatchInterfaceName+":"+iface.Name)
+		case MatchMAC:
+			if iface.MAC == "" {
+				return "", fmt.Errorf("no MAC address for interface %s", iface.Name)
+			}
+
+			specs = append(specs, MatchMAC+":"+strings.ToLower(iface.MAC))
+		default:
+			return "", fmt.Errorf("unknown interface match '%s'", match)
+		}
+	}
+
+	sort.Strings(specs)
+
+	return strings.Join(specs, ";"), nil
+}
+
+// UnmanagedConfig returns the path and the content of the configuration
+// snippet that WriteUnmanagedConfig writes.
+func UnmanagedConfig(confDir, confFile string, interfaces []UnmanagedInterface, match string) (string, []byte, error) {
+	spec, err := unmanagedDevicesSpec(interfaces, match)
+	if err != nil {
+		return "", nil, err
+	}
+
+	content := []byte("# Generated by the Intel Network Operator, removed when the policy is deleted.\n" +
+		"[keyfile]\n" +
+		"unmanaged-devices=" + spec + "\n")
+
+	return unmanagedConfPath(confDir, confFile), content, nil
+}
+
+// WriteUnmanagedConfig writes a NetworkManager configuration snippet that
+// keeps the interfaces unmanaged across NetworkManager restarts and reboots.
+// The interfaces are matched by interface name or MAC address. Returns true
+// when the configuration changed and NetworkManager needs to reload it.
+func WriteUnmanagedConfig(confDir, confFile string, interfaces []UnmanagedInterface, match string) (bool, error) {
+	path, content, err := UnmanagedConfig(confDir, confFile, interfaces, match)
+	if err != nil {
+		return false, err
+	}
+
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, content) {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(confDir, 0755); err != nil {
+		return false, fmt.Errorf("cannot create NetworkManager configuration directory: %v", err)
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return false, fmt.Errorf("cannot write NetworkManager configuration: %v", err)
+	}
+
+	klog.Infof("Wrote NetworkManager configuration %s", path)
+
+	return true, nil
+}
+
+// RemoveUnmanagedConfig removes the configuration snippet written by
+// WriteUnmanagedConfig. Returns true when NetworkManager needs to reload
+// its configuration.
+func RemoveUnmanagedConfig(confDir, confFile string) (bool, error) {
+	path := unmanagedConfPath(confDir, confFile)
+
+	if err := os.Remove(path); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("cannot remove NetworkManager configuration: %v", err)
+	}
+
+	klog.Infof("Removed NetworkManager configuration %s", path)
+
+	return true, nil
+}
+
+// HasUnmanagedConfig returns true when the configuration snippet exists.
+func HasUnmanagedConfig(confDir, confFile string) bool {
+	_, err := os.Stat(unmanagedConfPath(confDir, confFile))
+
+	return err == nil
+}
+
+// ReloadConfiguration makes NetworkManager reload its configuration files.
+func ReloadConfiguration(nm NetworkManagerIf) error {
+	// Check if NetworkManager is accessible
+	_, err := nm.GetPropertyVersion()
+	if err != nil {
+		klog.Info("Couldn't read NetworkManager version. It's probably not running.")
+
+		return nil
+	}
+
+	if err := nm.Reload(reloadConfFlag); err != nil {
+		return fmt.Errorf("cannot reload NetworkManager configuration: %v", err)
+	}
+
+	klog.Info("Reloaded NetworkManager configuration")
+
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package networkmanager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnmanagedConfig(t *testing.T) {
+	confDir := filepath.Join(t.TempDir(), "conf.d")
+
+	interfaces := []UnmanagedInterface{
+		{Name: "eth1", MAC: "B0:FD:0B:00:00:02"},
+		{Name: "eth0", MAC: "b0:fd:0b:00:00:01"},
+	}
+
+	changed, err := WriteUnmanagedConfig(confDir, DefaultConfFile, interfaces, MatchMAC)
+	if err != nil || !changed {
+		t.Fatalf("cannot write configuration: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(confDir, DefaultConfFile))
+	if err != nil {
+		t.Fatalf("cannot read configuration: %v", err)
+	}
+
+	if !strings.Contains(string(content), "[keyfile]\nunmanaged-devices=mac:b0:fd:0b:00:00:01;mac:b0:fd:0b:00:00:02\n") {
+		t.Errorf("unexpected configuration:\n%s", content)
+	}
+
+	if changed, _ := WriteUnmanagedConfig(confDir, DefaultConfFile, interfaces, MatchMAC); changed {
+		t.Error("unchanged configuration was rewritten")
+	}
+
+	if changed, _ := WriteUnmanagedConfig(confDir, DefaultConfFile, interfaces, MatchInterfaceName); !changed {
+		t.Error("changed configuration was not rewritten")
+	}
+
+	content, _ = os.ReadFile(filepath.Join(confDir, DefaultConfFile))
+	if !strings.Contains(string(content), "unmanaged-devices=interface-name:eth0;interface-name:eth1\n") {
+		t.Errorf("unexpected configuration:\n%s", content)
+	}
+
+	if _, err := WriteUnmanagedConfig(confDir, DefaultConfFile, []UnmanagedInterface{{Name: "eth0"}}, MatchMAC); err == nil {
+		t.Error("interface without MAC accepted")
+	}
+	if _, err := WriteUnmanagedConfig(confDir, DefaultConfFile, interfaces, "pci"); err == nil {
+		t.Error("unknown match accepted")
+	}
+
+	if !HasUnmanagedConfig(confDir, DefaultConfFile) {
+		t.Error("configuration not found")
+	}
+
+	if removed, err := RemoveUnmanagedConfig(confDir, DefaultConfFile); err != nil || !removed {
+		t.Errorf("configuration was not removed: %v", err)
+	}
+
+	if removed, err := RemoveUnmanagedConfig(confDir, DefaultConfFile); err != nil || removed {
+		t.Errorf("missing configuration removed: %v", err)
+	}
+}
+
+func TestUnmanagedConfigPolicies(t *testing.T) {
+	confDir := filepath.Join(t.TempDir(), "conf.d")
+
+	confA := "99-intel-network-operator-policy-a.conf"
+	confB := "99-intel-network-operator-policy-b.conf"
+
+	if _, err := WriteUnmanagedConfig(confDir, confA, []UnmanagedInterface{{Name: "eth0"}}, MatchInterfaceName); err != nil {
+		t.Fatalf("cannot write configuration: %v", err)
+	}
+	if _, err := WriteUnmanagedConfig(confDir, confB, []UnmanagedInterface{{Name: "eth1"}}, MatchInterfaceName); err != nil {
+		t.Fatalf("cannot write configuration: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(confDir, confA))
+	if !strings.Contains(string(content), "unmanaged-devices=interface-name:eth0\n") {
+		t.Errorf("configuration of the first policy was replaced:\n%s", content)
+	}
+
+	if removed, err := RemoveUnmanagedConfig(confDir, confB); err != nil || !removed {
+		t.Errorf("configuration was not removed: %v", err)
+	}
+
+	if !HasUnmanagedConfig(confDir, confA) || HasUnmanagedConfig(confDir, confB) {
+		t.Error("removing the configuration of one policy affected the other")
+	}
+}
+
+func TestReloadConfiguration(t *testing.T) {
+	var reloadFlags uint32
+
+	nm := &MockNetworkManager{
+		mockVersionQuery: func() (string, error) {
+			return "1.0.0", nil
+		},
+		mockReload: func(flags uint32) error {
+			reloadFlags = flags
+			return nil
+		},
+	}
+
+	if err := ReloadConfiguration(nm); err != nil || reloadFlags != reloadConfFlag {
+		t.Errorf("unexpected reload with flags %#x: %v", reloadFlags, err)
+	}
+
+	nm.mockReload = func(flags uint32) error {
+		return os.ErrPermission
+	}
+
+	if err := ReloadConfiguration(nm); err == nil {
+		t.Error("ReloadConfiguration should have failed")
+	}
+
+	nm.mockVersionQuery = func() (string, error) {
+		return "", os.ErrInvalid
+	}
+
+	if err := ReloadConfiguration(nm); err != nil {
+		t.Errorf("ReloadConfiguration should be skipped without NetworkManager: %v", err)
+	}
+}
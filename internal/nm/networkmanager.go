@@ -17,6 +17,7 @@
 package networkmanager
 
 import (
+	"fmt"
 	"slices"
 
 	"github.com/Wifx/gonetworkmanager/v3"
@@ -26,17 +27,36 @@ import (
 type NetworkManagerIf interface {
 	GetPropertyVersion() (string, error)
 	GetAllDevices() ([]DeviceWrapperIf, error)
+	Reload(flags uint32) error
+	ListConnections() ([]ConnectionWrapperIf, error)
+	AddConnection(settings ConnectionSettings) (ConnectionWrapperIf, error)
+	ActivateConnection(connection ConnectionWrapperIf, device DeviceWrapperIf) error
 }
 
 type DeviceWrapperIf interface {
 	GetPropertyInterface() (string, error)
+	GetPropertyManaged() (bool, error)
 	SetPropertyManaged(managed bool) error
 }
 
+// ConnectionSettings holds the settings of a connection profile by setting
+// name, e.g. "ipv4", and property.
+type ConnectionSettings = gonetworkmanager.ConnectionSettings
+
+type ConnectionWrapperIf interface {
+	GetSettings() (ConnectionSettings, error)
+	Update(settings ConnectionSettings) error
+	Delete() error
+}
+
 type DeviceWrapper struct {
 	device gonetworkmanager.Device
 }
 
+type ConnectionWrapper struct {
+	connection gonetworkmanager.Connection
+}
+
 type NetworkManager struct {
 	nm gonetworkmanager.NetworkManager
 }
@@ -54,6 +74,10 @@ func (r *NetworkManager) GetPropertyVersion() (string, error) {
 	return r.nm.GetPropertyVersion()
 }
 
+func (r *NetworkManager) Reload(flags uint32) error {
+	return r.nm.Reload(flags)
+}
+
 func (r *NetworkManager) GetAllDevices() ([]DeviceWrapperIf, error) {
 	devices, err := r.nm.GetAllDevices()
 	if err != nil {
@@ -68,15 +92,189 @@ func (r *NetworkManager) GetAllDevices() ([]DeviceWrapperIf, error) {
 	return wrappedDevices, nil
 }
 
+func (r *NetworkManager) ListConnections() ([]ConnectionWrapperIf, error) {
+	settings, err := gonetworkmanager.NewSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	connections, err := settings.ListConnections()
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedConnections := make([]ConnectionWrapperIf, 0, len(connections))
+	for _, connection := range connections {
+		wrappedConnections = append(wrappedConnections, &ConnectionWrapper{connection: connection})
+	}
+
+	return wrappedConnections, nil
+}
+
+func (r *NetworkManager) AddConnection(connectionSettings ConnectionSettings) (ConnectionWrapperIf, error) {
+	settings, err := gonetworkmanager.NewSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := settings.AddConnection(connectionSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConnectionWrapper{connection: connection}, nil
+}
+
+func (r *NetworkManager) ActivateConnection(connection ConnectionWrapperIf, device DeviceWrapperIf) error {
+	c, ok := connection.(*ConnectionWrapper)
+	if !ok {
+		return fmt.Errorf("unsupported connection type %T", connection)
+	}
+
+	d, ok := device.(*DeviceWrapper)
+	if !ok {
+		return fmt.Errorf("unsupported device type %T", device)
+	}
+
+	_, err := r.nm.ActivateConnection(c.connection, d.device, nil)
+
+	return err
+}
+
+func (c *ConnectionWrapper) GetSettings() (ConnectionSettings, error) {
+	return c.connection.GetSettings()
+}
+
+func (c *ConnectionWrapper) Update(settings ConnectionSettings) error {
+	return c.connection.Update(settings)
+}
+
+func (c *ConnectionWrapper) Delete() error {
+	return c.connection.Delete()
+}
+
 func (d *DeviceWrapper) GetPropertyInterface() (string, error) {
 	return d.device.GetPropertyInterface()
 }
 
+func (d *DeviceWrapper) GetPropertyManaged() (bool, error) {
+	return d.device.GetPropertyManaged()
+}
+
 func (d *DeviceWrapper) SetPropertyManaged(managed bool) error {
 	return d.device.SetPropertyManaged(managed)
 }
 
-func DisableNetworkManagerForInterfaces(nm NetworkManagerIf, interfaces []string) error {
+// managedDevices returns the devices of the interfaces that NetworkManager
+// manages.
+func managedDevices(nm NetworkManagerIf, interfaces []string) ([]DeviceWrapperIf, error) {
+	devices, err := nm.GetAllDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	managedDevices := []DeviceWrapperIf{}
+
+	for _, device := range devices {
+		netif, err := device.GetPropertyInterface()
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(interfaces, netif) {
+			continue
+		}
+
+		managed, err := device.GetPropertyManaged()
+		if err != nil {
+			return nil, err
+		}
+
+		// unmanaged by someone else or by an earlier run
+		if !managed {
+			continue
+		}
+
+		managedDevices = append(managedDevices, device)
+	}
+
+	return managedDevices, nil
+}
+
+// ManagedInterfaces returns the interfaces that NetworkManager manages and
+// DisableNetworkManagerForInterfaces would set unmanaged. Nothing is
+// returned when NetworkManager is not running.
+func ManagedInterfaces(nm NetworkManagerIf, interfaces []string) ([]string, error) {
+	if _, err := nm.GetPropertyVersion(); err != nil {
+		return nil, nil
+	}
+
+	devices, err := managedDevices(nm, interfaces)
+	if err != nil {
+		return nil, err
+	}
+
+	managed := make([]string, 0, len(devices))
+	for _, device := range devices {
+		netif, _ := device.GetPropertyInterface()
+		managed = append(managed, netif)
+	}
+
+	return managed, nil
+}
+
+// DisableNetworkManagerForInterfaces sets the interfaces unmanaged in
+// NetworkManager. Interfaces that were managed are added to the record at
+// recordPath so that RestoreNetworkManager can hand them back to
+// NetworkManager, also after a restart. An empty recordPath disables the
+// recording.
+func DisableNetworkManagerForInterfaces(nm NetworkManagerIf, interfaces []string, recordPath string) error {
+	// Check if NetworkManager is accessible
+	_, err := nm.GetPropertyVersion()
+	if err != nil {
+		klog.Info("Couldn't read NetworkManager version. It's probably not running.")
+
+		return nil
+	}
+
+	unmanage, err := managedDevices(nm, interfaces)
+	if err != nil {
+		return err
+	}
+
+	recorded, err := loadRecord(recordPath)
+	if err != nil {
+		return err
+	}
+
+	for _, device := range unmanage {
+		netif, _ := device.GetPropertyInterface()
+
+		if !slices.Contains(recorded, netif) {
+			recorded = append(recorded, netif)
+		}
+	}
+
+	// record before changing the state so that a crash doesn't lose track
+	if err := saveRecord(recordPath, recorded); err != nil {
+		return err
+	}
+
+	for _, device := range unmanage {
+		if err := device.SetPropertyManaged(false); err != nil {
+			return err
+		}
+
+		netif, _ := device.GetPropertyInterface()
+		klog.Infof("Disabled NetworkManager for interface %s", netif)
+	}
+
+	return nil
+}
+
+// ManageInterfaces hands the interfaces back to NetworkManager. Nothing is
+// changed when NetworkManager is not running.
+func ManageInterfaces(nm NetworkManagerIf, interfaces []string) error {
 	// Check if NetworkManager is accessible
 	_, err := nm.GetPropertyVersion()
 	if err != nil {
@@ -97,14 +295,52 @@ func DisableNetworkManagerForInterfaces(nm NetworkManagerIf, interfaces []string
 		}
 
 		if slices.Contains(interfaces, netif) {
-			err = device.SetPropertyManaged(false)
+			err = device.SetPropertyManaged(true)
 			if err != nil {
 				return err
 			}
 
-			klog.Infof("Disabled NetworkManager for interface %s", netif)
+			klog.Infof("Restored NetworkManager for interface %s", netif)
 		}
 	}
 
 	return nil
 }
+
+// RestoreNetworkManager hands the interfaces recorded by
+// DisableNetworkManagerForInterfaces back to NetworkManager and removes them
+// from the record. Only the recorded interfaces that are listed are
+// restored, nil restores all of them. The record is kept when
+// NetworkManager is not running.
+func RestoreNetworkManager(nm NetworkManagerIf, recordPath string, interfaces []string) error {
+	recorded, err := loadRecord(recordPath)
+	if err != nil || len(recorded) == 0 {
+		return err
+	}
+
+	restore := recorded
+	if interfaces != nil {
+		restore = slices.DeleteFunc(slices.Clone(recorded), func(name string) bool {
+			return !slices.Contains(interfaces, name)
+		})
+	}
+
+	if len(restore) == 0 {
+		return nil
+	}
+
+	// keep the record for a later attempt
+	if _, err := nm.GetPropertyVersion(); err != nil {
+		klog.Info("Couldn't read NetworkManager version. It's probably not running.")
+
+		return nil
+	}
+
+	if err := ManageInterfaces(nm, restore); err != nil {
+		return err
+	}
+
+	return saveRecord(recordPath, slices.DeleteFunc(recorded, func(name string) bool {
+		return slices.Contains(restore, name)
+	}))
+}
@@ -19,12 +19,18 @@ package networkmanager
 import (
 	"errors"
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 )
 
 type MockNetworkManager struct {
 	mockVersionQuery  func() (string, error)
 	mockGetAllDevices func() ([]DeviceWrapperIf, error)
+	mockReload        func(uint32) error
+
+	connections []*MockConnection
+	activated   []string
 }
 
 func (m *MockNetworkManager) GetPropertyVersion() (string, error) {
@@ -33,15 +39,61 @@ func (m *MockNetworkManager) GetPropertyVersion() (string, error) {
 func (m *MockNetworkManager) GetAllDevices() ([]DeviceWrapperIf, error) {
 	return m.mockGetAllDevices()
 }
+func (m *MockNetworkManager) Reload(flags uint32) error {
+	return m.mockReload(flags)
+}
+func (m *MockNetworkManager) ListConnections() ([]ConnectionWrapperIf, error) {
+	connections := []ConnectionWrapperIf{}
+	for _, c := range m.connections {
+		if !c.deleted {
+			connections = append(connections, c)
+		}
+	}
+	return connections, nil
+}
+func (m *MockNetworkManager) AddConnection(settings ConnectionSettings) (ConnectionWrapperIf, error) {
+	c := &MockConnection{settings: settings}
+	m.connections = append(m.connections, c)
+	return c, nil
+}
+func (m *MockNetworkManager) ActivateConnection(connection ConnectionWrapperIf, device DeviceWrapperIf) error {
+	netif, _ := device.GetPropertyInterface()
+	m.activated = append(m.activated, netif)
+	return nil
+}
+
+type MockConnection struct {
+	settings ConnectionSettings
+	deleted  bool
+}
+
+func (c *MockConnection) GetSettings() (ConnectionSettings, error) {
+	return c.settings, nil
+}
+func (c *MockConnection) Update(settings ConnectionSettings) error {
+	c.settings = settings
+	return nil
+}
+func (c *MockConnection) Delete() error {
+	c.deleted = true
+	return nil
+}
 
 type MockDevice struct {
 	mockIface      func() (string, error)
+	mockManaged    func() (bool, error)
 	mockSetManaged func(bool) error
 }
 
 func (d *MockDevice) GetPropertyInterface() (string, error) {
 	return d.mockIface()
 }
+func (d *MockDevice) GetPropertyManaged() (bool, error) {
+	if d.mockManaged == nil {
+		return true, nil
+	}
+	return d.mockManaged()
+}
 func (d *MockDevice) SetPropertyManaged(manage bool) error {
 	return d.mockSetManaged(manage)
 }
@@ -55,7 +107,7 @@ func TestDisableNetworkManagerForInterfacesOnHost(t *testing.T) {
 		t.Fatalf("NewNetworkManager failed")
 	}
 
-	err = DisableNetworkManagerForInterfaces(nm, interfaces)
+	err = DisableNetworkManagerForInterfaces(nm, interfaces, "")
 	if err != nil {
 		t.Errorf("DisableNetworkManagerForInterfaces failed: %v", err)
 	}
@@ -90,12 +142,55 @@ func TestDisableNetworkManagerForInterfaces(t *testing.T) {
 		},
 	}
 
-	err := DisableNetworkManagerForInterfaces(nm, interfaces)
+	err := DisableNetworkManagerForInterfaces(nm, interfaces, "")
 	if err != nil {
 		t.Errorf("DisableNetworkManagerForInterfaces failed: %v", err)
 	}
 }
 
+func TestManagedInterfaces(t *testing.T) {
+	device := func(name string, managed bool) DeviceWrapperIf {
+		return &MockDevice{
+			mockIface: func() (string, error) {
+				return name, nil
+			},
+			mockManaged: func() (bool, error) {
+				return managed, nil
+			},
+			mockSetManaged: func(manage bool) error {
+				t.Errorf("managed state of '%s' changed", name)
+				return nil
+			},
+		}
+	}
+
+	nm := &MockNetworkManager{
+		mockVersionQuery: func() (string, error) {
+			return "1.0.0", nil
+		},
+		mockGetAllDevices: func() ([]DeviceWrapperIf, error) {
+			return []DeviceWrapperIf{
+				device("ethXYZ", true),
+				device("ethZYX", false),
+				device("eno1", true),
+			}, nil
+		},
+	}
+
+	managed, err := ManagedInterfaces(nm, []string{"ethXYZ", "ethZYX"})
+	if err != nil || len(managed) != 1 || managed[0] != "ethXYZ" {
+		t.Errorf("unexpected managed interfaces %v: %v", managed, err)
+	}
+
+	nm.mockVersionQuery = func() (string, error) {
+		return "", errors.New("not running")
+	}
+
+	if managed, err := ManagedInterfaces(nm, []string{"ethXYZ"}); err != nil || len(managed) != 0 {
+		t.Errorf("unexpected managed interfaces without NetworkManager %v: %v", managed, err)
+	}
+}
+
 type TestCase struct {
 	name          string
 	ifaces        []string
@@ -167,9 +262,145 @@ func TestDisableNetworkManagerForInterfacesError(t *testing.T) {
 			},
 		}
 
-		err := DisableNetworkManagerForInterfaces(nm, interfaces)
+		err := DisableNetworkManagerForInterfaces(nm, interfaces, "")
 		if !errors.Is(err, tc.expectedErr) {
 			t.Errorf("%s should have failed: %v", tc.name, err)
 		}
 	}
 }
+
+func mockDevices(managed map[string]bool) []DeviceWrapperIf {
+	devices := []DeviceWrapperIf{}
+	for name := range managed {
+		devices = append(devices, &MockDevice{
+			mockIface: func() (string, error) {
+				return name, nil
+			},
+			mockManaged: func() (bool, error) {
+				return managed[name], nil
+			},
+			mockSetManaged: func(manage bool) error {
+				managed[name] = manage
+				return nil
+			},
+		})
+	}
+
+	return devices
+}
+
+func TestRestoreNetworkManager(t *testing.T) {
+	// ethZYX is unmanaged by the host configuration
+	managed := map[string]bool{"ethXYZ": true, "ethZYX": false, "eno1": true}
+	devices := mockDevices(managed)
+
+	nm := &MockNetworkManager{
+		mockVersionQuery: func() (string, error) {
+			return "1.0.0", nil
+		},
+		mockGetAllDevices: func() ([]DeviceWrapperIf, error) {
+			return devices, nil
+		},
+	}
+
+	recordPath := filepath.Join(t.TempDir(), "nm", "record.json")
+
+	if err := DisableNetworkManagerForInterfaces(nm, []string{"ethXYZ", "ethZYX"}, recordPath); err != nil {
+		t.Fatalf("DisableNetworkManagerForInterfaces failed: %v", err)
+	}
+
+	if managed["ethXYZ"] || managed["ethZYX"] || !managed["eno1"] {
+		t.Errorf("unexpected managed state after disabling: %v", managed)
+	}
+
+	// a restarted daemon finds the interfaces unmanaged
+	if err := DisableNetworkManagerForInterfaces(nm, []string{"ethXYZ", "ethZYX"}, recordPath); err != nil {
+		t.Fatalf("DisableNetworkManagerForInterfaces failed: %v", err)
+	}
+
+	recorded, err := loadRecord(recordPath)
+	if err != nil || !slices.Equal(recorded, []string{"ethXYZ"}) {
+		t.Errorf("unexpected record %v: %v", recorded, err)
+	}
+
+	if err := RestoreNetworkManager(nm, recordPath, nil); err != nil {
+		t.Fatalf("RestoreNetworkManager failed: %v", err)
+	}
+
+	if !managed["ethXYZ"] || managed["ethZYX"] || !managed["eno1"] {
+		t.Errorf("unexpected managed state after restoring: %v", managed)
+	}
+
+	if _, err := os.Stat(recordPath); !os.IsNotExist(err) {
+		t.Errorf("record was not removed: %v", err)
+	}
+
+	// nothing left to restore
+	if err := RestoreNetworkManager(nm, recordPath, nil); err != nil {
+		t.Errorf("RestoreNetworkManager failed without a record: %v", err)
+	}
+}
+
+func TestRestoreNetworkManagerInterfaces(t *testing.T) {
+	managed := map[string]bool{"ethXYZ": false, "ethZYX": false}
+	devices := mockDevices(managed)
+
+	nm := &MockNetworkManager{
+		mockVersionQuery: func() (string, error) {
+			return "1.0.0", nil
+		},
+		mockGetAllDevices: func() ([]DeviceWrapperIf, error) {
+			return devices, nil
+		},
+	}
+
+	recordPath := filepath.Join(t.TempDir(), "record.json")
+
+	if err := saveRecord(recordPath, []string{"ethXYZ", "ethZYX"}); err != nil {
+		t.Fatalf("cannot save record: %v", err)
+	}
+
+	// eno1 isn't recorded, NetworkManager didn't manage it
+	if err := RestoreNetworkManager(nm, recordPath, []string{"ethXYZ", "eno1"}); err != nil {
+		t.Fatalf("RestoreNetworkManager failed: %v", err)
+	}
+
+	if !managed["ethXYZ"] || managed["ethZYX"] {
+		t.Errorf("unexpected managed state after restoring: %v", managed)
+	}
+
+	if recorded, _ := loadRecord(recordPath); !slices.Equal(recorded, []string{"ethZYX"}) {
+		t.Errorf("unexpected record after restoring: %v", recorded)
+	}
+}
+
+func TestRestoreNetworkManagerNotRunning(t *testing.T) {
+	recordPath := filepath.Join(t.TempDir(), "record.json")
+
+	if err := saveRecord(recordPath, []string{"ethXYZ"}); err != nil {
+		t.Fatalf("cannot save record: %v", err)
+	}
+
+	nm := &MockNetworkManager{
+		mockVersionQuery: func() (string, error) {
+			return "", os.ErrInvalid
+		},
+	}
+
+	if err := RestoreNetworkManager(nm, recordPath, nil); err != nil {
+		t.Errorf("RestoreNetworkManager failed: %v", err)
+	}
+
+	// kept for when NetworkManager is running again
+	if recorded, _ := loadRecord(recordPath); !slices.Equal(recorded, []string{"ethXYZ"}) {
+		t.Errorf("record was not kept: %v", recorded)
+	}
+
+	if err := os.WriteFile(recordPath, []byte("{"), 0644); err != nil {
+		t.Fatalf("cannot write record: %v", err)
+	}
+
+	if err := RestoreNetworkManager(nm, recordPath, nil); err == nil {
+		t.Error("RestoreNetworkManager should have failed with a corrupted record")
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package networkmanager
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/google/uuid"
+	"k8s.io/klog/v2"
+)
+
+const (
+	profilePrefix = "intel-network-operator-"
+)
+
+// InterfaceProfile describes the L3 configuration of an interface that is
+// applied with a NetworkManager connection profile.
+type InterfaceProfile struct {
+	Interface string
+	// Address and prefix length of the point-to-point network
+	Address *net.IPNet
+	// Gateway of the routed networks, the LLDP peer
+	Gateway net.IP
+	// Routes to the routed networks via the gateway
+	Routes []*net.IPNet
+	// MTU of the interface, 0 to keep the current one
+	MTU int
+}
+
+// ProfileName returns the name of the connection profile of the interface.
+func ProfileName(ifname string) string {
+	return profilePrefix + ifname
+}
+
+// profileUUID returns a stable UUID for the connection profile so that the
+// profile of an interface is recognized after restarts.
+func profileUUID(ifname string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(ProfileName(ifname))).String()
+}
+
+func profileSettings(p InterfaceProfile) ConnectionSettings {
+	prefixLen, _ := p.Address.Mask.Size()
+
+	ipSetting, otherSetting := "ipv4", "ipv6"
+	if p.Address.IP.To4() == nil {
+		ipSetting, otherSetting = "ipv6", "ipv4"
+	}
+
+	routes := make([]map[string]interface{}, 0, len(p.Routes))
+	for _, dst := range p.Routes {
+		dstPrefixLen, _ := dst.Mask.Size()
+
+		routes = append(routes, map[string]interface{}{
+			"dest":     dst.IP.String(),
+			"prefix":   uint32(dstPrefixLen),
+			"next-hop": p.Gateway.String(),
+		})
+	}
+
+	settings := ConnectionSettings{
+		"connection": {
+			"id":             ProfileName(p.Interface),
+			"uuid":           profileUUID(p.Interface),
+			"type":           "802-3-ethernet",
+			"interface-name": p.Interface,
+			"autoconnect":    true,
+		},
+		"802-3-ethernet": {},
+		ipSetting: {
+			"method": "manual",
+			"address-data": []map[string]interface{}{
+				{"address": p.Address.IP.String(), "prefix": uint32(prefixLen)},
+			},
+			"route-data": routes,
+		},
+		otherSetting: {
+			"method": "disabled",
+		},
+	}
+
+	if p.MTU > 0 {
+		settings["802-3-ethernet"]["mtu"] = uint32(p.MTU)
+	}
+
+	return settings
+}
+
+func checkNetworkManager(nm NetworkManagerIf) error {
+	if _, err := nm.GetPropertyVersion(); err != nil {
+		return fmt.Errorf("NetworkManager is not available: %v", err)
+	}
+
+	return nil
+}
+
+// findProfile returns the connection profile of the interface or nil if
+// there's none.
+func findProfile(nm NetworkManagerIf, ifname string) (ConnectionWrapperIf, error) {
+	connections, err := nm.ListConnections()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, connection := range connections {
+		settings, err := connection.GetSettings()
+		if err != nil {
+			// e.g. connections of other users
+			continue
+		}
+
+		if id, ok := settings["connection"]["id"].(string); ok && id == ProfileName(ifname) {
+			return connection, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func findDevice(nm NetworkManagerIf, ifname string) (DeviceWrapperIf, error) {
+	devices, err := nm.GetAllDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, device := range devices {
+		netif, err := device.GetPropertyInterface()
+		if err != nil {
+			return nil, err
+		}
+
+		if netif == ifname {
+			return device, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no NetworkManager device for interface %s", ifname)
+}
+
+// ApplyProfile creates or updates the connection profile of the interface
+// and activates it on the interface.
+func ApplyProfile(nm NetworkManagerIf, p InterfaceProfile) error {
+	if err := checkNetworkManager(nm); err != nil {
+		return err
+	}
+
+	device, err := findDevice(nm, p.Interface)
+	if err != nil {
+		return err
+	}
+
+	connection, err := findProfile(nm, p.Interface)
+	if err != nil {
+		return err
+	}
+
+	settings := profileSettings(p)
+
+	if connection == nil {
+		if connection, err = nm.AddConnection(settings); err != nil {
+			return fmt.Errorf("cannot add connection profile %s: %v", ProfileName(p.Interface), err)
+		}
+	} else if err := connection.Update(settings); err != nil {
+		return fmt.Errorf("cannot update connection profile %s: %v", ProfileName(p.Interface), err)
+	}
+
+	if err := nm.ActivateConnection(connection, device); err != nil {
+		return fmt.Errorf("cannot activate connection profile %s: %v", ProfileName(p.Interface), err)
+	}
+
+	klog.Infof("Applied connection profile %s with address %s", ProfileName(p.Interface), p.Address)
+
+	return nil
+}
+
+// DeleteProfile deletes the connection profile of the interface, which
+// deactivates it and removes the address and routes.
+func DeleteProfile(nm NetworkManagerIf, ifname string) error {
+	if err := checkNetworkManager(nm); err != nil {
+		return err
+	}
+
+	connection, err := findProfile(nm, ifname)
+	if err != nil || connection == nil {
+		return err
+	}
+
+	if err := connection.Delete(); err != nil {
+		return fmt.Errorf("cannot delete connection profile %s: %v", ProfileName(ifname), err)
+	}
+
+	klog.Infof("Deleted connection profile %s", ProfileName(ifname))
+
+	return nil
+}
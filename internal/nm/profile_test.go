@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package networkmanager
+
+import (
+	"net"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestProfileSettings(t *testing.T) {
+	_, routed, _ := net.ParseCIDR("10.210.0.0/16")
+
+	settings := profileSettings(InterfaceProfile{
+		Interface: "eth0",
+		Address:   &net.IPNet{IP: net.IPv4(10, 210, 8, 121), Mask: net.CIDRMask(30, 32)},
+		Gateway:   net.IPv4(10, 210, 8, 122),
+		Routes:    []*net.IPNet{routed},
+		MTU:       8000,
+	})
+
+	if settings["connection"]["id"] != "intel-network-operator-eth0" || settings["connection"]["interface-name"] != "eth0" {
+		t.Errorf("unexpected connection settings %v", settings["connection"])
+	}
+	if settings["connection"]["uuid"] != profileUUID("eth0") || profileUUID("eth0") == profileUUID("eth1") {
+		t.Errorf("connection UUID is not stable per interface: %v", settings["connection"]["uuid"])
+	}
+	if settings["802-3-ethernet"]["mtu"] != uint32(8000) {
+		t.Errorf("unexpected MTU %v", settings["802-3-ethernet"]["mtu"])
+	}
+
+	ipv4 := settings["ipv4"]
+	if ipv4["method"] != "manual" || settings["ipv6"]["method"] != "disabled" {
+		t.Errorf("unexpected methods %v, %v", ipv4["method"], settings["ipv6"]["method"])
+	}
+
+	addrs := ipv4["address-data"].([]map[string]interface{})
+	if len(addrs) != 1 || addrs[0]["address"] != "10.210.8.121" || addrs[0]["prefix"] != uint32(30) {
+		t.Errorf("unexpected addresses %v", addrs)
+	}
+
+	routes := ipv4["route-data"].([]map[string]interface{})
+	if len(routes) != 1 || routes[0]["dest"] != "10.210.0.0" || routes[0]["prefix"] != uint32(16) || routes[0]["next-hop"] != "10.210.8.122" {
+		t.Errorf("unexpected routes %v", routes)
+	}
+
+	settings = profileSettings(InterfaceProfile{
+		Interface: "eth0",
+		Address:   &net.IPNet{IP: net.ParseIP("fd00:10::1"), Mask: net.CIDRMask(127, 128)},
+		Gateway:   net.ParseIP("fd00:10::"),
+	})
+
+	if settings["ipv6"]["method"] != "manual" || settings["ipv4"]["method"] != "disabled" {
+		t.Errorf("unexpected IPv6 settings %v", settings)
+	}
+	if _, ok := settings["802-3-ethernet"]["mtu"]; ok {
+		t.Error("MTU set without one")
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	nm := &MockNetworkManager{
+		mockVersionQuery: func() (string, error) {
+			return "1.46.0", nil
+		},
+		mockGetAllDevices: func() ([]DeviceWrapperIf, error) {
+			return mockDevices(map[string]bool{"eth0": true, "eth1": true}), nil
+		},
+	}
+
+	// a connection of the host
+	nm.connections = []*MockConnection{{settings: ConnectionSettings{"connection": {"id": "Wired connection 1"}}}}
+
+	profile := InterfaceProfile{
+		Interface: "eth0",
+		Address:   &net.IPNet{IP: net.IPv4(10, 210, 8, 121), Mask: net.CIDRMask(30, 32)},
+		Gateway:   net.IPv4(10, 210, 8, 122),
+	}
+
+	if err := ApplyProfile(nm, profile); err != nil {
+		t.Fatalf("ApplyProfile failed: %v", err)
+	}
+
+	profile.Address.IP = net.IPv4(10, 210, 9, 121)
+
+	if err := ApplyProfile(nm, profile); err != nil {
+		t.Fatalf("ApplyProfile failed: %v", err)
+	}
+
+	if len(nm.connections) != 2 {
+		t.Fatalf("expected the profile to be updated, got %d connections", len(nm.connections))
+	}
+
+	addrs := nm.connections[1].settings["ipv4"]["address-data"].([]map[string]interface{})
+	if addrs[0]["address"] != "10.210.9.121" {
+		t.Errorf("profile was not updated: %v", addrs)
+	}
+
+	if !slices.Equal(nm.activated, []string{"eth0", "eth0"}) {
+		t.Errorf("unexpected activations %v", nm.activated)
+	}
+
+	profile.Interface = "eth2"
+	if err := ApplyProfile(nm, profile); err == nil {
+		t.Error("profile applied for an unknown device")
+	}
+
+	if err := DeleteProfile(nm, "eth0"); err != nil || !nm.connections[1].deleted {
+		t.Errorf("profile was not deleted: %v", err)
+	}
+	if nm.connections[0].deleted {
+		t.Error("connection of the host was deleted")
+	}
+
+	// nothing to delete
+	if err := DeleteProfile(nm, "eth0"); err != nil {
+		t.Errorf("DeleteProfile failed: %v", err)
+	}
+
+	nm.mockVersionQuery = func() (string, error) {
+		return "", os.ErrInvalid
+	}
+
+	if err := ApplyProfile(nm, profile); err == nil {
+		t.Error("profile applied without NetworkManager")
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package networkmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultRecordPath is the host path of the record of the interfaces
+// unmanaged in NetworkManager.
+const DefaultRecordPath = "/var/lib/intel-network-operator/networkmanager-unmanaged.json"
+
+// unmanagedRecord lists the interfaces that were managed by NetworkManager
+// before they were set unmanaged.
+type unmanagedRecord struct {
+	Interfaces []string `json:"interfaces"`
+}
+
+func loadRecord(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read NetworkManager record: %v", err)
+	}
+
+	var record unmanagedRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("cannot parse NetworkManager record %s: %v", path, err)
+	}
+
+	return record.Interfaces, nil
+}
+
+// saveRecord writes the record, or removes it when there are no interfaces.
+func saveRecord(path string, interfaces []string) error {
+	if path == "" {
+		return nil
+	}
+
+	if len(interfaces) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("cannot remove NetworkManager record: %v", err)
+		}
+
+		return nil
+	}
+
+	sort.Strings(interfaces)
+
+	data, err := json.Marshal(unmanagedRecord{Interfaces: interfaces})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("cannot create NetworkManager record directory: %v", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("cannot write NetworkManager record: %v", err)
+	}
+
+	return os.Rename(tmpPath, path)
+}
@@ -27,6 +27,7 @@ package lldp
 import (
 	"context"
 	"fmt"
+	"net"
 	"reflect"
 	"time"
 
@@ -49,13 +50,32 @@ type Client struct {
 	ctx           context.Context
 }
 
+// OrgTLV holds an organizationally specific TLV of a lldp frame.
+type OrgTLV struct {
+	OUI     uint32
+	SubType uint8
+	Info    []byte
+}
+
 // DiscoveryResult holds optional TLV SysName and SysDescription fields of a real lldp frame.
 type DiscoveryResult struct {
-	InterfaceName   string
-	SysName         string
-	SysDescription  string
-	PortDescription string
-	PeerMAC         []byte
+	InterfaceName    string
+	SysName          string
+	SysDescription   string
+	PortDescription  string
+	PeerMAC          []byte
+	OrgTLVs          []OrgTLV
+	ChassisID        []byte
+	ChassisIDSubtype layers.LLDPChassisIDSubType
+	PortID           []byte
+	PortIDSubtype    layers.LLDPPortIDSubType
+	// Management addresses of all Management Address TLVs in the frame.
+	MgmtAddresses   []net.IP
+	SysCapabilities layers.LLDPSysCapabilities
+	// Port VLAN ID from the IEEE 802.1 TLV, 0 if not present.
+	VLAN uint16
+	// Maximum frame size from the IEEE 802.3 TLV, 0 if not present.
+	MTU uint16
 }
 
 // NewClient creates a new lldp client.
@@ -68,9 +88,19 @@ func NewClient(ctx context.Context, ifacename string, hwAddr []byte) *Client {
 }
 
 // Start searches on the configured interface for lldp packages and
-// pushes the optional TLV SysName and SysDescription fields of each
+// pushes the optional TLV SysName and SysDescription fields of the first
 // found lldp package into the given channel.
 func (l *Client) Start(resultChan chan<- DiscoveryResult) error {
+	return l.receive(resultChan, true)
+}
+
+// Monitor keeps pushing the information of every lldp package found on the
+// configured interface into the given channel until the context is done.
+func (l *Client) Monitor(resultChan chan<- DiscoveryResult) error {
+	return l.receive(resultChan, false)
+}
+
+func (l *Client) receive(resultChan chan<- DiscoveryResult, once bool) error {
 	defer l.Close()
 
 	var packetSource *gopacket.PacketSource
@@ -101,47 +131,20 @@ func (l *Client) Start(resultChan chan<- DiscoveryResult) error {
 				continue
 			}
 
-			if packet.LinkLayer().LayerType() != layers.LayerTypeEthernet {
+			dr, ok := l.discoveryResult(packet)
+			if !ok {
 				continue
 			}
 
-			// Ignore LLDP packets sent by us
-			if reflect.DeepEqual(packet.LinkLayer().LinkFlow().Src().Raw(), l.InterfaceMac) {
-				continue
+			select {
+			case resultChan <- dr:
+			case <-l.ctx.Done():
+				return nil
 			}
 
-			dr := DiscoveryResult{InterfaceName: l.InterfaceName}
-			for _, layer := range packet.Layers() {
-				if layer.LayerType() == layers.LayerTypeLinkLayerDiscovery {
-					info, ok := layer.(*layers.LinkLayerDiscovery)
-					if !ok {
-						continue
-					}
-
-					if info.ChassisID.Subtype == layers.LLDPChassisIDSubTypeMACAddr {
-						dr.PeerMAC = info.ChassisID.ID
-					}
-
-					if info.PortID.Subtype == layers.LLDPPortIDSubtypeMACAddr {
-						dr.PeerMAC = info.PortID.ID
-					}
-
-					continue
-				}
-
-				if layer.LayerType() == layers.LayerTypeLinkLayerDiscoveryInfo {
-					info, ok := layer.(*layers.LinkLayerDiscoveryInfo)
-					if !ok {
-						continue
-					}
-					dr.SysName = info.SysName
-					dr.SysDescription = info.SysDescription
-					dr.PortDescription = info.PortDescription
-				}
-
+			if once {
+				return nil
 			}
-			resultChan <- dr
-			return nil
 
 		case <-l.ctx.Done():
 			return nil
@@ -149,6 +152,110 @@ func (l *Client) Start(resultChan chan<- DiscoveryResult) error {
 	}
 }
 
+// discoveryResult returns the information of the lldp package, or false
+// for packages that aren't ethernet or were sent by us.
+func (l *Client) discoveryResult(packet gopacket.Packet) (DiscoveryResult, bool) {
+	if packet.LinkLayer() == nil || packet.LinkLayer().LayerType() != layers.LayerTypeEthernet {
+		return DiscoveryResult{}, false
+	}
+
+	// Ignore LLDP packets sent by us
+	if reflect.DeepEqual(packet.LinkLayer().LinkFlow().Src().Raw(), l.InterfaceMac) {
+		return DiscoveryResult{}, false
+	}
+
+	dr := DiscoveryResult{InterfaceName: l.InterfaceName}
+	for _, layer := range packet.Layers() {
+		if layer.LayerType() == layers.LayerTypeLinkLayerDiscovery {
+			info, ok := layer.(*layers.LinkLayerDiscovery)
+			if !ok {
+				continue
+			}
+
+			if info.ChassisID.Subtype == layers.LLDPChassisIDSubTypeMACAddr {
+				dr.PeerMAC = info.ChassisID.ID
+			}
+
+			if info.PortID.Subtype == layers.LLDPPortIDSubtypeMACAddr {
+				dr.PeerMAC = info.PortID.ID
+			}
+
+			dr.ChassisID = info.ChassisID.ID
+			dr.ChassisIDSubtype = info.ChassisID.Subtype
+			dr.PortID = info.PortID.ID
+			dr.PortIDSubtype = info.PortID.Subtype
+
+			// LinkLayerDiscoveryInfo only keeps the last management address
+			dr.MgmtAddresses = mgmtAddresses(info.Values)
+
+			continue
+		}
+
+		if layer.LayerType() == layers.LayerTypeLinkLayerDiscoveryInfo {
+			info, ok := layer.(*layers.LinkLayerDiscoveryInfo)
+			if !ok {
+				continue
+			}
+			dr.SysName = info.SysName
+			dr.SysDescription = info.SysDescription
+			dr.PortDescription = info.PortDescription
+
+			for _, tlv := range info.OrgTLVs {
+				dr.OrgTLVs = append(dr.OrgTLVs, OrgTLV{
+					OUI:     uint32(tlv.OUI),
+					SubType: tlv.SubType,
+					Info:    tlv.Info,
+				})
+			}
+
+			dr.SysCapabilities = info.SysCapabilities
+
+			if info8021, err := info.Decode8021(); err == nil {
+				dr.VLAN = info8021.PVID
+			}
+
+			if info8023, err := info.Decode8023(); err == nil {
+				dr.MTU = info8023.MTU
+			}
+		}
+	}
+
+	return dr, true
+}
+
+// mgmtAddresses returns the IPv4 and IPv6 addresses of the Management
+// Address TLVs.
+func mgmtAddresses(values []layers.LinkLayerDiscoveryValue) []net.IP {
+	addrs := []net.IP{}
+
+	for _, v := range values {
+		if v.Type != layers.LLDPTLVMgmtAddress || len(v.Value) < 2 {
+			continue
+		}
+
+		// address string length covers the subtype and the address
+		addrLen := int(v.Value[0])
+		if addrLen < 2 || len(v.Value) < addrLen+1 {
+			continue
+		}
+
+		addr := v.Value[2 : addrLen+1]
+
+		switch layers.IANAAddressFamily(v.Value[1]) {
+		case layers.IANAAddressFamilyIPV4:
+			if len(addr) == net.IPv4len {
+				addrs = append(addrs, net.IP(addr))
+			}
+		case layers.IANAAddressFamilyIPV6:
+			if len(addr) == net.IPv6len {
+				addrs = append(addrs, net.IP(addr))
+			}
+		}
+	}
+
+	return addrs
+}
+
 // Close the LLDP client
 func (l *Client) Close() {
 	if l.handle != nil {
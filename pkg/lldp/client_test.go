@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lldp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestDiscoveryResult(t *testing.T) {
+	peerAddr := net.HardwareAddr{0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+	localAddr := net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+
+	frame, err := NewFrame(peerAddr, Advertisement{
+		SysName:         "switch-1",
+		PortDescription: "Ethernet1 10.210.8.122/30",
+		MgmtAddresses:   []net.IP{net.IPv4(10, 210, 8, 122)},
+	}, 120*time.Second)
+	if err != nil {
+		t.Fatalf("creating frame failed: %v", err)
+	}
+
+	packet := gopacket.NewPacket(frame, layers.LayerTypeEthernet, gopacket.Default)
+
+	client := NewClient(context.Background(), "eth0", localAddr)
+
+	dr, ok := client.discoveryResult(packet)
+	if !ok {
+		t.Fatal("no result for the peer's frame")
+	}
+
+	if dr.InterfaceName != "eth0" || dr.SysName != "switch-1" ||
+		dr.PortDescription != "Ethernet1 10.210.8.122/30" || !bytes.Equal(dr.PeerMAC, peerAddr) ||
+		len(dr.MgmtAddresses) != 1 || !dr.MgmtAddresses[0].Equal(net.IPv4(10, 210, 8, 122)) {
+		t.Errorf("unexpected result %+v", dr)
+	}
+
+	client = NewClient(context.Background(), "eth0", peerAddr)
+
+	if _, ok := client.discoveryResult(packet); ok {
+		t.Error("result for our own frame")
+	}
+}
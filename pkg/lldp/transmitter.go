@@ -0,0 +1,245 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lldp
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcap"
+	"k8s.io/klog/v2"
+)
+
+// nearest bridge group address, not forwarded by 802.1D bridges
+var lldpMulticastAddr = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x0e}
+
+// Advertisement holds the information advertised in transmitted lldp frames.
+type Advertisement struct {
+	// Chassis ID shared by all ports of the node, e.g. the node name.
+	ChassisID       string
+	SysName         string
+	SysDescription  string
+	PortDescription string
+	MgmtAddresses   []net.IP
+	// Interface index reported with the management addresses.
+	InterfaceIndex int
+}
+
+// Transmitter sends lldp frames periodically.
+type Transmitter struct {
+	InterfaceName string
+	InterfaceMac  []byte
+	Interval      time.Duration
+	TTL           time.Duration
+	handle        *pcap.Handle
+	ctx           context.Context
+	mutex         sync.Mutex
+	advertisement Advertisement
+}
+
+// NewTransmitter creates a new lldp transmitter.
+func NewTransmitter(ctx context.Context, ifacename string, hwAddr []byte, interval, ttl time.Duration) *Transmitter {
+	return &Transmitter{
+		InterfaceName: ifacename,
+		InterfaceMac:  hwAddr,
+		Interval:      interval,
+		TTL:           ttl,
+		ctx:           ctx,
+	}
+}
+
+// SetAdvertisement changes the information advertised in the next frames.
+func (t *Transmitter) SetAdvertisement(adv Advertisement) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.advertisement = adv
+}
+
+func (t *Transmitter) getAdvertisement() Advertisement {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.advertisement
+}
+
+// Start transmits the advertisement on the configured interface every
+// interval until the context is done. Failed sends, e.g. while the link is
+// down, are logged and retried on the next tick with a newly opened handle.
+// A shutdown frame with a zero TTL is sent on exit so that the peer can
+// remove the information right away.
+func (t *Transmitter) Start() error {
+	defer t.Close()
+
+	return t.transmit(t.send)
+}
+
+// transmit calls send every interval until the context is done.
+func (t *Transmitter) transmit(send func(ttl time.Duration) error) error {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	failing := false
+
+	for {
+		if err := send(t.TTL); err != nil {
+			if !failing {
+				klog.Warningf("LLDP transmit failed, retrying every %v: %v", t.Interval, err)
+			}
+
+			// the interface may have been recreated, e.g. after a driver
+			// rebind, and the old handle would keep failing
+			t.Close()
+
+			failing = true
+		} else if failing {
+			klog.Infof("LLDP transmit on interface:%s recovered", t.InterfaceName)
+
+			failing = false
+		}
+
+		select {
+		case <-ticker.C:
+		case <-t.ctx.Done():
+			return send(0)
+		}
+	}
+}
+
+func (t *Transmitter) send(ttl time.Duration) error {
+	frame, err := NewFrame(t.InterfaceMac, t.getAdvertisement(), ttl)
+	if err != nil {
+		return err
+	}
+
+	if t.handle == nil {
+		t.handle, err = pcap.OpenLive(t.InterfaceName, 65536, false, pcap.BlockForever)
+		if err != nil {
+			return fmt.Errorf("unable to open interface:%s: %w", t.InterfaceName, err)
+		}
+	}
+
+	if err := t.handle.WritePacketData(frame); err != nil {
+		return fmt.Errorf("unable to send lldp frame on interface:%s: %w", t.InterfaceName, err)
+	}
+
+	return nil
+}
+
+// Close the LLDP transmitter
+func (t *Transmitter) Close() {
+	if t.handle != nil {
+		t.handle.Close()
+		t.handle = nil
+	}
+}
+
+// NewFrame creates an ethernet frame with an LLDPDU carrying the advertisement.
+// The port is identified by its MAC address.
+func NewFrame(hwAddr []byte, adv Advertisement, ttl time.Duration) ([]byte, error) {
+	ttlSeconds := ttl / time.Second
+	if ttlSeconds > math.MaxUint16 {
+		ttlSeconds = math.MaxUint16
+	}
+
+	lldpdu := &layers.LinkLayerDiscovery{
+		ChassisID: layers.LLDPChassisID{
+			Subtype: layers.LLDPChassisIDSubTypeLocal,
+			ID:      []byte(adv.ChassisID),
+		},
+		PortID: layers.LLDPPortID{
+			Subtype: layers.LLDPPortIDSubtypeMACAddr,
+			ID:      hwAddr,
+		},
+		TTL: uint16(ttlSeconds),
+	}
+
+	if adv.ChassisID == "" {
+		lldpdu.ChassisID = layers.LLDPChassisID{
+			Subtype: layers.LLDPChassisIDSubTypeMACAddr,
+			ID:      hwAddr,
+		}
+	}
+
+	lldpdu.Values = appendStringTLV(lldpdu.Values, layers.LLDPTLVPortDescription, adv.PortDescription)
+	lldpdu.Values = appendStringTLV(lldpdu.Values, layers.LLDPTLVSysName, adv.SysName)
+	lldpdu.Values = appendStringTLV(lldpdu.Values, layers.LLDPTLVSysDescription, adv.SysDescription)
+
+	for _, addr := range adv.MgmtAddresses {
+		lldpdu.Values = append(lldpdu.Values, mgmtAddressTLV(addr, adv.InterfaceIndex))
+	}
+
+	eth := &layers.Ethernet{
+		SrcMAC:       hwAddr,
+		DstMAC:       lldpMulticastAddr,
+		EthernetType: layers.EthernetTypeLinkLayerDiscovery,
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{}, eth, lldpdu); err != nil {
+		return nil, fmt.Errorf("unable to serialize lldp frame: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// appendStringTLV appends a string TLV, strings are limited to 255 octets.
+func appendStringTLV(values []layers.LinkLayerDiscoveryValue, tlvType layers.LLDPTLVType, value string) []layers.LinkLayerDiscoveryValue {
+	if value == "" {
+		return values
+	}
+
+	if len(value) > 255 {
+		value = value[:255]
+	}
+
+	return append(values, layers.LinkLayerDiscoveryValue{
+		Type:   tlvType,
+		Length: uint16(len(value)),
+		Value:  []byte(value),
+	})
+}
+
+func mgmtAddressTLV(addr net.IP, ifIndex int) layers.LinkLayerDiscoveryValue {
+	subtype := layers.IANAAddressFamilyIPV6
+	if addr4 := addr.To4(); addr4 != nil {
+		subtype = layers.IANAAddressFamilyIPV4
+		addr = addr4
+	}
+
+	// address string length, subtype, address, interface numbering
+	// subtype, interface number and a zero length OID
+	value := make([]byte, 0, len(addr)+8)
+	value = append(value, byte(len(addr)+1), byte(subtype))
+	value = append(value, addr...)
+	value = append(value, byte(layers.LLDPInterfaceSubtypeifIndex))
+	value = binary.BigEndian.AppendUint32(value, uint32(ifIndex))
+	value = append(value, 0)
+
+	return layers.LinkLayerDiscoveryValue{
+		Type:   layers.LLDPTLVMgmtAddress,
+		Length: uint16(len(value)),
+		Value:  value,
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2025 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lldp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestNewFrame(t *testing.T) {
+	hwAddr := net.HardwareAddr{0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+	adv := Advertisement{
+		ChassisID:       "node-1",
+		SysName:         "host-1",
+		SysDescription:  "Kubernetes node node-1",
+		PortDescription: "eth0",
+		MgmtAddresses:   []net.IP{net.IPv4(10, 210, 8, 121), net.ParseIP("fd00::1")},
+		InterfaceIndex:  5,
+	}
+
+	frame, err := NewFrame(hwAddr, adv, 120*time.Second)
+	if err != nil {
+		t.Fatalf("creating frame failed: %v", err)
+	}
+
+	packet := gopacket.NewPacket(frame, layers.LayerTypeEthernet, gopacket.Default)
+
+	eth, ok := packet.Layer(layers.LayerTypeEthernet).(*layers.Ethernet)
+	if !ok || !bytes.Equal(eth.DstMAC, lldpMulticastAddr) || !bytes.Equal(eth.SrcMAC, hwAddr) {
+		t.Fatalf("unexpected ethernet layer %+v", eth)
+	}
+
+	lldpdu, ok := packet.Layer(layers.LayerTypeLinkLayerDiscovery).(*layers.LinkLayerDiscovery)
+	if !ok {
+		t.Fatal("no lldp layer in frame")
+	}
+
+	if string(lldpdu.ChassisID.ID) != "node-1" || lldpdu.ChassisID.Subtype != layers.LLDPChassisIDSubTypeLocal {
+		t.Errorf("unexpected chassis id %+v", lldpdu.ChassisID)
+	}
+	if !bytes.Equal(lldpdu.PortID.ID, hwAddr) || lldpdu.PortID.Subtype != layers.LLDPPortIDSubtypeMACAddr {
+		t.Errorf("unexpected port id %+v", lldpdu.PortID)
+	}
+	if lldpdu.TTL != 120 {
+		t.Errorf("expected TTL 120, got %d", lldpdu.TTL)
+	}
+
+	info, ok := packet.Layer(layers.LayerTypeLinkLayerDiscoveryInfo).(*layers.LinkLayerDiscoveryInfo)
+	if !ok {
+		t.Fatal("no lldp info layer in frame")
+	}
+
+	if info.SysName != adv.SysName || info.SysDescription != adv.SysDescription || info.PortDescription != adv.PortDescription {
+		t.Errorf("unexpected lldp info %+v", info)
+	}
+
+	addrs := mgmtAddresses(lldpdu.Values)
+	if len(addrs) != 2 || !addrs[0].Equal(adv.MgmtAddresses[0]) || !addrs[1].Equal(adv.MgmtAddresses[1]) {
+		t.Errorf("unexpected management addresses %v", addrs)
+	}
+}
+
+func TestNewFrameShutdown(t *testing.T) {
+	hwAddr := net.HardwareAddr{0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+
+	frame, err := NewFrame(hwAddr, Advertisement{}, 0)
+	if err != nil {
+		t.Fatalf("creating frame failed: %v", err)
+	}
+
+	packet := gopacket.NewPacket(frame, layers.LayerTypeEthernet, gopacket.Default)
+
+	lldpdu, ok := packet.Layer(layers.LayerTypeLinkLayerDiscovery).(*layers.LinkLayerDiscovery)
+	if !ok {
+		t.Fatal("no lldp layer in frame")
+	}
+
+	if lldpdu.TTL != 0 {
+		t.Errorf("expected TTL 0, got %d", lldpdu.TTL)
+	}
+	if !bytes.Equal(lldpdu.ChassisID.ID, hwAddr) || lldpdu.ChassisID.Subtype != layers.LLDPChassisIDSubTypeMACAddr {
+		t.Errorf("unexpected chassis id %+v", lldpdu.ChassisID)
+	}
+}
+
+func TestTransmitAfterSendError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	transmitter := NewTransmitter(ctx, "eth0", nil, time.Millisecond, 120*time.Second)
+
+	var ttls []time.Duration
+
+	send := func(ttl time.Duration) error {
+		ttls = append(ttls, ttl)
+
+		switch len(ttls) {
+		case 1, 2:
+			return errors.New("network is down")
+		case 4:
+			cancel()
+		}
+
+		return nil
+	}
+
+	if err := transmitter.transmit(send); err != nil {
+		t.Fatalf("transmit failed: %v", err)
+	}
+
+	// the sends go on after the errors, a shutdown frame is sent on exit
+	if len(ttls) < 5 || ttls[len(ttls)-1] != 0 {
+		t.Errorf("unexpected sends %v", ttls)
+	}
+}
@@ -70,9 +70,11 @@ func FuzzNetworkConfigurationGaudiSO(f *testing.F) {
 					"nodetype": nodeType,
 				},
 				GaudiScaleOut: api.GaudiScaleOutSpec{
-					Layer:      layer,
-					Image:      image,
-					PullPolicy: "Always",
+					LinkDiscoverySpec: api.LinkDiscoverySpec{
+						Layer:      layer,
+						Image:      image,
+						PullPolicy: "Always",
+					},
 				},
 				LogLevel: logLevel,
 			},